@@ -0,0 +1,26 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// v1 is the storage version and the hub that all other served versions
+// convert to and from in the conversion webhook.
+
+// Hub marks SecretProviderClass as a conversion hub.
+func (*SecretProviderClass) Hub() {}
+
+// Hub marks SecretProviderClassPodStatus as a conversion hub.
+func (*SecretProviderClassPodStatus) Hub() {}
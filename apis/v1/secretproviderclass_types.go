@@ -0,0 +1,265 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Provider enum for all the provider names
+type Provider string
+
+const (
+	// Azure provider for Azure Key Vault
+	Azure Provider = "Azure"
+	// Vault provider for Hashicorp Vault
+	Vault Provider = "Vault"
+)
+
+// SecretRetentionPolicy describes what happens to a synced K8s secret when its secret object
+// is removed from the SecretProviderClass
+// +kubebuilder:validation:Enum=Delete;Retain
+type SecretRetentionPolicy string
+
+const (
+	// SecretRetentionPolicyDelete deletes the synced K8s secret
+	SecretRetentionPolicyDelete SecretRetentionPolicy = "Delete"
+	// SecretRetentionPolicyRetain keeps the synced K8s secret, which is no longer managed by the driver
+	SecretRetentionPolicyRetain SecretRetentionPolicy = "Retain"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// DataTransformType is the type of transform applied to the object content
+// +kubebuilder:validation:Enum=Base64Decode;HexDecode;JSONPath;YAMLKey;PEMBlock;Trim
+type DataTransformType string
+
+const (
+	// DataTransformBase64Decode decodes the base64 encoded content
+	DataTransformBase64Decode DataTransformType = "Base64Decode"
+	// DataTransformHexDecode decodes the hex encoded content
+	DataTransformHexDecode DataTransformType = "HexDecode"
+	// DataTransformJSONPath extracts the value at the JSONPath expression from the JSON content
+	DataTransformJSONPath DataTransformType = "JSONPath"
+	// DataTransformYAMLKey extracts the value of the key from the YAML content
+	DataTransformYAMLKey DataTransformType = "YAMLKey"
+	// DataTransformPEMBlock selects a PEM block from the content
+	DataTransformPEMBlock DataTransformType = "PEMBlock"
+	// DataTransformTrim trims the leading and trailing whitespace from the content
+	DataTransformTrim DataTransformType = "Trim"
+)
+
+// DataTransform defines a transform applied to the object content
+type DataTransform struct {
+	// type of the transform
+	Type DataTransformType `json:"type"`
+	// jsonPath expression for the JSONPath transform, e.g. {.password}
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+	// key for the YAMLKey transform. Nested keys are separated by '.'
+	// +optional
+	Key string `json:"key,omitempty"`
+	// pemType of the block for the PEMBlock transform, e.g. CERTIFICATE. Blocks of
+	// any type are selected if empty.
+	// +optional
+	PEMType string `json:"pemType,omitempty"`
+	// index of the block for the PEMBlock transform among the blocks matching pemType
+	// +optional
+	Index int `json:"index,omitempty"`
+}
+
+// FileTransform defines the transforms applied to a file written to the mount
+type FileTransform struct {
+	// path of the file in the mount, i.e. the object name or object alias
+	Path string `json:"path,omitempty"`
+	// transforms applied to the file content in order
+	Transforms []DataTransform `json:"transforms,omitempty"`
+}
+
+// SecretTemplate defines a Go text/template rendered with the content of the mounted objects
+type SecretTemplate struct {
+	// name of the template, which is referenced by the secret object data
+	Name string `json:"name,omitempty"`
+	// template rendered with the mounted objects, e.g. {{ .username }}
+	Template string `json:"template,omitempty"`
+	// path of the file in the mount the template is rendered to. The template
+	// isn't written to the mount if empty.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// SecretObjectData defines the desired state of synced K8s secret object data
+type SecretObjectData struct {
+	// name of the object to sync
+	ObjectName string `json:"objectName,omitempty"`
+	// name of the template to sync instead of an object
+	// +optional
+	Template string `json:"template,omitempty"`
+	// data field to populate
+	Key string `json:"key,omitempty"`
+	// transforms applied to the object content in order before it's synced
+	// +optional
+	Transforms []DataTransform `json:"transforms,omitempty"`
+}
+
+// SecretObject defines the desired state of synced K8s secret objects
+type SecretObject struct {
+	// name of the K8s secret object
+	SecretName string `json:"secretName,omitempty"`
+	// type of K8s secret object
+	Type string `json:"type,omitempty"`
+	// labels of K8s secret object
+	Labels map[string]string `json:"labels,omitempty"`
+	// annotations of k8s secret object
+	Annotations map[string]string   `json:"annotations,omitempty"`
+	Data        []*SecretObjectData `json:"data,omitempty"`
+	// retentionPolicy of the K8s secret object when the secret object is removed
+	// from the secret provider class. Defaults to Delete.
+	// +optional
+	RetentionPolicy SecretRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// ConfigMapObject defines the desired state of synced K8s configmap objects. The configmaps
+// are meant for non-sensitive objects, e.g. CA bundles and public certificates, that are
+// consumed without access to secrets.
+type ConfigMapObject struct {
+	// name of the K8s configmap object
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// labels of K8s configmap object
+	Labels map[string]string `json:"labels,omitempty"`
+	// annotations of K8s configmap object
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// data of the K8s configmap object. Content that isn't valid UTF-8 is synced
+	// to binaryData.
+	Data []*SecretObjectData `json:"data,omitempty"`
+	// retentionPolicy of the K8s configmap object when the configmap object is removed
+	// from the secret provider class. Defaults to Delete.
+	// +optional
+	RetentionPolicy SecretRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// ParametersFromReference references a configmap or secret in the namespace of the
+// secret provider class
+type ParametersFromReference struct {
+	// name of the configmap or secret
+	Name string `json:"name"`
+	// optional specifies whether the configmap or secret must exist
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ParametersFromSource defines a configmap or secret whose keys are added to the
+// provider parameters. Exactly one of configMapRef and secretRef must be set.
+type ParametersFromSource struct {
+	// configMapRef references the configmap whose data is added to the parameters
+	// +optional
+	ConfigMapRef *ParametersFromReference `json:"configMapRef,omitempty"`
+	// secretRef references the secret whose data is added to the parameters
+	// +optional
+	SecretRef *ParametersFromReference `json:"secretRef,omitempty"`
+}
+
+// SecretProviderClassSpec defines the desired state of SecretProviderClass
+type SecretProviderClassSpec struct {
+	// Configuration for provider name
+	Provider Provider `json:"provider,omitempty"`
+	// Configuration for specific provider
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// parametersFrom lists the configmaps and secrets whose keys are merged into the
+	// parameters. When a key exists in more than one source, the value from the last
+	// source takes precedence, and the parameters take precedence over all sources.
+	// +optional
+	ParametersFrom []*ParametersFromSource `json:"parametersFrom,omitempty"`
+	SecretObjects  []*SecretObject         `json:"secretObjects,omitempty"`
+	// configMapObjects are synced as K8s configmaps in the same way as secretObjects
+	// are synced as K8s secrets
+	// +optional
+	ConfigMapObjects []*ConfigMapObject `json:"configMapObjects,omitempty"`
+	// fileTransforms are applied to the files returned by the provider before
+	// they are written to the mount
+	// +optional
+	FileTransforms []*FileTransform `json:"fileTransforms,omitempty"`
+	// templates rendered with the mounted objects after the provider has written
+	// them to the mount. Templates are written to the mount and synced as secret data.
+	// +optional
+	Templates []*SecretTemplate `json:"templates,omitempty"`
+	// defaultMode is the mode of the files in the mount, unless the provider sets the
+	// mode of a file. Defaults to 0644.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	DefaultMode *int32 `json:"defaultMode,omitempty"`
+}
+
+// ByPodStatus defines the state of SecretProviderClass as seen by
+// an individual controller
+type ByPodStatus struct {
+	// id of the pod that wrote the status
+	ID string `json:"id,omitempty"`
+	// namespace of the pod that wrote the status
+	Namespace string `json:"namespace,omitempty"`
+	// name of the pod using the secret provider class
+	PodName string `json:"podName,omitempty"`
+	// name of the node the pod is running on
+	NodeName string `json:"nodeName,omitempty"`
+	// objects mounted in the pod
+	Objects []SecretProviderClassObject `json:"objects,omitempty"`
+	// ready is true if the objects are mounted in the pod and none of the
+	// secret provider class pod status conditions are false
+	Ready bool `json:"ready,omitempty"`
+}
+
+// SecretProviderClassStatus defines the observed state of SecretProviderClass
+type SecretProviderClassStatus struct {
+	// ByPod is the status of the pods using the secret provider class. Pods that are
+	// not ready are listed first and the list is limited to a maximum number of pods.
+	ByPod []*ByPodStatus `json:"byPod,omitempty"`
+	// TotalPods is the number of pods using the secret provider class
+	TotalPods int32 `json:"totalPods,omitempty"`
+	// ReadyPods is the number of pods using the secret provider class that are ready
+	ReadyPods int32 `json:"readyPods,omitempty"`
+	// FailedPods is the number of pods using the secret provider class that are not ready
+	FailedPods int32 `json:"failedPods,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.provider"
+// +kubebuilder:printcolumn:name="Pods",type="integer",JSONPath=".status.totalPods"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyPods"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedPods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+
+// SecretProviderClass is the Schema for the secretproviderclasses API
+type SecretProviderClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretProviderClassSpec   `json:"spec,omitempty"`
+	Status SecretProviderClassStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretProviderClassList contains a list of SecretProviderClass
+type SecretProviderClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretProviderClass `json:"items"`
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// InternalNodeLabel used for setting the node name spc pod status belongs to
+	InternalNodeLabel = "internal.secrets-store.csi.k8s.io/node-name"
+)
+
+const (
+	// ConditionTypeMounted indicates the secrets store objects have been mounted in the pod
+	ConditionTypeMounted = "Mounted"
+	// ConditionTypeRotated indicates the mounted contents were rotated in the last rotation attempt
+	ConditionTypeRotated = "Rotated"
+	// ConditionTypeSecretsSynced indicates the secret objects in the secret provider class
+	// have been synced as Kubernetes secrets
+	ConditionTypeSecretsSynced = "SecretsSynced"
+	// ConditionTypeProviderHealthy indicates the provider responded to the last request
+	ConditionTypeProviderHealthy = "ProviderHealthy"
+
+	// ConditionReasonSucceeded is the reason for conditions with status True.
+	// Conditions with status False use the error codes in pkg/errors as reason.
+	ConditionReasonSucceeded = "Succeeded"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// SecretProviderClassPodStatusStatus defines the observed state of SecretProviderClassPodStatus
+type SecretProviderClassPodStatusStatus struct {
+	PodName                 string `json:"podName,omitempty"`
+	SecretProviderClassName string `json:"secretProviderClassName,omitempty"`
+	// SecretProviderClassKind is the kind of the secret provider class, either SecretProviderClass
+	// or ClusterSecretProviderClass. An empty kind is a SecretProviderClass.
+	// +optional
+	SecretProviderClassKind string `json:"secretProviderClassKind,omitempty"`
+	// Provider is the provider the volume was mounted with. It's used to call the provider
+	// when the volume is unmounted, as the secret provider class may be deleted first.
+	// +optional
+	Provider   string                      `json:"provider,omitempty"`
+	Mounted    bool                        `json:"mounted,omitempty"`
+	TargetPath string                      `json:"targetPath,omitempty"`
+	Objects    []SecretProviderClassObject `json:"objects,omitempty"`
+	// Conditions represent the latest observations of the mount, rotation, secret sync
+	// and provider state for the pod
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSuccessfulRotationTime is the last time the mounted contents were successfully rotated.
+	// The rotation polls that don't change the rest of the status don't update it.
+	// +optional
+	LastSuccessfulRotationTime *metav1.Time `json:"lastSuccessfulRotationTime,omitempty"`
+	// LastAttemptTime is the last time a mount or rotation was attempted.
+	// The rotation polls that don't change the rest of the status don't update it.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+// SecretProviderClassObject defines the object fetched from external secrets store
+type SecretProviderClassObject struct {
+	ID      string `json:"id,omitempty"`
+	Version string `json:"version,omitempty"`
+	// ExpiresAt is the time the object expires, as reported by the provider
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// RefreshAfter is the time after which the object is fetched from the provider
+	// again, as reported by the provider or derived from the expiry
+	// +optional
+	RefreshAfter *metav1.Time `json:"refreshAfter,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +genclient
+
+// SecretProviderClassPodStatus is the Schema for the secretproviderclassespodstatus API
+type SecretProviderClassPodStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status SecretProviderClassPodStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretProviderClassPodStatusList contains a list of SecretProviderClassPodStatus
+type SecretProviderClassPodStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretProviderClassPodStatus `json:"items"`
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// StandaloneSyncAnnotation is set to "true" on a secret provider class to sync its secret objects
+	// without a pod mounting the volume. The objects are fetched by the standalone sync controller.
+	StandaloneSyncAnnotation = "secrets-store.csi.k8s.io/standalone-sync"
+	// StandaloneSyncServiceAccountAnnotation is the service account name passed to the provider
+	// in the mount request of the standalone sync, in place of the service account of the pod.
+	StandaloneSyncServiceAccountAnnotation = "secrets-store.csi.k8s.io/standalone-sync-service-account"
+	// StandaloneSyncSecretRefAnnotation is the name of the secret in the namespace of the secret provider
+	// class passed to the provider in the mount request of the standalone sync, in place of the
+	// nodePublishSecretRef of the volume.
+	StandaloneSyncSecretRefAnnotation = "secrets-store.csi.k8s.io/standalone-sync-secret-ref"
+	// StandaloneSyncAllowedAnnotation is set on a service account or secret to the comma separated
+	// names of the secret provider classes in the namespace that can use it in the standalone sync.
+	// The service account and secret named in the annotations of a secret provider class must allow
+	// it, so the permission to update the secret provider class doesn't grant their use.
+	StandaloneSyncAllowedAnnotation = "secrets-store.csi.k8s.io/standalone-sync-allowed-secret-provider-classes"
+
+	// ConditionTypeSynced indicates the objects in the secret provider class have been
+	// fetched from the provider and synced as Kubernetes secrets by the standalone sync
+	ConditionTypeSynced = "Synced"
+)
+
+// SecretProviderClassSyncStatusStatus defines the observed state of SecretProviderClassSyncStatus
+type SecretProviderClassSyncStatusStatus struct {
+	// SecretProviderClassName is the name of the secret provider class that's synced
+	SecretProviderClassName string `json:"secretProviderClassName,omitempty"`
+	// Objects are the versions of the objects fetched in the last successful sync
+	// +optional
+	Objects []SecretProviderClassObject `json:"objects,omitempty"`
+	// SyncedSecrets are the names of the secrets synced from the secret provider class
+	// +optional
+	SyncedSecrets []string `json:"syncedSecrets,omitempty"`
+	// SyncedConfigMaps are the names of the configmaps synced from the secret provider class
+	// +optional
+	SyncedConfigMaps []string `json:"syncedConfigMaps,omitempty"`
+	// Conditions represent the latest observations of the sync and provider state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSuccessfulSyncTime is the last time the objects were successfully synced
+	// +optional
+	LastSuccessfulSyncTime *metav1.Time `json:"lastSuccessfulSyncTime,omitempty"`
+	// LastAttemptTime is the last time a sync was attempted
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="SecretProviderClass",type=string,JSONPath=`.status.secretProviderClassName`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="LastSync",type=date,JSONPath=`.status.lastSuccessfulSyncTime`
+// +genclient
+
+// SecretProviderClassSyncStatus is the Schema for the secretproviderclasssyncstatuses API.
+// It has the same name as the secret provider class synced by the standalone sync controller.
+type SecretProviderClassSyncStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status SecretProviderClassSyncStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretProviderClassSyncStatusList contains a list of SecretProviderClassSyncStatus
+type SecretProviderClassSyncStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretProviderClassSyncStatus `json:"items"`
+}
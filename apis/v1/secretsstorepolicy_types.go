@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// ParameterConstraint constrains the value of a parameter sent to the provider
+type ParameterConstraint struct {
+	// key of the parameter
+	Key string `json:"key"`
+	// allowedValues lists the values the parameter can be set to
+	// +optional
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	// pattern is a regular expression the whole value of the parameter must match
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// required specifies whether the parameter must be set
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// SecretsStorePolicySpec defines the desired state of SecretsStorePolicy
+type SecretsStorePolicySpec struct {
+	// allowedProviders lists the providers the secret provider classes used in the
+	// namespace can reference. All providers are allowed if empty.
+	// +optional
+	AllowedProviders []string `json:"allowedProviders,omitempty"`
+	// parameters constrain the values of the parameters sent to the provider, after
+	// the placeholders are expanded and the parametersFrom sources are merged.
+	// Parameters without a constraint are allowed.
+	// +optional
+	Parameters []ParameterConstraint `json:"parameters,omitempty"`
+	// allowSecretSync specifies whether the secret provider classes used in the namespace
+	// can sync the objects as Kubernetes secrets with secretObjects, or as configmaps with
+	// configMapObjects. Defaults to true.
+	// +optional
+	AllowSecretSync *bool `json:"allowSecretSync,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Providers",type=string,JSONPath=`.spec.allowedProviders`
+// +kubebuilder:printcolumn:name="SecretSync",type=boolean,JSONPath=`.spec.allowSecretSync`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+
+// SecretsStorePolicy is the Schema for the secretsstorepolicies API. The policies in a namespace
+// are enforced for the secret provider classes used by the pods in the namespace before the
+// provider is called. All the policies in the namespace must allow the mount.
+type SecretsStorePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretsStorePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretsStorePolicyList contains a list of SecretsStorePolicy
+type SecretsStorePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretsStorePolicy `json:"items"`
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// V1FieldsAnnotation is set on the v1alpha1 objects converted from v1 objects that have
+// fields missing in v1alpha1. It has the json encoding of the v1 spec and status, so the
+// fields aren't lost when the object is written with v1alpha1 and converted back to v1.
+const V1FieldsAnnotation = "secrets-store.csi.x-k8s.io/v1-fields"
+
+// secretProviderClassV1Fields is the part of a v1 SecretProviderClass stored in the V1FieldsAnnotation
+type secretProviderClassV1Fields struct {
+	Spec   secretsstorev1.SecretProviderClassSpec   `json:"spec,omitempty"`
+	Status secretsstorev1.SecretProviderClassStatus `json:"status,omitempty"`
+}
+
+// secretProviderClassPodStatusV1Fields is the part of a v1 SecretProviderClassPodStatus
+// stored in the V1FieldsAnnotation
+type secretProviderClassPodStatusV1Fields struct {
+	Status secretsstorev1.SecretProviderClassPodStatusStatus `json:"status,omitempty"`
+}
+
+// ConvertTo converts this SecretProviderClass to the hub (v1) version.
+func (src *SecretProviderClass) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*secretsstorev1.SecretProviderClass)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+	restored := &secretProviderClassV1Fields{}
+	objectMeta, err := restoreV1Fields(&src.ObjectMeta, restored)
+	if err != nil {
+		return err
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = objectMeta
+	dst.Spec = restored.Spec
+	dst.Spec.Provider = secretsstorev1.Provider(in.Spec.Provider)
+	dst.Spec.Parameters = in.Spec.Parameters
+	dst.Spec.SecretObjects = convertSecretObjectsToV1(in.Spec.SecretObjects, restored.Spec.SecretObjects)
+	dst.Status = restored.Status
+	dst.Status.ByPod = convertByPodToV1(in.Status.ByPod, restored.Status.ByPod)
+	return nil
+}
+
+// ConvertFrom converts from the hub (v1) version to this SecretProviderClass.
+func (dst *SecretProviderClass) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*secretsstorev1.SecretProviderClass)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	deleteV1FieldsAnnotation(&dst.ObjectMeta)
+	dst.Spec = SecretProviderClassSpec{
+		Provider:      Provider(in.Spec.Provider),
+		Parameters:    in.Spec.Parameters,
+		SecretObjects: convertSecretObjectsFromV1(in.Spec.SecretObjects),
+	}
+	dst.Status = SecretProviderClassStatus{
+		ByPod: convertByPodFromV1(in.Status.ByPod),
+	}
+
+	// the annotation is only set when the fields would be lost without it, so the
+	// objects that only use the v1alpha1 fields are served unchanged
+	roundTrip := &secretsstorev1.SecretProviderClass{}
+	if err := dst.ConvertTo(roundTrip); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(roundTrip.Spec, src.Spec) && equality.Semantic.DeepEqual(roundTrip.Status, src.Status) {
+		return nil
+	}
+	return setV1Fields(&dst.ObjectMeta, &secretProviderClassV1Fields{Spec: src.Spec, Status: src.Status})
+}
+
+// ConvertTo converts this SecretProviderClassPodStatus to the hub (v1) version.
+func (src *SecretProviderClassPodStatus) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*secretsstorev1.SecretProviderClassPodStatus)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+	restored := &secretProviderClassPodStatusV1Fields{}
+	objectMeta, err := restoreV1Fields(&src.ObjectMeta, restored)
+	if err != nil {
+		return err
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = objectMeta
+	dst.Status = restored.Status
+	dst.Status.PodName = in.Status.PodName
+	dst.Status.SecretProviderClassName = in.Status.SecretProviderClassName
+	dst.Status.Mounted = in.Status.Mounted
+	dst.Status.TargetPath = in.Status.TargetPath
+	dst.Status.Objects = convertObjectsToV1(in.Status.Objects, restored.Status.Objects)
+	return nil
+}
+
+// ConvertFrom converts from the hub (v1) version to this SecretProviderClassPodStatus.
+func (dst *SecretProviderClassPodStatus) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*secretsstorev1.SecretProviderClassPodStatus)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	deleteV1FieldsAnnotation(&dst.ObjectMeta)
+	dst.Status = SecretProviderClassPodStatusStatus{
+		PodName:                 in.Status.PodName,
+		SecretProviderClassName: in.Status.SecretProviderClassName,
+		Mounted:                 in.Status.Mounted,
+		TargetPath:              in.Status.TargetPath,
+		Objects:                 convertObjectsFromV1(in.Status.Objects),
+	}
+
+	roundTrip := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := dst.ConvertTo(roundTrip); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(roundTrip.Status, src.Status) {
+		return nil
+	}
+	return setV1Fields(&dst.ObjectMeta, &secretProviderClassPodStatusV1Fields{Status: src.Status})
+}
+
+// setV1Fields stores the json encoding of fields in the V1FieldsAnnotation of the object
+func setV1Fields(objectMeta *metav1.ObjectMeta, fields interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal v1 fields, err: %w", err)
+	}
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = make(map[string]string)
+	}
+	objectMeta.Annotations[V1FieldsAnnotation] = string(data)
+	return nil
+}
+
+// restoreV1Fields decodes the V1FieldsAnnotation of the object into fields and returns a copy
+// of the object metadata without the annotation. fields is left unchanged if the object
+// doesn't have the annotation.
+func restoreV1Fields(objectMeta *metav1.ObjectMeta, fields interface{}) (metav1.ObjectMeta, error) {
+	out := *objectMeta.DeepCopy()
+	data, ok := out.Annotations[V1FieldsAnnotation]
+	if !ok {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(data), fields); err != nil {
+		return out, fmt.Errorf("failed to unmarshal %s annotation, err: %w", V1FieldsAnnotation, err)
+	}
+	deleteV1FieldsAnnotation(&out)
+	return out, nil
+}
+
+// deleteV1FieldsAnnotation removes the V1FieldsAnnotation from the object metadata
+func deleteV1FieldsAnnotation(objectMeta *metav1.ObjectMeta) {
+	delete(objectMeta.Annotations, V1FieldsAnnotation)
+	if len(objectMeta.Annotations) == 0 {
+		objectMeta.Annotations = nil
+	}
+}
+
+// convertSecretObjectsToV1 converts the secret objects to v1. The v1 fields of the restored
+// secret objects with the same secret name are kept.
+func convertSecretObjectsToV1(in []*SecretObject, restored []*secretsstorev1.SecretObject) []*secretsstorev1.SecretObject {
+	if in == nil {
+		return nil
+	}
+	out := make([]*secretsstorev1.SecretObject, 0, len(in))
+	for _, secretObj := range in {
+		if secretObj == nil {
+			out = append(out, nil)
+			continue
+		}
+		var restoredData []*secretsstorev1.SecretObjectData
+		converted := &secretsstorev1.SecretObject{
+			SecretName:  secretObj.SecretName,
+			Type:        secretObj.Type,
+			Labels:      secretObj.Labels,
+			Annotations: secretObj.Annotations,
+		}
+		for _, r := range restored {
+			if r != nil && r.SecretName == secretObj.SecretName {
+				converted.RetentionPolicy = r.RetentionPolicy
+				restoredData = r.Data
+				break
+			}
+		}
+		converted.Data = convertSecretObjectDataToV1(secretObj.Data, restoredData)
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertSecretObjectDataToV1 converts the secret object data to v1. The v1 fields of the
+// restored data with the same key are kept.
+func convertSecretObjectDataToV1(in []*SecretObjectData, restored []*secretsstorev1.SecretObjectData) []*secretsstorev1.SecretObjectData {
+	if in == nil {
+		return nil
+	}
+	out := make([]*secretsstorev1.SecretObjectData, 0, len(in))
+	for _, data := range in {
+		if data == nil {
+			out = append(out, nil)
+			continue
+		}
+		converted := &secretsstorev1.SecretObjectData{ObjectName: data.ObjectName, Key: data.Key}
+		for _, r := range restored {
+			if r != nil && r.Key == data.Key {
+				converted.Template = r.Template
+				converted.Transforms = r.Transforms
+				break
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertSecretObjectsFromV1 converts the v1 secret objects to v1alpha1
+func convertSecretObjectsFromV1(in []*secretsstorev1.SecretObject) []*SecretObject {
+	if in == nil {
+		return nil
+	}
+	out := make([]*SecretObject, 0, len(in))
+	for _, secretObj := range in {
+		if secretObj == nil {
+			out = append(out, nil)
+			continue
+		}
+		converted := &SecretObject{
+			SecretName:  secretObj.SecretName,
+			Type:        secretObj.Type,
+			Labels:      secretObj.Labels,
+			Annotations: secretObj.Annotations,
+		}
+		if secretObj.Data != nil {
+			converted.Data = make([]*SecretObjectData, 0, len(secretObj.Data))
+			for _, data := range secretObj.Data {
+				if data == nil {
+					converted.Data = append(converted.Data, nil)
+					continue
+				}
+				converted.Data = append(converted.Data, &SecretObjectData{ObjectName: data.ObjectName, Key: data.Key})
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertByPodToV1 converts the pod statuses to v1. The v1 fields of the restored pod
+// statuses with the same id are kept.
+func convertByPodToV1(in []*ByPodStatus, restored []*secretsstorev1.ByPodStatus) []*secretsstorev1.ByPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]*secretsstorev1.ByPodStatus, 0, len(in))
+	for _, byPod := range in {
+		if byPod == nil {
+			out = append(out, nil)
+			continue
+		}
+		converted := &secretsstorev1.ByPodStatus{ID: byPod.ID, Namespace: byPod.Namespace}
+		for _, r := range restored {
+			if r != nil && r.ID == byPod.ID {
+				converted.PodName = r.PodName
+				converted.NodeName = r.NodeName
+				converted.Objects = r.Objects
+				converted.Ready = r.Ready
+				break
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertByPodFromV1 converts the v1 pod statuses to v1alpha1
+func convertByPodFromV1(in []*secretsstorev1.ByPodStatus) []*ByPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]*ByPodStatus, 0, len(in))
+	for _, byPod := range in {
+		if byPod == nil {
+			out = append(out, nil)
+			continue
+		}
+		out = append(out, &ByPodStatus{ID: byPod.ID, Namespace: byPod.Namespace})
+	}
+	return out
+}
+
+// convertObjectsToV1 converts the objects to v1. The v1 fields of the restored objects
+// with the same id are kept.
+func convertObjectsToV1(in []SecretProviderClassObject, restored []secretsstorev1.SecretProviderClassObject) []secretsstorev1.SecretProviderClassObject {
+	if in == nil {
+		return nil
+	}
+	out := make([]secretsstorev1.SecretProviderClassObject, 0, len(in))
+	for _, obj := range in {
+		converted := secretsstorev1.SecretProviderClassObject{ID: obj.ID, Version: obj.Version}
+		for _, r := range restored {
+			if r.ID == obj.ID {
+				converted.ExpiresAt = r.ExpiresAt
+				converted.RefreshAfter = r.RefreshAfter
+				break
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertObjectsFromV1 converts the v1 objects to v1alpha1
+func convertObjectsFromV1(in []secretsstorev1.SecretProviderClassObject) []SecretProviderClassObject {
+	if in == nil {
+		return nil
+	}
+	out := make([]SecretProviderClassObject, 0, len(in))
+	for _, obj := range in {
+		out = append(out, SecretProviderClassObject{ID: obj.ID, Version: obj.Version})
+	}
+	return out
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+func TestSecretProviderClassConversion(t *testing.T) {
+	src := &SecretProviderClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "spc", Namespace: "default"},
+		Spec: SecretProviderClassSpec{
+			Provider:   "provider1",
+			Parameters: map[string]string{"key": "value"},
+			SecretObjects: []*SecretObject{
+				{
+					SecretName:  "secret1",
+					Type:        "Opaque",
+					Labels:      map[string]string{"l": "v"},
+					Annotations: map[string]string{"a": "v"},
+					Data: []*SecretObjectData{
+						{ObjectName: "obj1", Key: "key1"},
+					},
+				},
+			},
+		},
+		Status: SecretProviderClassStatus{
+			ByPod: []*ByPodStatus{{ID: "pod1", Namespace: "default"}},
+		},
+	}
+
+	hub := &secretsstorev1.SecretProviderClass{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if hub.Spec.Provider != "provider1" || len(hub.Spec.SecretObjects) != 1 || hub.Spec.SecretObjects[0].Data[0].Key != "key1" {
+		t.Fatalf("ConvertTo() unexpected hub object: %+v", hub)
+	}
+
+	got := &SecretProviderClass{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if diff := cmp.Diff(src, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretProviderClassPodStatusConversion(t *testing.T) {
+	src := &SecretProviderClassPodStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1-default-spc",
+			Namespace: "default",
+			Labels:    map[string]string{InternalNodeLabel: "node1"},
+		},
+		Status: SecretProviderClassPodStatusStatus{
+			PodName:                 "pod1",
+			SecretProviderClassName: "spc",
+			Mounted:                 true,
+			TargetPath:              "/var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/vol/mount",
+			Objects:                 []SecretProviderClassObject{{ID: "secret/obj1", Version: "v1"}},
+		},
+	}
+
+	hub := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	got := &SecretProviderClassPodStatus{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if diff := cmp.Diff(src, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretProviderClassPodStatusHubRoundTrip(t *testing.T) {
+	now := metav1.Now()
+	hub := &secretsstorev1.SecretProviderClassPodStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod1-default-spc",
+			Namespace:   "default",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+			PodName:                 "pod1",
+			SecretProviderClassName: "spc",
+			Mounted:                 true,
+			Conditions: []metav1.Condition{
+				{
+					Type:               secretsstorev1.ConditionTypeMounted,
+					Status:             metav1.ConditionTrue,
+					Reason:             secretsstorev1.ConditionReasonSucceeded,
+					LastTransitionTime: metav1.Unix(now.Unix(), 0),
+				},
+			},
+			LastAttemptTime:            &metav1.Time{Time: now.Rfc3339Copy().Time},
+			LastSuccessfulRotationTime: &metav1.Time{Time: now.Rfc3339Copy().Time},
+			Objects: []secretsstorev1.SecretProviderClassObject{
+				{ID: "secret/static", Version: "v1"},
+				{ID: "secret/cert", Version: "v1", ExpiresAt: &metav1.Time{Time: now.Rfc3339Copy().Time}, RefreshAfter: &metav1.Time{Time: now.Rfc3339Copy().Time}},
+			},
+		},
+	}
+
+	spoke := &SecretProviderClassPodStatus{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	got := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if diff := cmp.Diff(hub, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretProviderClassHubRoundTrip(t *testing.T) {
+	defaultMode := int32(0400)
+	hub := &secretsstorev1.SecretProviderClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "spc", Namespace: "default"},
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider:   "provider1",
+			Parameters: map[string]string{"key": "value"},
+			ParametersFrom: []*secretsstorev1.ParametersFromSource{
+				{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+				{SecretRef: &secretsstorev1.ParametersFromReference{Name: "vault-role", Optional: true}},
+			},
+			SecretObjects: []*secretsstorev1.SecretObject{
+				{
+					SecretName: "secret1",
+					Type:       "Opaque",
+					Data: []*secretsstorev1.SecretObjectData{
+						{
+							ObjectName: "obj1",
+							Key:        "key1",
+							Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformJSONPath, JSONPath: "{.password}"}},
+						},
+						{Template: "url", Key: "url"},
+					},
+					RetentionPolicy: secretsstorev1.SecretRetentionPolicyRetain,
+				},
+			},
+			FileTransforms: []*secretsstorev1.FileTransform{
+				{Path: "obj1", Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformBase64Decode}}},
+			},
+			Templates: []*secretsstorev1.SecretTemplate{
+				{Name: "url", Template: "{{ .obj1 }}", Path: "url"},
+			},
+			DefaultMode: &defaultMode,
+			ConfigMapObjects: []*secretsstorev1.ConfigMapObject{
+				{
+					ConfigMapName:   "ca",
+					Data:            []*secretsstorev1.SecretObjectData{{ObjectName: "ca.crt", Key: "ca.crt"}},
+					RetentionPolicy: secretsstorev1.SecretRetentionPolicyRetain,
+				},
+			},
+		},
+		Status: secretsstorev1.SecretProviderClassStatus{
+			ByPod: []*secretsstorev1.ByPodStatus{
+				{
+					ID:        "uid1",
+					Namespace: "default",
+					PodName:   "pod1",
+					NodeName:  "node1",
+					Objects:   []secretsstorev1.SecretProviderClassObject{{ID: "secret/obj1", Version: "v1"}},
+					Ready:     true,
+				},
+			},
+			TotalPods: 1,
+			ReadyPods: 1,
+		},
+	}
+
+	spoke := &SecretProviderClass{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	got := &secretsstorev1.SecretProviderClass{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if diff := cmp.Diff(hub, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretProviderClassConversionAnnotation(t *testing.T) {
+	hub := &secretsstorev1.SecretProviderClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "spc", Namespace: "default"},
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider:      "provider1",
+			SecretObjects: []*secretsstorev1.SecretObject{{SecretName: "secret1", Data: []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "key1"}}}},
+		},
+	}
+
+	spoke := &SecretProviderClass{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if _, ok := spoke.Annotations[V1FieldsAnnotation]; ok {
+		t.Errorf("ConvertFrom() set the %s annotation for an object without v1 fields", V1FieldsAnnotation)
+	}
+
+	hub.Spec.SecretObjects[0].RetentionPolicy = secretsstorev1.SecretRetentionPolicyRetain
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if _, ok := spoke.Annotations[V1FieldsAnnotation]; !ok {
+		t.Errorf("ConvertFrom() didn't set the %s annotation for an object with v1 fields", V1FieldsAnnotation)
+	}
+}
+
+func TestSecretProviderClassConversionEditedSpoke(t *testing.T) {
+	hub := &secretsstorev1.SecretProviderClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "spc", Namespace: "default"},
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider:       "provider1",
+			ParametersFrom: []*secretsstorev1.ParametersFromSource{{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "config"}}},
+			SecretObjects: []*secretsstorev1.SecretObject{
+				{
+					SecretName:      "secret1",
+					Data:            []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "key1", Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformTrim}}}},
+					RetentionPolicy: secretsstorev1.SecretRetentionPolicyRetain,
+				},
+				{
+					SecretName:      "secret2",
+					Data:            []*secretsstorev1.SecretObjectData{{ObjectName: "obj2", Key: "key2"}},
+					RetentionPolicy: secretsstorev1.SecretRetentionPolicyRetain,
+				},
+			},
+		},
+	}
+
+	spoke := &SecretProviderClass{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	// edit the common fields with v1alpha1: the v1 fields of the kept secret objects are restored
+	spoke.Spec.Provider = "provider2"
+	spoke.Spec.SecretObjects[0].Data[0].ObjectName = "obj3"
+	spoke.Spec.SecretObjects = spoke.Spec.SecretObjects[:1]
+
+	got := &secretsstorev1.SecretProviderClass{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	want := hub.DeepCopy()
+	want.Spec.Provider = "provider2"
+	want.Spec.SecretObjects[0].Data[0].ObjectName = "obj3"
+	want.Spec.SecretObjects = want.Spec.SecretObjects[:1]
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ConvertTo() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretProviderClassPodStatusConversionEditedSpoke(t *testing.T) {
+	expiresAt := metav1.Unix(1700000000, 0)
+	hub := &secretsstorev1.SecretProviderClassPodStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1-default-spc", Namespace: "default"},
+		Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+			PodName:                 "pod1",
+			SecretProviderClassName: "spc",
+			SecretProviderClassKind: "ClusterSecretProviderClass",
+			Mounted:                 true,
+			Objects: []secretsstorev1.SecretProviderClassObject{
+				{ID: "secret/obj1", Version: "v1", ExpiresAt: &expiresAt},
+			},
+		},
+	}
+
+	spoke := &SecretProviderClassPodStatus{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	spoke.Status.Objects[0].Version = "v2"
+	spoke.Status.Objects = append(spoke.Status.Objects, SecretProviderClassObject{ID: "secret/obj2", Version: "v1"})
+
+	got := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	want := hub.DeepCopy()
+	want.Status.Objects[0].Version = "v2"
+	want.Status.Objects = append(want.Status.Objects, secretsstorev1.SecretProviderClassObject{ID: "secret/obj2", Version: "v1"})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ConvertTo() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -14,7 +14,9 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package v1alpha1 contains API Schema definitions for the networking v1alpha1 API group
+// Package v1alpha1 contains API Schema definitions for the networking v1alpha1 API group.
+// The v1alpha1 schema is frozen: new fields are only added to v1, and the conversion
+// webhook keeps them when an object is written with v1alpha1.
 // +kubebuilder:object:generate=true
 // +groupName=secrets-store.csi.x-k8s.io
 package v1alpha1
@@ -34,17 +34,24 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	cliflag "k8s.io/component-base/cli/flag"
 	json "k8s.io/component-base/logs/json"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
 	"sigs.k8s.io/secrets-store-csi-driver/controllers"
 	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +68,12 @@ var (
 	enableProfile        = flag.Bool("enable-pprof", false, "enable pprof profiling")
 	profilePort          = flag.Int("pprof-port", 6065, "port for pprof profiling")
 	maxCallRecvMsgSize   = flag.Int("max-call-recv-msg-size", 1024*1024*4, "maximum size in bytes of gRPC response from plugins")
+	// the driver refuses to connect to providers with a runtime version below the minimum version
+	minProviderVersions = cliflag.ConfigurationMap{}
+	// the maximum size of the mount response of the providers, overrides max-call-recv-msg-size
+	providerMaxMountSizes = cliflag.ConfigurationMap{}
+	// the call timeout, maximum concurrent mounts and circuit breaker of the providers
+	providerConfigFile = flag.String("provider-config-file", "", "Path to the provider config file with the call timeout, maximum concurrent mounts and circuit breaker of the providers")
 
 	// enable filtered watch for NodePublishSecretRef secrets. The filtering is done on the csi driver label: secrets-store.csi.k8s.io/used=true
 	// For Kubernetes secrets used to provide credentials for use with the CSI driver, set the label by running: kubectl label secret secrets-store-creds secrets-store.csi.k8s.io/used=true
@@ -69,6 +82,34 @@ var (
 	// Enable optional healthcheck for provider clients that exist in memory
 	providerHealthCheck         = flag.Bool("provider-health-check", false, "Enable health check for configured providers")
 	providerHealthCheckInterval = flag.Duration("provider-health-check-interval", 2*time.Minute, "Provider healthcheck interval duration")
+	// the connection to the provider is reset and mounts fail fast after the consecutive failed health checks
+	providerHealthCheckFailureThreshold = flag.Int("provider-health-check-failure-threshold", 3, "Number of consecutive failed health checks after which a provider is unhealthy")
+
+	// Serve the conversion webhook for the v1alpha1 <-> v1 secrets-store.csi.x-k8s.io APIs
+	enableConversionWebhook = flag.Bool("enable-conversion-webhook", false, "Enable the conversion webhook for SecretProviderClass and SecretProviderClassPodStatus")
+	webhookPort             = flag.Int("webhook-port", 9443, "port the webhook server binds to")
+	webhookCertDir          = flag.String("webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "directory containing the webhook server tls.crt and tls.key")
+	// Serve the validating webhooks for SecretProviderClass and ClusterSecretProviderClass on the same webhook server
+	enableValidatingWebhook = flag.Bool("enable-validating-webhook", false, "Enable the validating webhook for SecretProviderClass and ClusterSecretProviderClass")
+	// Generate and rotate the webhook serving certificate, and configure the CRDs and the validating webhook
+	// configuration with its CA. The CRDs are installed with the None conversion strategy and switched to Webhook.
+	enableWebhookCertRotation      = flag.Bool("enable-webhook-cert-rotation", false, "Generate and rotate the webhook serving certificate and inject its CA in the conversion and validating webhooks")
+	webhookCertSecretName          = flag.String("webhook-cert-secret-name", "secrets-store-csi-driver-webhook-cert", "name of the secret the webhook certificate is stored in, in the webhook service namespace")
+	webhookServiceName             = flag.String("webhook-service-name", "secrets-store-csi-driver-webhook-service", "name of the service the webhook server is exposed with")
+	webhookServiceNamespace        = flag.String("webhook-service-namespace", "kube-system", "namespace of the service the webhook server is exposed with")
+	validatingWebhookConfiguration = flag.String("validating-webhook-configuration-name", "secrets-store-csi-driver-validating-webhook", "name of the validating webhook configuration the CA is injected in")
+
+	// Run the controller that aggregates the secret provider class pod statuses from all nodes in the
+	// secret provider class status instead of the driver. It runs in a deployment with leader election,
+	// as it watches the spc pod statuses of the whole cluster.
+	spcStatusController     = flag.Bool("spc-status-controller", false, "Run the controller that aggregates the secret provider class pod statuses in the secret provider class status instead of the CSI driver [alpha]")
+	spcStatusUpdateInterval = flag.Duration("spc-status-update-interval", 30*time.Second, "Minimum interval between status updates of a secret provider class")
+
+	// Run the standalone sync controller instead of the driver. It syncs the secret provider classes annotated
+	// for standalone sync as Kubernetes secrets without a pod mounting the volume.
+	standaloneSync         = flag.Bool("standalone-sync", false, "Run the standalone secret sync controller instead of the CSI driver [alpha]")
+	standaloneSyncDir      = flag.String("standalone-sync-dir", "/var/run/secrets-store-sync", "Private directory the standalone sync writes the objects to, which should be an in-memory volume")
+	standaloneSyncInterval = flag.Duration("standalone-sync-interval", 2*time.Minute, "Interval between syncs of a secret provider class by the standalone sync")
 
 	scheme = runtime.NewScheme()
 )
@@ -76,6 +117,7 @@ var (
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
+	_ = secretsstorev1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -83,6 +125,9 @@ func main() {
 	klog.InitFlags(nil)
 	defer klog.Flush()
 
+	flag.Var(&minProviderVersions, "min-provider-versions", "comma-separated list of provider=version pairs with the minimum runtime version of the providers, e.g. vault=0.3.0")
+	flag.Var(&providerMaxMountSizes, "provider-max-mount-sizes", "comma-separated list of provider=size pairs with the maximum size of the mount response of the providers, e.g. vault=16Mi")
+
 	flag.Parse()
 
 	if *logFormatJSON {
@@ -108,10 +153,21 @@ func main() {
 	cfg := ctrl.GetConfigOrDie()
 	cfg.UserAgent = version.GetUserAgent("controller")
 
+	if *standaloneSync {
+		runStandaloneSync(cfg)
+		return
+	}
+	if *spcStatusController {
+		runSPCStatusController(cfg)
+		return
+	}
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: *metricsAddr,
 		LeaderElection:     false,
+		Port:               *webhookPort,
+		CertDir:            *webhookCertDir,
 		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
 			return apiutil.NewDynamicRESTMapper(c, apiutil.WithLazyDiscovery)
 		},
@@ -124,10 +180,10 @@ func main() {
 				},
 				// this enables filtered watch of secretproviderclasspodstatuses based on the internal node label
 				// internal.secrets-store.csi.k8s.io/node-name=<node name> added by csi driver
-				&v1alpha1.SecretProviderClassPodStatus{}: {
+				&secretsstorev1.SecretProviderClassPodStatus{}: {
 					Label: labels.SelectorFromSet(
 						labels.Set{
-							v1alpha1.InternalNodeLabel: *nodeID,
+							secretsstorev1.InternalNodeLabel: *nodeID,
 						},
 					),
 				},
@@ -140,6 +196,15 @@ func main() {
 						},
 					),
 				},
+				// this enables filtered watch of configmaps based on the same label added to the
+				// configmaps synced by the CSI driver
+				&corev1.ConfigMap{}: {
+					Label: labels.SelectorFromSet(
+						labels.Set{
+							controllers.SecretManagedLabel: "true",
+						},
+					),
+				},
 			},
 		}),
 	})
@@ -154,17 +219,54 @@ func main() {
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		klog.Fatalf("failed to create controller, error: %+v", err)
 	}
+	if *enableConversionWebhook {
+		klog.InfoS("conversion webhook enabled", "port", *webhookPort)
+		if err = (&secretsstorev1.SecretProviderClass{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Fatalf("failed to create conversion webhook for secretproviderclass, error: %+v", err)
+		}
+		if err = (&secretsstorev1.SecretProviderClassPodStatus{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Fatalf("failed to create conversion webhook for secretproviderclasspodstatus, error: %+v", err)
+		}
+	}
+	if *enableValidatingWebhook {
+		klog.InfoS("validating webhook enabled", "port", *webhookPort)
+		if err = webhook.NewSecretProviderClassValidator(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+			klog.Fatalf("failed to create validating webhook for secretproviderclass, error: %+v", err)
+		}
+		if err = webhook.NewClusterSecretProviderClassValidator().SetupWithManager(mgr); err != nil {
+			klog.Fatalf("failed to create validating webhook for clustersecretproviderclass, error: %+v", err)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	ctx := withShutdownSignal(context.Background())
 
+	if *enableWebhookCertRotation && (*enableConversionWebhook || *enableValidatingWebhook) {
+		runWebhookCertRotation(ctx, cfg, mgr)
+	}
+
 	// create provider clients
 	providerClients := secretsstore.NewPluginClientBuilder(*providerVolumePath, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(*maxCallRecvMsgSize)))
 	defer providerClients.Cleanup()
+	if err := providerClients.SetMinimumVersions(minProviderVersions); err != nil {
+		klog.Fatalf("failed to set minimum provider versions, error: %+v", err)
+	}
+	if err := providerClients.SetMaxMountSizes(providerMaxMountSizes); err != nil {
+		klog.Fatalf("failed to set provider maximum mount sizes, error: %+v", err)
+	}
+	setProviderConfig(providerClients)
+	runProviderRegistration(ctx, mgr, providerClients)
 
 	// enable provider health check
 	if *providerHealthCheck {
-		klog.InfoS("provider health check enabled", "interval", *providerHealthCheckInterval)
+		klog.InfoS("provider health check enabled", "interval", *providerHealthCheckInterval, "failureThreshold", *providerHealthCheckFailureThreshold)
+		if err := providerClients.SetHealthCheckFailureThreshold(*providerHealthCheckFailureThreshold); err != nil {
+			klog.Fatalf("failed to set provider health check failure threshold, error: %+v", err)
+		}
+		secretsstore.NewProviderHealthObserver(providerClients)
+		if err := mgr.AddMetricsExtraHandler("/readyz/providers", secretsstore.NewHealthHandler(providerClients)); err != nil {
+			klog.Fatalf("failed to add provider health endpoint, error: %+v", err)
+		}
 		go providerClients.HealthCheck(ctx, *providerHealthCheckInterval)
 	}
 
@@ -181,7 +283,7 @@ func main() {
 
 	// Secret rotation
 	if *enableSecretRotation {
-		rec, err := rotation.NewReconciler(mgr.GetCache(), scheme, *providerVolumePath, *nodeID, *rotationPollInterval, providerClients, *filteredWatchSecret)
+		rec, err := rotation.NewReconciler(mgr.GetCache(), mgr.GetAPIReader(), scheme, *providerVolumePath, *nodeID, *rotationPollInterval, providerClients, *filteredWatchSecret)
 		if err != nil {
 			klog.Fatalf("failed to initialize rotation reconciler, error: %+v", err)
 		}
@@ -189,7 +291,149 @@ func main() {
 	}
 
 	driver := secretsstore.GetDriver()
-	driver.Run(ctx, *driverName, *nodeID, *endpoint, *providerVolumePath, providerClients, mgr.GetClient())
+	driver.Run(ctx, *driverName, *nodeID, *endpoint, *providerVolumePath, providerClients, mgr.GetClient(), mgr.GetAPIReader(), mgr.GetEventRecorderFor("csi-secrets-store-driver"))
+}
+
+// runWebhookCertRotation writes the webhook serving certificate before the manager starts the
+// webhook server, and keeps it renewed and injected in the webhooks while the manager runs.
+func runWebhookCertRotation(ctx context.Context, cfg *rest.Config, mgr ctrl.Manager) {
+	// the manager's client reads secrets from a cache that only has the synced secrets
+	c, err := client.New(cfg, client.Options{Scheme: scheme, Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		klog.Fatalf("failed to create webhook cert rotation client, error: %+v", err)
+	}
+	rotator := webhook.NewCertRotator(c,
+		types.NamespacedName{Namespace: *webhookServiceNamespace, Name: *webhookCertSecretName},
+		types.NamespacedName{Namespace: *webhookServiceNamespace, Name: *webhookServiceName},
+		*webhookCertDir)
+	if *enableConversionWebhook {
+		rotator.CRDNames = webhook.ConversionCRDs
+	}
+	if *enableValidatingWebhook {
+		rotator.ValidatingWebhookConfiguration = *validatingWebhookConfiguration
+	}
+	klog.InfoS("webhook cert rotation enabled", "secret", rotator.Secret, "service", rotator.Service)
+	if err := wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		if err := rotator.Sync(ctx); err != nil {
+			klog.ErrorS(err, "failed to sync webhook cert")
+			return false, nil
+		}
+		return true, nil
+	}, ctx.Done()); err != nil {
+		klog.Fatalf("failed to sync webhook cert, error: %+v", err)
+	}
+	if err := mgr.Add(rotator); err != nil {
+		klog.Fatalf("failed to add webhook cert rotation, error: %+v", err)
+	}
+}
+
+// runStandaloneSync runs the standalone sync controller. The controller runs in a deployment
+// with leader election, so only one replica calls the providers for the secret provider classes.
+func runStandaloneSync(cfg *rest.Config) {
+	klog.InfoS("standalone sync enabled", "syncDir", *standaloneSyncDir, "interval", *standaloneSyncInterval)
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                     scheme,
+		MetricsBindAddress:         *metricsAddr,
+		LeaderElection:             true,
+		LeaderElectionID:           "secrets-store-csi-driver-standalone-sync",
+		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
+			return apiutil.NewDynamicRESTMapper(c, apiutil.WithLazyDiscovery)
+		},
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				// only the secrets and configmaps synced by the driver are cached
+				&corev1.Secret{}: {
+					Label: labels.SelectorFromSet(labels.Set{controllers.SecretManagedLabel: "true"}),
+				},
+				&corev1.ConfigMap{}: {
+					Label: labels.SelectorFromSet(labels.Set{controllers.SecretManagedLabel: "true"}),
+				},
+			},
+		}),
+	})
+	if err != nil {
+		klog.Fatalf("failed to start standalone sync manager, error: %+v", err)
+	}
+
+	providerClients := secretsstore.NewPluginClientBuilder(*providerVolumePath, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(*maxCallRecvMsgSize)))
+	defer providerClients.Cleanup()
+	if err := providerClients.SetMinimumVersions(minProviderVersions); err != nil {
+		klog.Fatalf("failed to set minimum provider versions, error: %+v", err)
+	}
+	if err := providerClients.SetMaxMountSizes(providerMaxMountSizes); err != nil {
+		klog.Fatalf("failed to set provider maximum mount sizes, error: %+v", err)
+	}
+	setProviderConfig(providerClients)
+	ctx := withShutdownSignal(context.Background())
+	runProviderRegistration(ctx, mgr, providerClients)
+
+	if err = controllers.NewSecretProviderClassSyncReconciler(mgr, providerClients, *standaloneSyncDir, *standaloneSyncInterval).SetupWithManager(mgr); err != nil {
+		klog.Fatalf("failed to create standalone sync controller, error: %+v", err)
+	}
+
+	klog.Infof("starting standalone sync manager")
+	if err := mgr.Start(ctx); err != nil {
+		klog.Fatalf("failed to run standalone sync manager, error: %+v", err)
+	}
+}
+
+// runSPCStatusController runs the controller that aggregates the spc pod statuses in the secret provider
+// class status. The controller runs in a deployment with leader election, so only one replica caches the
+// spc pod statuses of the whole cluster and updates the secret provider classes.
+func runSPCStatusController(cfg *rest.Config) {
+	klog.InfoS("secret provider class status aggregation enabled", "updateInterval", *spcStatusUpdateInterval)
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                     scheme,
+		MetricsBindAddress:         *metricsAddr,
+		LeaderElection:             true,
+		LeaderElectionID:           "secrets-store-csi-driver-spc-status",
+		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
+			return apiutil.NewDynamicRESTMapper(c, apiutil.WithLazyDiscovery)
+		},
+	})
+	if err != nil {
+		klog.Fatalf("failed to start spc status manager, error: %+v", err)
+	}
+	if err = controllers.NewSecretProviderClassStatusReconciler(mgr, *spcStatusUpdateInterval).SetupWithManager(mgr); err != nil {
+		klog.Fatalf("failed to create spc status controller, error: %+v", err)
+	}
+
+	klog.Infof("starting spc status manager")
+	if err := mgr.Start(withShutdownSignal(context.Background())); err != nil {
+		klog.Fatalf("failed to run spc status manager, error: %+v", err)
+	}
+}
+
+// setProviderConfig loads the provider config file, if set, and reports the state of the
+// calls to the providers it limits
+func setProviderConfig(providerClients *secretsstore.PluginClientBuilder) {
+	if *providerConfigFile == "" {
+		return
+	}
+	providerConfig, err := secretsstore.LoadProviderConfig(*providerConfigFile)
+	if err != nil {
+		klog.Fatalf("failed to load provider config, error: %+v", err)
+	}
+	if err := providerClients.SetProviderConfig(providerConfig); err != nil {
+		klog.Fatalf("failed to set provider config, error: %+v", err)
+	}
+	secretsstore.NewProviderCallObserver(providerClients)
+}
+
+// runProviderRegistration watches the provider volume to register the providers when their
+// socket is created and remove them when it's removed, and serves the provider registry on
+// the /debug/providers endpoint of the metrics server.
+func runProviderRegistration(ctx context.Context, mgr ctrl.Manager, providerClients *secretsstore.PluginClientBuilder) {
+	if err := mgr.AddMetricsExtraHandler("/debug/providers", secretsstore.NewRegistryHandler(providerClients)); err != nil {
+		klog.Fatalf("failed to add provider registry endpoint, error: %+v", err)
+	}
+	go func() {
+		if err := secretsstore.NewPluginWatcher(providerClients).Run(ctx); err != nil {
+			klog.ErrorS(err, "failed to watch provider volume, providers are connected to on the first mount")
+		}
+	}()
 }
 
 // withShutdownSignal returns a copy of the parent context that will close if
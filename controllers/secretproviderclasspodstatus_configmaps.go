@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/secretutil"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	configMapCreationFailedReason = "FailedToCreateConfigMap"
+	configMapUpdateFailedReason   = "FailedToUpdateConfigMap"
+	configMapUpdatedReason        = "ConfigMapUpdated"
+	configMapDeletedReason        = "ConfigMapDeleted"
+	configMapRetainedReason       = "ConfigMapRetained"
+	configMapCleanupFailedReason  = "FailedToCleanupConfigMap"
+)
+
+// syncConfigMaps creates or updates the configmaps defined in the configmap objects of the spc
+// with the mounted files. The configmaps are managed in the same way as the synced secrets, so
+// they have the same labels and annotations, and existing configmaps that aren't managed by the
+// driver are skipped. It returns the errors for the configmaps that couldn't be synced.
+func (r *SecretProviderClassPodStatusReconciler) syncConfigMaps(ctx context.Context, pod *corev1.Pod, spc *secretsstorev1.SecretProviderClass, spcPodStatus *secretsstorev1.SecretProviderClassPodStatus, spcRef string, files map[string]string) []error {
+	var errs []error
+	for _, configMapObj := range spc.Spec.ConfigMapObjects {
+		if configMapObj == nil {
+			continue
+		}
+		configMapName := strings.TrimSpace(configMapObj.ConfigMapName)
+
+		if err := secretutil.ValidateConfigMapObject(*configMapObj); err != nil {
+			klog.ErrorS(err, "failed to validate configmap object in spc", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+			errs = append(errs, fmt.Errorf("failed to validate configmap object in spc %s/%s, err: %+v", spc.Namespace, spc.Name, err))
+			continue
+		}
+
+		data, binaryData, err := secretutil.GetConfigMapData(configMapObj.Data, files, spc.Spec.Templates)
+		if err != nil {
+			r.generateEvent(pod, corev1.EventTypeWarning, configMapCreationFailedReason, fmt.Sprintf("failed to get data in spc %s/%s for configmap %s, err: %+v", pod.Namespace, spc.Name, configMapName, err))
+			klog.ErrorS(err, "failed to get data in spc for configmap", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "configmap", klog.ObjectRef{Namespace: pod.Namespace, Name: configMapName}, "spcps", klog.KObj(spcPodStatus))
+			errs = append(errs, fmt.Errorf("failed to get data in spc %s/%s for configmap %s, err: %+v", pod.Namespace, spc.Name, configMapName, err))
+			continue
+		}
+
+		desired := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   pod.Namespace,
+				Name:        configMapName,
+				Labels:      make(map[string]string),
+				Annotations: make(map[string]string),
+			},
+			Data:       data,
+			BinaryData: binaryData,
+		}
+		for k, v := range configMapObj.Labels {
+			desired.Labels[k] = v
+		}
+		for k, v := range configMapObj.Annotations {
+			desired.Annotations[k] = v
+		}
+		desired.Labels[SecretManagedLabel] = "true"
+		desired.Annotations[SecretProviderClassAnnotation] = spcRef
+		desired.Annotations[SecretRetentionPolicyAnnotation] = string(retentionPolicy(configMapObj.RetentionPolicy))
+
+		existing, err := r.getConfigMap(ctx, configMapName, pod.Namespace)
+		if err != nil {
+			klog.ErrorS(err, "failed to check if configmap exists", "configmap", klog.ObjectRef{Namespace: pod.Namespace, Name: configMapName}, "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+			if apierrors.IsForbidden(err) {
+				klog.Warning(SyncSecretForbiddenWarning)
+			}
+			errs = append(errs, fmt.Errorf("failed to check if configmap %s exists, err: %+v", configMapName, err))
+			continue
+		}
+
+		if existing != nil {
+			if existing.GetLabels()[SecretManagedLabel] != "true" {
+				klog.V(5).InfoS("configmap is not managed by the driver, skipping update", "configmap", klog.KObj(existing), "spc", klog.KObj(spc))
+				continue
+			}
+			changes, err := updateK8sConfigMap(ctx, r.writer, existing, desired)
+			if err != nil {
+				klog.ErrorS(err, "failed to update Kubernetes configmap", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "configmap", klog.KObj(existing), "spcps", klog.KObj(spcPodStatus))
+				r.generateEvent(pod, corev1.EventTypeWarning, configMapUpdateFailedReason, fmt.Sprintf("failed to update configmap %s/%s, err: %+v", pod.Namespace, configMapName, err))
+				errs = append(errs, fmt.Errorf("failed to update configmap %s, err: %+v", configMapName, err))
+				continue
+			}
+			if len(changes) > 0 {
+				r.generateEvent(pod, corev1.EventTypeNormal, configMapUpdatedReason, fmt.Sprintf("updated configmap %s/%s to match spc %s: %s", pod.Namespace, configMapName, spc.Name, strings.Join(changes, ", ")))
+			}
+			continue
+		}
+
+		createFn := func() (bool, error) {
+			if err := r.createK8sConfigMap(ctx, desired); err != nil {
+				klog.ErrorS(err, "failed to create Kubernetes configmap", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "configmap", klog.ObjectRef{Namespace: pod.Namespace, Name: configMapName}, "spcps", klog.KObj(spcPodStatus))
+				if apierrors.IsForbidden(err) {
+					klog.Warning(SyncSecretForbiddenWarning)
+				}
+				return false, nil
+			}
+			return true, nil
+		}
+		if err := wait.ExponentialBackoff(wait.Backoff{
+			Steps:    5,
+			Duration: 1 * time.Millisecond,
+			Factor:   1.0,
+			Jitter:   0.1,
+		}, createFn); err != nil {
+			r.generateEvent(pod, corev1.EventTypeWarning, configMapCreationFailedReason, err.Error())
+			errs = append(errs, fmt.Errorf("failed to create configmap %s, err: %+v", configMapName, err))
+		}
+	}
+	return errs
+}
+
+// createK8sConfigMap creates the K8s configmap. If a configmap with the same name already
+// exists in the namespace of the pod, the error is nil.
+func (r *SecretProviderClassPodStatusReconciler) createK8sConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	configMap = configMap.DeepCopy()
+	setAppliedKeys(configMap)
+	err := r.writer.Create(ctx, configMap)
+	if err == nil {
+		klog.InfoS("successfully created Kubernetes configmap", "configmap", klog.KObj(configMap))
+		return nil
+	}
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// getConfigMap returns the configmap with name and namespace, or nil if it doesn't exist
+func (r *SecretProviderClassPodStatusReconciler) getConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error) {
+	o := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, o)
+	if err == nil {
+		return o, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// updateK8sConfigMap converges the existing configmap managed by the driver to the desired
+// configmap in the same way as updateK8sSecret. It returns a description of the changes,
+// which is empty if the configmap is up to date.
+func updateK8sConfigMap(ctx context.Context, writer client.Writer, existing, desired *corev1.ConfigMap) ([]string, error) {
+	desired = desired.DeepCopy()
+	setAppliedKeys(desired)
+	updated := existing.DeepCopy()
+	patch := client.MergeFromWithOptions(existing.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	changes := metadataChanges(updated, existing, desired)
+	if ownerRefs, changed := mergeOwnerReferences(updated.OwnerReferences, desired.OwnerReferences); changed {
+		updated.OwnerReferences = ownerRefs
+		changes = append(changes, "owner references")
+	}
+	changes = append(changes, dataChanges(configMapContent(existing), configMapContent(desired))...)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	updated.Data = desired.Data
+	updated.BinaryData = desired.BinaryData
+
+	if err := writer.Patch(ctx, updated, patch); err != nil {
+		return nil, err
+	}
+	klog.InfoS("updated Kubernetes configmap", "configmap", klog.KObj(updated), "changes", changes)
+	return changes, nil
+}
+
+// configMapContent returns the data and binaryData of the configmap in a single map
+func configMapContent(configMap *corev1.ConfigMap) map[string][]byte {
+	content := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for k, v := range configMap.Data {
+		content[k] = []byte(v)
+	}
+	for k, v := range configMap.BinaryData {
+		content[k] = v
+	}
+	return content
+}
+
+// cleanupRemovedConfigMaps deletes or retains the configmaps synced from the spc that are no
+// longer defined in its configmap objects, in the same way as cleanupRemovedSecrets
+func (r *SecretProviderClassPodStatusReconciler) cleanupRemovedConfigMaps(ctx context.Context, pod *corev1.Pod, spc *secretsstorev1.SecretProviderClass, spcRef string) error {
+	configMapNames := make(map[string]struct{}, len(spc.Spec.ConfigMapObjects))
+	for _, configMapObj := range spc.Spec.ConfigMapObjects {
+		if configMapObj != nil {
+			configMapNames[strings.TrimSpace(configMapObj.ConfigMapName)] = struct{}{}
+		}
+	}
+
+	configMapList := &corev1.ConfigMapList{}
+	if err := r.reader.List(ctx, configMapList, client.InNamespace(pod.Namespace), client.MatchingLabels{SecretManagedLabel: "true"}); err != nil {
+		return err
+	}
+	objs := make([]client.Object, 0, len(configMapList.Items))
+	for i := range configMapList.Items {
+		objs = append(objs, &configMapList.Items[i])
+	}
+	return cleanupRemovedObjects(ctx, r.writer, r.eventRecorder, pod, spc, spcRef, syncedConfigMap, objs, configMapNames)
+}
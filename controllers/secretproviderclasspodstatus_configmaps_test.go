@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+func newConfigMap(name, namespace string, labels map[string]string, annotations map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			ResourceVersion: "73659",
+		},
+	}
+}
+
+func TestSyncConfigMaps(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "ca"), []byte("ca-bundle"), 0600)).To(Succeed())
+	files := map[string]string{"ca": filepath.Join(dir, "ca")}
+
+	managed := newConfigMap("managed", "default", map[string]string{SecretManagedLabel: "true", "other": "value"}, nil)
+	managed.Data = map[string]string{"ca.crt": "old", "removed": "value"}
+	unmanaged := newConfigMap("unmanaged", "default", nil, nil)
+	unmanaged.Data = map[string]string{"ca.crt": "old"}
+
+	client := fake.NewFakeClientWithScheme(scheme, managed, unmanaged)
+	reconciler := newReconciler(client, scheme, "node1")
+	recorder := record.NewFakeRecorder(10)
+	reconciler.eventRecorder = recorder
+
+	spc := newSecretProviderClass("spc1", "default")
+	configMapObject := func(name string) *secretsstorev1.ConfigMapObject {
+		return &secretsstorev1.ConfigMapObject{
+			ConfigMapName: name,
+			Labels:        map[string]string{"app": "test"},
+			Data:          []*secretsstorev1.SecretObjectData{{ObjectName: "ca", Key: "ca.crt"}},
+		}
+	}
+	spc.Spec.ConfigMapObjects = []*secretsstorev1.ConfigMapObject{configMapObject("created"), configMapObject("managed"), configMapObject("unmanaged")}
+
+	errs := reconciler.syncConfigMaps(context.TODO(), newPod("pod1", "default", nil), spc, newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"), "SecretProviderClass/spc1", files)
+	g.Expect(errs).To(BeEmpty())
+
+	// the configmap is created with the labels and annotations of the synced objects
+	created, err := reconciler.getConfigMap(context.TODO(), "created", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).NotTo(BeNil())
+	g.Expect(created.Data).To(Equal(map[string]string{"ca.crt": "ca-bundle"}))
+	g.Expect(created.Labels).To(HaveKeyWithValue(SecretManagedLabel, "true"))
+	g.Expect(created.Labels).To(HaveKeyWithValue("app", "test"))
+	g.Expect(created.Annotations).To(HaveKeyWithValue(SecretProviderClassAnnotation, "SecretProviderClass/spc1"))
+	g.Expect(created.Annotations).To(HaveKeyWithValue(SecretRetentionPolicyAnnotation, string(secretsstorev1.SecretRetentionPolicyDelete)))
+
+	// the managed configmap is converged and keeps the labels set by others
+	updated, err := reconciler.getConfigMap(context.TODO(), "managed", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Data).To(Equal(map[string]string{"ca.crt": "ca-bundle"}))
+	g.Expect(updated.Labels).To(HaveKeyWithValue("other", "value"))
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	// the configmap not managed by the driver is unchanged
+	skipped, err := reconciler.getConfigMap(context.TODO(), "unmanaged", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(skipped.Data).To(Equal(map[string]string{"ca.crt": "old"}))
+
+	// no changes when the configmaps are up to date
+	errs = reconciler.syncConfigMaps(context.TODO(), newPod("pod1", "default", nil), spc, newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"), "SecretProviderClass/spc1", files)
+	g.Expect(errs).To(BeEmpty())
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	// objects that aren't mounted are reported
+	spc.Spec.ConfigMapObjects[0].Data[0].ObjectName = "missing"
+	errs = reconciler.syncConfigMaps(context.TODO(), newPod("pod1", "default", nil), spc, newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"), "SecretProviderClass/spc1", files)
+	g.Expect(errs).To(HaveLen(1))
+}
+
+func TestCleanupRemovedConfigMaps(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	synced := func(name string, policy secretsstorev1.SecretRetentionPolicy) *v1.ConfigMap {
+		configMap := newConfigMap(name, "default", map[string]string{SecretManagedLabel: "true"}, map[string]string{
+			SecretProviderClassAnnotation:   "SecretProviderClass/spc1",
+			SecretRetentionPolicyAnnotation: string(policy),
+		})
+		configMap.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "uid1"}}
+		return configMap
+	}
+	client := fake.NewFakeClientWithScheme(scheme,
+		synced("configmap1", secretsstorev1.SecretRetentionPolicyDelete),
+		synced("removed", secretsstorev1.SecretRetentionPolicyDelete),
+		synced("retained", secretsstorev1.SecretRetentionPolicyRetain),
+	)
+	reconciler := newReconciler(client, scheme, "node1")
+	recorder := record.NewFakeRecorder(10)
+	reconciler.eventRecorder = recorder
+
+	spc := newSecretProviderClass("spc1", "default")
+	spc.Spec.ConfigMapObjects = []*secretsstorev1.ConfigMapObject{{ConfigMapName: "configmap1"}}
+	err = reconciler.cleanupRemovedConfigMaps(context.TODO(), newPod("pod1", "default", nil), spc, "SecretProviderClass/spc1")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	configMap, err := reconciler.getConfigMap(context.TODO(), "configmap1", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configMap).NotTo(BeNil())
+
+	configMap, err = reconciler.getConfigMap(context.TODO(), "removed", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configMap).To(BeNil())
+
+	configMap, err = reconciler.getConfigMap(context.TODO(), "retained", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configMap).NotTo(BeNil())
+	g.Expect(configMap.Labels).NotTo(HaveKey(SecretManagedLabel))
+	g.Expect(configMap.Annotations).NotTo(HaveKey(SecretProviderClassAnnotation))
+	g.Expect(configMap.OwnerReferences).To(BeEmpty())
+	g.Expect(recorder.Events).To(HaveLen(2))
+}
+
+func TestPatcherForConfigMaps(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spc := newSecretProviderClass("spc1", "default")
+	spc.Spec.ConfigMapObjects = []*secretsstorev1.ConfigMapObject{{ConfigMapName: "configmap1"}}
+	initObjects := []runtime.Object{
+		newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"),
+		spc,
+		newPod("pod1", "default", nil),
+		newConfigMap("configmap1", "default", nil, nil),
+	}
+	client := fake.NewFakeClientWithScheme(scheme, initObjects...)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	err = reconciler.Patcher(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// check the spcps has been added as owner to the configmap
+	configMap := &v1.ConfigMap{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: "configmap1", Namespace: "default"}, configMap)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	g.Expect(configMap.OwnerReferences[0].Kind).To(Equal("SecretProviderClassPodStatus"))
+	g.Expect(configMap.OwnerReferences[0].Name).To(Equal("pod1-default-spc1"))
+}
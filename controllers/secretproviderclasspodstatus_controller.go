@@ -17,46 +17,78 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned/scheme"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/k8sutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/policyutil"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/secretutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcpsutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
 	SecretManagedLabel         = "secrets-store.csi.k8s.io/managed"
 	SecretUsedLabel            = "secrets-store.csi.k8s.io/used"
 	secretCreationFailedReason = "FailedToCreateSecret"
+	secretUpdateFailedReason   = "FailedToUpdateSecret"
+	secretUpdatedReason        = "SecretUpdated"
+	secretDeletedReason        = "SecretDeleted"
+	secretRetainedReason       = "SecretRetained"
+	secretCleanupFailedReason  = "FailedToCleanupSecret"
+
+	// SecretProviderClassAnnotation is set on the secrets synced by the driver to <kind>/<name> of the
+	// secret provider class they were synced from
+	SecretProviderClassAnnotation = "secrets-store.csi.k8s.io/secret-provider-class"
+	// SecretRetentionPolicyAnnotation is set on the secrets synced by the driver to the retention policy
+	// of the secret object, which is needed after the secret object is removed from the secret provider class
+	SecretRetentionPolicyAnnotation = "secrets-store.csi.k8s.io/retention-policy"
+	// AppliedLabelsAnnotation is set on the secrets synced by the driver to the comma separated keys of the
+	// labels set by the driver, so the labels removed from the secret object can be removed from the secret
+	AppliedLabelsAnnotation = "secrets-store.csi.k8s.io/applied-labels"
+	// AppliedAnnotationsAnnotation is set on the secrets synced by the driver to the comma separated keys of
+	// the annotations set by the driver, in the same way as AppliedLabelsAnnotation
+	AppliedAnnotationsAnnotation = "secrets-store.csi.k8s.io/applied-annotations"
 
 	SyncSecretForbiddenWarning = "The secret operation failed with forbidden error. If you installed the CSI driver using helm, ensure syncSecret.enabled=true is set."
 )
@@ -70,6 +102,9 @@ type SecretProviderClassPodStatusReconciler struct {
 	reader        client.Reader
 	writer        client.Writer
 	eventRecorder record.EventRecorder
+	// apiReader reads the configmaps and secrets in the parametersFrom of the spc, which aren't
+	// managed by the driver so they're not in the cache
+	apiReader client.Reader
 }
 
 // New creates a new SecretProviderClassPodStatusReconciler
@@ -86,6 +121,7 @@ func New(mgr manager.Manager, nodeID string) (*SecretProviderClassPodStatusRecon
 		nodeID:        nodeID,
 		reader:        mgr.GetCache(),
 		writer:        mgr.GetClient(),
+		apiReader:     mgr.GetAPIReader(),
 		eventRecorder: recorder,
 	}, nil
 }
@@ -111,9 +147,10 @@ func (r *SecretProviderClassPodStatusReconciler) Patcher(ctx context.Context) er
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	spcPodStatusList := &v1alpha1.SecretProviderClassPodStatusList{}
-	spcMap := make(map[string]v1alpha1.SecretProviderClass)
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	spcMap := make(map[string]secretsstorev1.SecretProviderClass)
 	secretOwnerMap := make(map[types.NamespacedName][]metav1.OwnerReference)
+	configMapOwnerMap := make(map[types.NamespacedName][]metav1.OwnerReference)
 	// get a list of all spc pod status that belong to the node
 	err := r.reader.List(ctx, spcPodStatusList, r.ListOptionsLabelSelector())
 	if err != nil {
@@ -123,16 +160,17 @@ func (r *SecretProviderClassPodStatusReconciler) Patcher(ctx context.Context) er
 	spcPodStatuses := spcPodStatusList.Items
 	for i := range spcPodStatuses {
 		spcName := spcPodStatuses[i].Status.SecretProviderClassName
-		spc := &v1alpha1.SecretProviderClass{}
+		spcKind := spcPodStatuses[i].Status.SecretProviderClassKind
+		spc := &secretsstorev1.SecretProviderClass{}
 		namespace := spcPodStatuses[i].Namespace
 
-		if val, exists := spcMap[namespace+"/"+spcName]; exists {
+		if val, exists := spcMap[spcKind+"/"+namespace+"/"+spcName]; exists {
 			spc = &val
 		} else {
-			if err := r.reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: spcName}, spc); err != nil {
+			if spc, err = spcutil.GetSecretProviderClass(ctx, r.reader, spcKind, spcName, namespace); err != nil {
 				return fmt.Errorf("failed to get spc %s, err: %+v", spcName, err)
 			}
-			spcMap[namespace+"/"+spcName] = *spc
+			spcMap[spcKind+"/"+namespace+"/"+spcName] = *spc
 		}
 		// get the pod and check if the pod has a owner reference
 		pod := &v1.Pod{}
@@ -176,11 +214,15 @@ func (r *SecretProviderClassPodStatusReconciler) Patcher(ctx context.Context) er
 				secretOwnerMap[key] = ownerRefs
 			}
 		}
+		for _, configMap := range spc.Spec.ConfigMapObjects {
+			key := types.NamespacedName{Name: configMap.ConfigMapName, Namespace: namespace}
+			configMapOwnerMap[key] = append(configMapOwnerMap[key], ownerRefs...)
+		}
 	}
 
 	for secret, owners := range secretOwnerMap {
 		patchFn := func() (bool, error) {
-			if err := r.patchSecretWithOwnerRef(ctx, secret.Name, secret.Namespace, owners...); err != nil {
+			if err := r.patchWithOwnerRef(ctx, &corev1.Secret{}, secret.Name, secret.Namespace, owners...); err != nil {
 				if !apierrors.IsConflict(err) || !apierrors.IsTimeout(err) {
 					klog.ErrorS(err, "failed to set owner ref for secret", "secret", klog.ObjectRef{Namespace: secret.Namespace, Name: secret.Name})
 				}
@@ -203,6 +245,29 @@ func (r *SecretProviderClassPodStatusReconciler) Patcher(ctx context.Context) er
 		}
 	}
 
+	for configMap, owners := range configMapOwnerMap {
+		patchFn := func() (bool, error) {
+			if err := r.patchWithOwnerRef(ctx, &corev1.ConfigMap{}, configMap.Name, configMap.Namespace, owners...); err != nil {
+				if !apierrors.IsConflict(err) || !apierrors.IsTimeout(err) {
+					klog.ErrorS(err, "failed to set owner ref for configmap", "configmap", klog.ObjectRef{Namespace: configMap.Namespace, Name: configMap.Name})
+				}
+				if apierrors.IsForbidden(err) {
+					klog.Warning(SyncSecretForbiddenWarning)
+				}
+				return false, nil
+			}
+			return true, nil
+		}
+		if err := wait.ExponentialBackoff(wait.Backoff{
+			Steps:    5,
+			Duration: 1 * time.Millisecond,
+			Factor:   1.0,
+			Jitter:   0.1,
+		}, patchFn); err != nil {
+			return err
+		}
+	}
+
 	klog.V(10).Infof("patcher completed")
 	return nil
 }
@@ -210,13 +275,16 @@ func (r *SecretProviderClassPodStatusReconciler) Patcher(ctx context.Context) er
 // ListOptionsLabelSelector returns a ListOptions with a label selector for node name.
 func (r *SecretProviderClassPodStatusReconciler) ListOptionsLabelSelector() client.ListOption {
 	return client.MatchingLabels(map[string]string{
-		v1alpha1.InternalNodeLabel: r.nodeID,
+		secretsstorev1.InternalNodeLabel: r.nodeID,
 	})
 }
 
 // +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=secretproviderclasspodstatuses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=secretproviderclasspodstatuses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=secretproviderclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=clustersecretproviderclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=secretsstorepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -226,7 +294,7 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 
 	klog.InfoS("reconcile started", "spcps", req.NamespacedName.String())
 
-	spcPodStatus := &v1alpha1.SecretProviderClassPodStatus{}
+	spcPodStatus := &secretsstorev1.SecretProviderClassPodStatus{}
 	if err := r.reader.Get(ctx, req.NamespacedName, spcPodStatus); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.InfoS("reconcile complete", "spcps", req.NamespacedName.String())
@@ -255,8 +323,8 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 	}
 
 	spcName := spcPodStatus.Status.SecretProviderClassName
-	spc := &v1alpha1.SecretProviderClass{}
-	if err := r.reader.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: spcName}, spc); err != nil {
+	spc, err := spcutil.GetSecretProviderClass(ctx, r.reader, spcPodStatus.Status.SecretProviderClassKind, spcName, req.Namespace)
+	if err != nil {
 		klog.ErrorS(err, "failed to get spc", "spc", spcName)
 		if apierrors.IsNotFound(err) {
 			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
@@ -264,13 +332,51 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
-	if len(spc.Spec.SecretObjects) == 0 {
-		klog.InfoS("no secret objects defined for spc, nothing to reconcile", "spc", klog.KObj(spc), "spcps", klog.KObj(spcPodStatus))
+	spcKind := spcPodStatus.Status.SecretProviderClassKind
+	if spcKind == "" {
+		spcKind = secretsstorev1.SecretProviderClassKind
+	}
+	spcRef := spcKind + "/" + spcName
+
+	errs := make([]error, 0)
+	// remove the secrets synced from the secret objects that are no longer in the spc
+	if err := r.cleanupRemovedSecrets(ctx, pod, spc, spcRef); err != nil {
+		klog.ErrorS(err, "failed to clean up secrets removed from spc", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+		errs = append(errs, fmt.Errorf("failed to clean up secrets removed from spc %s/%s, err: %+v", req.Namespace, spcName, err))
+	}
+
+	if err := r.cleanupRemovedConfigMaps(ctx, pod, spc, spcRef); err != nil {
+		klog.ErrorS(err, "failed to clean up configmaps removed from spc", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+		errs = append(errs, fmt.Errorf("failed to clean up configmaps removed from spc %s/%s, err: %+v", req.Namespace, spcName, err))
+	}
+
+	if len(spc.Spec.SecretObjects) == 0 && len(spc.Spec.ConfigMapObjects) == 0 {
+		if len(errs) > 0 {
+			r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to sync one or more secrets, err: %+v", errs))
+			return ctrl.Result{Requeue: true}, nil
+		}
+		klog.InfoS("no secret or configmap objects defined for spc, nothing to reconcile", "spc", klog.KObj(spc), "spcps", klog.KObj(spcPodStatus))
 		return ctrl.Result{}, nil
 	}
 
+	// enforce the secrets store policies in the namespace of the pod before the objects are synced,
+	// as a policy can be created or changed after the volume was mounted
+	if err := r.enforcePolicies(ctx, pod, spc); err != nil {
+		var violation *policyutil.ViolationError
+		if errors.As(err, &violation) {
+			klog.ErrorS(err, "secrets store policy doesn't allow the sync", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+			r.generateEvent(pod, corev1.EventTypeWarning, internalerrors.SecretsStorePolicyViolation, err.Error())
+			r.updateSecretsSyncedCondition(ctx, spcPodStatus, err)
+			// the policies aren't watched, so the sync is checked again with the periodic requeue
+			return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+		klog.ErrorS(err, "failed to enforce secrets store policies", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+		r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to enforce secrets store policies, err: %+v", err))
+		return ctrl.Result{}, err
+	}
+
 	// determine which pod volume this is associated with
-	podVol := k8sutil.SPCVolume(pod, spc.Name)
+	podVol := k8sutil.SPCVolumeForKind(pod, spcPodStatus.Status.SecretProviderClassKind, spc.Name)
 	if podVol == nil {
 		return ctrl.Result{}, fmt.Errorf("failed to find secret provider class pod status volume for pod %s/%s", req.Namespace, spcPodStatus.Status.PodName)
 	}
@@ -287,9 +393,18 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 	if err != nil {
 		r.generateEvent(pod, corev1.EventTypeWarning, secretCreationFailedReason, fmt.Sprintf("failed to get mounted files, err: %+v", err))
 		klog.ErrorS(err, "failed to get mounted files", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
+		r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to get mounted files, err: %+v", err))
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, err
 	}
-	errs := make([]error, 0)
+	// only the node with the newest mounted contents converges the data of the existing secrets
+	syncSource := true
+	if len(spc.Spec.SecretObjects) > 0 {
+		if syncSource, err = r.isSyncSource(ctx, spcPodStatus, spcKind); err != nil {
+			klog.ErrorS(err, "failed to list spc pod statuses for spc", "spc", klog.KObj(spc), "spcps", klog.KObj(spcPodStatus))
+			r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to list spc pod statuses for spc %s/%s, err: %+v", req.Namespace, spcName, err))
+			return ctrl.Result{}, err
+		}
+	}
 	for _, secretObj := range spc.Spec.SecretObjects {
 		secretName := strings.TrimSpace(secretObj.SecretName)
 
@@ -298,7 +413,34 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 			errs = append(errs, fmt.Errorf("failed to validate secret object in spc %s/%s, err: %+v", spc.Namespace, spc.Name, err))
 			continue
 		}
-		exists, err := r.secretExists(ctx, secretName, req.Namespace)
+		secretType := secretutil.GetSecretType(strings.TrimSpace(secretObj.Type))
+
+		datamap := make(map[string][]byte)
+		if datamap, err = secretutil.GetSecretData(secretObj.Data, secretType, files, spc.Spec.Templates); err != nil {
+			r.generateEvent(pod, corev1.EventTypeWarning, secretCreationFailedReason, fmt.Sprintf("failed to get data in spc %s/%s for secret %s, err: %+v", req.Namespace, spcName, secretName, err))
+			klog.ErrorS(err, "failed to get data in spc for secret", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "secret", klog.ObjectRef{Namespace: req.Namespace, Name: secretName}, "spcps", klog.KObj(spcPodStatus))
+			errs = append(errs, fmt.Errorf("failed to get data in spc %s/%s for secret %s, err: %+v", req.Namespace, spcName, secretName, err))
+			continue
+		}
+
+		labelsMap := make(map[string]string)
+		for k, v := range secretObj.Labels {
+			labelsMap[k] = v
+		}
+		annotationsMap := make(map[string]string)
+		for k, v := range secretObj.Annotations {
+			annotationsMap[k] = v
+		}
+		// Set secrets-store.csi.k8s.io/managed=true label on the secret that's created and managed
+		// by the secrets-store-csi-driver. This label will be used to perform a filtered list watch
+		// only on secrets created and managed by the driver
+		labelsMap[SecretManagedLabel] = "true"
+		// record the spc and the retention policy, so the secret can be cleaned up
+		// when the secret object is removed from the spc
+		annotationsMap[SecretProviderClassAnnotation] = spcRef
+		annotationsMap[SecretRetentionPolicyAnnotation] = string(retentionPolicy(secretObj.RetentionPolicy))
+
+		existing, err := r.getSecret(ctx, secretName, req.Namespace)
 		if err != nil {
 			klog.ErrorS(err, "failed to check if secret exists", "secret", klog.ObjectRef{Namespace: req.Namespace, Name: secretName}, "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
 			// syncSecret.enabled is set to false by default in the helm chart for installing the driver in v0.0.23+
@@ -310,46 +452,55 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 			continue
 		}
 
-		var funcs []func() (bool, error)
-
-		if !exists {
-			secretType := secretutil.GetSecretType(strings.TrimSpace(secretObj.Type))
-
-			datamap := make(map[string][]byte)
-			if datamap, err = secretutil.GetSecretData(secretObj.Data, secretType, files); err != nil {
-				r.generateEvent(pod, corev1.EventTypeWarning, secretCreationFailedReason, fmt.Sprintf("failed to get data in spc %s/%s for secret %s, err: %+v", req.Namespace, spcName, secretName, err))
-				klog.ErrorS(err, "failed to get data in spc for secret", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "secret", klog.ObjectRef{Namespace: req.Namespace, Name: secretName}, "spcps", klog.KObj(spcPodStatus))
-				errs = append(errs, fmt.Errorf("failed to get data in spc %s/%s for secret %s, err: %+v", req.Namespace, spcName, secretName, err))
+		// converge the existing secret to the secret object in the spc if it's managed by the driver
+		if existing != nil {
+			if existing.GetLabels()[SecretManagedLabel] != "true" {
+				klog.V(5).InfoS("secret is not managed by the driver, skipping update", "secret", klog.KObj(existing), "spc", klog.KObj(spc))
 				continue
 			}
-
-			labelsMap := make(map[string]string)
-			if secretObj.Labels != nil {
-				labelsMap = secretObj.Labels
+			desired := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   req.Namespace,
+					Name:        secretName,
+					Labels:      labelsMap,
+					Annotations: annotationsMap,
+				},
+				Type: secretType,
+				Data: datamap,
 			}
-			annotationsMap := make(map[string]string)
-			if secretObj.Annotations != nil {
-				annotationsMap = secretObj.Annotations
+			if !syncSource {
+				// the data and the type, which needs the data for the new type, are converged by the
+				// node of the spc pod status with the newest mounted contents
+				klog.V(5).InfoS("spc pod status doesn't have the newest contents, skipping secret data update", "secret", klog.KObj(existing), "spc", klog.KObj(spc), "spcps", klog.KObj(spcPodStatus))
+				desired.Type = existing.Type
+				desired.Data = existing.Data
 			}
-			// Set secrets-store.csi.k8s.io/managed=true label on the secret that's created and managed
-			// by the secrets-store-csi-driver. This label will be used to perform a filtered list watch
-			// only on secrets created and managed by the driver
-			labelsMap[SecretManagedLabel] = "true"
-
-			createFn := func() (bool, error) {
-				if err := r.createK8sSecret(ctx, secretName, req.Namespace, datamap, labelsMap, annotationsMap, secretType); err != nil {
-					klog.ErrorS(err, "failed to create Kubernetes secret", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "secret", klog.ObjectRef{Namespace: req.Namespace, Name: secretName}, "spcps", klog.KObj(spcPodStatus))
-					// syncSecret.enabled is set to false by default in the helm chart for installing the driver in v0.0.23+
-					// that would result in a forbidden error, so generate a warning that can be helpful for debugging
-					if apierrors.IsForbidden(err) {
-						klog.Warning(SyncSecretForbiddenWarning)
-					}
-					return false, nil
+			changes, err := updateK8sSecret(ctx, r.writer, existing, desired)
+			if err != nil {
+				klog.ErrorS(err, "failed to update Kubernetes secret", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "secret", klog.KObj(existing), "spcps", klog.KObj(spcPodStatus))
+				r.generateEvent(pod, corev1.EventTypeWarning, secretUpdateFailedReason, fmt.Sprintf("failed to update secret %s/%s, err: %+v", req.Namespace, secretName, err))
+				errs = append(errs, fmt.Errorf("failed to update secret %s, err: %+v", secretName, err))
+				continue
+			}
+			if len(changes) > 0 {
+				r.generateEvent(pod, corev1.EventTypeNormal, secretUpdatedReason, fmt.Sprintf("updated secret %s/%s to match spc %s: %s", req.Namespace, secretName, spcName, strings.Join(changes, ", ")))
+			}
+			continue
+		}
+
+		createFn := func() (bool, error) {
+			if err := r.createK8sSecret(ctx, secretName, req.Namespace, datamap, labelsMap, annotationsMap, secretType); err != nil {
+				klog.ErrorS(err, "failed to create Kubernetes secret", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "secret", klog.ObjectRef{Namespace: req.Namespace, Name: secretName}, "spcps", klog.KObj(spcPodStatus))
+				// syncSecret.enabled is set to false by default in the helm chart for installing the driver in v0.0.23+
+				// that would result in a forbidden error, so generate a warning that can be helpful for debugging
+				if apierrors.IsForbidden(err) {
+					klog.Warning(SyncSecretForbiddenWarning)
 				}
-				return true, nil
+				return false, nil
 			}
-			funcs = append(funcs, createFn)
+			return true, nil
 		}
+		funcs := []func() (bool, error){createFn}
 
 		for _, f := range funcs {
 			if err := wait.ExponentialBackoff(wait.Backoff{
@@ -359,14 +510,19 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 				Jitter:   0.1,
 			}, f); err != nil {
 				r.generateEvent(pod, corev1.EventTypeWarning, secretCreationFailedReason, err.Error())
+				r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to create secret %s, err: %+v", secretName, err))
 				return ctrl.Result{RequeueAfter: 5 * time.Second}, err
 			}
 		}
 	}
 
+	errs = append(errs, r.syncConfigMaps(ctx, pod, spc, spcPodStatus, spcRef, files)...)
+
 	if len(errs) > 0 {
+		r.updateSecretsSyncedCondition(ctx, spcPodStatus, fmt.Errorf("failed to sync one or more secrets, err: %+v", errs))
 		return ctrl.Result{Requeue: true}, nil
 	}
+	r.updateSecretsSyncedCondition(ctx, spcPodStatus, nil)
 
 	klog.InfoS("reconcile complete", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "spcps", klog.KObj(spcPodStatus))
 	// requeue the spc pod status again after 5mins to check if secret and ownerRef exists
@@ -375,18 +531,80 @@ func (r *SecretProviderClassPodStatusReconciler) Reconcile(ctx context.Context,
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
+// SetupWithManager sets up the controller to reconcile the spc pod statuses on the node. The spc pod
+// statuses are also reconciled when the spec of the secret provider class they reference changes,
+// so the synced secrets are updated without waiting for the periodic requeue.
 func (r *SecretProviderClassPodStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.SecretProviderClassPodStatus{}).
-		WithEventFilter(r.belongsToNodePredicate()).
+		For(&secretsstorev1.SecretProviderClassPodStatus{}, builder.WithPredicates(r.belongsToNodePredicate())).
+		Watches(&source.Kind{Type: &secretsstorev1.SecretProviderClass{}}, handler.EnqueueRequestsFromMapFunc(r.spcPodStatusesForSPC), builder.WithPredicates(specChangedPredicate())).
+		Watches(&source.Kind{Type: &secretsstorev1.ClusterSecretProviderClass{}}, handler.EnqueueRequestsFromMapFunc(r.spcPodStatusesForSPC), builder.WithPredicates(specChangedPredicate())).
 		Complete(r)
 }
 
+// spcPodStatusesForSPC maps the secret provider class or cluster secret provider class to the
+// spc pod statuses on the node that reference it
+func (r *SecretProviderClassPodStatusReconciler) spcPodStatusesForSPC(obj client.Object) []reconcile.Request {
+	kind := secretsstorev1.SecretProviderClassKind
+	opts := []client.ListOption{r.ListOptionsLabelSelector()}
+	if _, ok := obj.(*secretsstorev1.ClusterSecretProviderClass); ok {
+		kind = secretsstorev1.ClusterSecretProviderClassKind
+	} else {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	if err := r.reader.List(context.Background(), spcPodStatusList, opts...); err != nil {
+		klog.ErrorS(err, "failed to list spc pod statuses", "kind", kind, "name", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, spcps := range spcPodStatusList.Items {
+		spcKind := spcps.Status.SecretProviderClassKind
+		if spcKind == "" {
+			spcKind = secretsstorev1.SecretProviderClassKind
+		}
+		if spcKind != kind || spcps.Status.SecretProviderClassName != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: spcps.Namespace, Name: spcps.Name}})
+	}
+	return requests
+}
+
+// specChangedPredicate only processes updates to the secret provider class that change the spec.
+// The status of the secret provider class is updated when the spc pod statuses change, which
+// doesn't change the synced secrets.
+func specChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			switch oldObj := e.ObjectOld.(type) {
+			case *secretsstorev1.SecretProviderClass:
+				newObj, ok := e.ObjectNew.(*secretsstorev1.SecretProviderClass)
+				return !ok || !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec)
+			case *secretsstorev1.ClusterSecretProviderClass:
+				newObj, ok := e.ObjectNew.(*secretsstorev1.ClusterSecretProviderClass)
+				return !ok || !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec)
+			}
+			return true
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 // belongsToNodePredicate defines predicates for handlers
 func (r *SecretProviderClassPodStatusReconciler) belongsToNodePredicate() predicate.Funcs {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return r.processIfBelongsToNode(e.ObjectNew)
+			return r.processIfBelongsToNode(e.ObjectNew) && !onlyStatusConditionsChanged(e.ObjectOld, e.ObjectNew)
 		},
 		CreateFunc: func(e event.CreateEvent) bool {
 			return r.processIfBelongsToNode(e.Object)
@@ -403,7 +621,7 @@ func (r *SecretProviderClassPodStatusReconciler) belongsToNodePredicate() predic
 // processIfBelongsToNode determines if the secretproviderclasspodstatus belongs to the node based on the
 // internal.secrets-store.csi.k8s.io/node-name: <node name> label. If belongs to node, then the spcps is processed.
 func (r *SecretProviderClassPodStatusReconciler) processIfBelongsToNode(objMeta metav1.Object) bool {
-	node, ok := objMeta.GetLabels()[v1alpha1.InternalNodeLabel]
+	node, ok := objMeta.GetLabels()[secretsstorev1.InternalNodeLabel]
 	if !ok {
 		return false
 	}
@@ -413,6 +631,57 @@ func (r *SecretProviderClassPodStatusReconciler) processIfBelongsToNode(objMeta
 	return true
 }
 
+// onlyStatusConditionsChanged returns true if the update to the secretproviderclasspodstatus only changed
+// the status conditions and timestamps. These are written by the driver to record the outcome of mount,
+// rotation and sync, and shouldn't trigger another reconcile.
+func onlyStatusConditionsChanged(oldObj, newObj client.Object) bool {
+	oldSPCPS, ok := oldObj.(*secretsstorev1.SecretProviderClassPodStatus)
+	if !ok {
+		return false
+	}
+	newSPCPS, ok := newObj.(*secretsstorev1.SecretProviderClassPodStatus)
+	if !ok {
+		return false
+	}
+	oldStatus, newStatus := oldSPCPS.Status.DeepCopy(), newSPCPS.Status.DeepCopy()
+	oldStatus.Conditions, newStatus.Conditions = nil, nil
+	oldStatus.LastAttemptTime, newStatus.LastAttemptTime = nil, nil
+	oldStatus.LastSuccessfulRotationTime, newStatus.LastSuccessfulRotationTime = nil, nil
+	return equality.Semantic.DeepEqual(oldStatus, newStatus) &&
+		equality.Semantic.DeepEqual(oldSPCPS.GetLabels(), newSPCPS.GetLabels()) &&
+		equality.Semantic.DeepEqual(oldSPCPS.GetOwnerReferences(), newSPCPS.GetOwnerReferences())
+}
+
+// enforcePolicies checks the spc and the parameters sent to the provider for the pod against the
+// secrets store policies in the namespace of the pod, in the same way as the mount
+func (r *SecretProviderClassPodStatusReconciler) enforcePolicies(ctx context.Context, pod *v1.Pod, spc *secretsstorev1.SecretProviderClass) error {
+	parameters, err := k8sutil.ExpandParameters(spc.Spec.Parameters, pod)
+	if err != nil {
+		return err
+	}
+	if err = spcutil.AddParametersFrom(ctx, spcutil.NewParametersSource(r.apiReader), spc, parameters); err != nil {
+		return err
+	}
+	return policyutil.Enforce(ctx, r.reader, pod.Namespace, spc, parameters)
+}
+
+// updateSecretsSyncedCondition sets the SecretsSynced condition on the spc pod status and
+// patches the spc pod status if the condition changed
+func (r *SecretProviderClassPodStatusReconciler) updateSecretsSyncedCondition(ctx context.Context, spcPodStatus *secretsstorev1.SecretProviderClassPodStatus, syncErr error) {
+	reason := internalerrors.FailedToSyncSecret
+	var violation *policyutil.ViolationError
+	if errors.As(syncErr, &violation) {
+		reason = internalerrors.SecretsStorePolicyViolation
+	}
+	patch := client.MergeFromWithOptions(spcPodStatus.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if !spcpsutil.SetCondition(spcPodStatus, secretsstorev1.ConditionTypeSecretsSynced, reason, syncErr) {
+		return
+	}
+	if err := r.writer.Patch(ctx, spcPodStatus, patch); err != nil {
+		klog.ErrorS(err, "failed to update secrets synced condition", "spcps", klog.KObj(spcPodStatus))
+	}
+}
+
 // createK8sSecret creates K8s secret with data from mounted files
 // If a secret with the same name already exists in the namespace of the pod, the error is nil.
 func (r *SecretProviderClassPodStatusReconciler) createK8sSecret(ctx context.Context, name, namespace string, datamap map[string][]byte, labelsmap map[string]string, annotationsmap map[string]string, secretType corev1.SecretType) error {
@@ -426,6 +695,7 @@ func (r *SecretProviderClassPodStatusReconciler) createK8sSecret(ctx context.Con
 		Type: secretType,
 		Data: datamap,
 	}
+	setAppliedKeys(secret)
 
 	err := r.writer.Create(ctx, secret)
 	if err == nil {
@@ -440,48 +710,178 @@ func (r *SecretProviderClassPodStatusReconciler) createK8sSecret(ctx context.Con
 
 // patchSecretWithOwnerRef patches the secret owner reference with the spc pod status
 func (r *SecretProviderClassPodStatusReconciler) patchSecretWithOwnerRef(ctx context.Context, name, namespace string, ownerRefs ...metav1.OwnerReference) error {
-	secret := &corev1.Secret{}
-	secretKey := types.NamespacedName{
+	return r.patchWithOwnerRef(ctx, &corev1.Secret{}, name, namespace, ownerRefs...)
+}
+
+// patchWithOwnerRef adds the owner references that aren't set yet to the secret or configmap
+// synced by the driver. obj is the empty object of the kind to patch.
+func (r *SecretProviderClassPodStatusReconciler) patchWithOwnerRef(ctx context.Context, obj client.Object, name, namespace string, ownerRefs ...metav1.OwnerReference) error {
+	key := types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}
-	if err := r.Client.Get(ctx, secretKey, secret); err != nil {
+	if err := r.Client.Get(ctx, key, obj); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(5).InfoS("secret not found for patching", "secret", klog.ObjectRef{Namespace: namespace, Name: name})
+			klog.V(5).InfoS("object not found for patching", "object", klog.ObjectRef{Namespace: namespace, Name: name})
 			return nil
 		}
 		return err
 	}
 
-	patch := client.MergeFromWithOptions(secret.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
 	needsPatch := false
 
-	secretOwnerRefs := secret.GetOwnerReferences()
-	secretOwnerMap := make(map[string]types.UID)
-	for _, or := range secretOwnerRefs {
-		secretOwnerMap[or.Name] = or.UID
+	objOwnerRefs := obj.GetOwnerReferences()
+	objOwnerMap := make(map[string]types.UID)
+	for _, or := range objOwnerRefs {
+		objOwnerMap[or.Name] = or.UID
 	}
 
 	for i := range ownerRefs {
-		if _, exists := secretOwnerMap[ownerRefs[i].Name]; exists {
+		if _, exists := objOwnerMap[ownerRefs[i].Name]; exists {
 			continue
 		}
 		// add to map for tracking
-		secretOwnerMap[ownerRefs[i].Name] = ownerRefs[i].UID
+		objOwnerMap[ownerRefs[i].Name] = ownerRefs[i].UID
 		needsPatch = true
 		klog.V(5).Infof("Adding %s/%s as owner ref for %s/%s", ownerRefs[i].APIVersion, ownerRefs[i].Name, namespace, name)
-		secretOwnerRefs = append(secretOwnerRefs, ownerRefs[i])
+		objOwnerRefs = append(objOwnerRefs, ownerRefs[i])
 	}
 
 	if needsPatch {
-		secret.SetOwnerReferences(secretOwnerRefs)
-		return r.writer.Patch(ctx, secret, patch)
+		obj.SetOwnerReferences(objOwnerRefs)
+		return r.writer.Patch(ctx, obj, patch)
+	}
+	return nil
+}
+
+// syncedKind describes a kind of object synced from the secret objects or configmap objects
+// in the spc, which is used in the events generated when the objects are cleaned up
+type syncedKind struct {
+	name                string
+	deletedReason       string
+	retainedReason      string
+	cleanupFailedReason string
+}
+
+var (
+	syncedSecret    = syncedKind{name: "secret", deletedReason: secretDeletedReason, retainedReason: secretRetainedReason, cleanupFailedReason: secretCleanupFailedReason}
+	syncedConfigMap = syncedKind{name: "configmap", deletedReason: configMapDeletedReason, retainedReason: configMapRetainedReason, cleanupFailedReason: configMapCleanupFailedReason}
+)
+
+// cleanupRemovedSecrets deletes or retains the secrets synced from the spc that are no longer
+// defined in its secret objects, based on the retention policy recorded on the secret. Retained
+// secrets are no longer managed by the driver, so the managed label and owner references are removed.
+func (r *SecretProviderClassPodStatusReconciler) cleanupRemovedSecrets(ctx context.Context, pod *v1.Pod, spc *secretsstorev1.SecretProviderClass, spcRef string) error {
+	secretNames := make(map[string]struct{}, len(spc.Spec.SecretObjects))
+	for _, secretObj := range spc.Spec.SecretObjects {
+		if secretObj != nil {
+			secretNames[strings.TrimSpace(secretObj.SecretName)] = struct{}{}
+		}
+	}
+
+	secretList := &v1.SecretList{}
+	if err := r.reader.List(ctx, secretList, client.InNamespace(pod.Namespace), client.MatchingLabels{SecretManagedLabel: "true"}); err != nil {
+		return err
+	}
+	objs := make([]client.Object, 0, len(secretList.Items))
+	for i := range secretList.Items {
+		objs = append(objs, &secretList.Items[i])
+	}
+	return cleanupRemovedObjects(ctx, r.writer, r.eventRecorder, pod, spc, spcRef, syncedSecret, objs, secretNames)
+}
+
+// cleanupRemovedObjects deletes or retains the objects synced from the spc whose name isn't in names.
+// The events are generated for eventObj, which is the pod or the spc the objects are synced for.
+func cleanupRemovedObjects(ctx context.Context, writer client.Writer, eventRecorder record.EventRecorder, eventObj runtime.Object, spc *secretsstorev1.SecretProviderClass, spcRef string, kind syncedKind, objs []client.Object, names map[string]struct{}) error {
+	var errs []error
+	for _, obj := range objs {
+		if obj.GetAnnotations()[SecretProviderClassAnnotation] != spcRef {
+			continue
+		}
+		if _, ok := names[obj.GetName()]; ok {
+			continue
+		}
+
+		if secretsstorev1.SecretRetentionPolicy(obj.GetAnnotations()[SecretRetentionPolicyAnnotation]) == secretsstorev1.SecretRetentionPolicyRetain {
+			patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+			labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+			delete(labels, SecretManagedLabel)
+			delete(annotations, SecretProviderClassAnnotation)
+			delete(annotations, SecretRetentionPolicyAnnotation)
+			obj.SetLabels(labels)
+			obj.SetAnnotations(annotations)
+			obj.SetOwnerReferences(nil)
+			if err := writer.Patch(ctx, obj, patch); err != nil && !apierrors.IsNotFound(err) {
+				eventRecorder.Eventf(eventObj, corev1.EventTypeWarning, kind.cleanupFailedReason, fmt.Sprintf("failed to retain %s %s/%s removed from spc %s, err: %+v", kind.name, obj.GetNamespace(), obj.GetName(), spc.Name, err))
+				errs = append(errs, fmt.Errorf("failed to retain %s %s, err: %+v", kind.name, obj.GetName(), err))
+				continue
+			}
+			klog.InfoS(fmt.Sprintf("retained Kubernetes %s removed from spc", kind.name), kind.name, klog.KObj(obj), "spc", spcRef)
+			eventRecorder.Eventf(eventObj, corev1.EventTypeNormal, kind.retainedReason, fmt.Sprintf("%s %s/%s was removed from spc %s and is no longer managed by the driver", kind.name, obj.GetNamespace(), obj.GetName(), spc.Name))
+			continue
+		}
+
+		uid := obj.GetUID()
+		if err := writer.Delete(ctx, obj, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+			eventRecorder.Eventf(eventObj, corev1.EventTypeWarning, kind.cleanupFailedReason, fmt.Sprintf("failed to delete %s %s/%s removed from spc %s, err: %+v", kind.name, obj.GetNamespace(), obj.GetName(), spc.Name, err))
+			errs = append(errs, fmt.Errorf("failed to delete %s %s, err: %+v", kind.name, obj.GetName(), err))
+			continue
+		}
+		klog.InfoS(fmt.Sprintf("deleted Kubernetes %s removed from spc", kind.name), kind.name, klog.KObj(obj), "spc", spcRef)
+		eventRecorder.Eventf(eventObj, corev1.EventTypeNormal, kind.deletedReason, fmt.Sprintf("deleted %s %s/%s removed from spc %s", kind.name, obj.GetNamespace(), obj.GetName(), spc.Name))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%+v", errs)
 	}
 	return nil
 }
 
-// secretExists checks if the secret with name and namespace already exists
-func (r *SecretProviderClassPodStatusReconciler) secretExists(ctx context.Context, name, namespace string) (bool, error) {
+// retentionPolicy returns the retention policy of the secret or configmap object, which defaults to Delete
+func retentionPolicy(policy secretsstorev1.SecretRetentionPolicy) secretsstorev1.SecretRetentionPolicy {
+	if policy == secretsstorev1.SecretRetentionPolicyRetain {
+		return secretsstorev1.SecretRetentionPolicyRetain
+	}
+	return secretsstorev1.SecretRetentionPolicyDelete
+}
+
+// isSyncSource returns true if the spc pod status has the newest mounted contents of the spc pod statuses
+// that reference the same secret provider class, on all the nodes. The contents of an spc pod status are
+// as new as its last successful rotation, or its creation if it wasn't rotated. The ties are broken by
+// name, so the controllers on all the nodes pick the same spc pod status and don't overwrite the secret
+// data with the contents mounted on their node while a rotation is in progress.
+func (r *SecretProviderClassPodStatusReconciler) isSyncSource(ctx context.Context, spcPodStatus *secretsstorev1.SecretProviderClassPodStatus, spcKind string) (bool, error) {
+	spcPodStatuses := &secretsstorev1.SecretProviderClassPodStatusList{}
+	// the cache only contains the spc pod statuses of this node
+	if err := r.apiReader.List(ctx, spcPodStatuses, client.InNamespace(spcPodStatus.Namespace)); err != nil {
+		return false, err
+	}
+	contentsTime := func(spcps *secretsstorev1.SecretProviderClassPodStatus) time.Time {
+		if spcps.Status.LastSuccessfulRotationTime != nil {
+			return spcps.Status.LastSuccessfulRotationTime.Time
+		}
+		return spcps.CreationTimestamp.Time
+	}
+	for i := range spcPodStatuses.Items {
+		other := &spcPodStatuses.Items[i]
+		otherKind := other.Status.SecretProviderClassKind
+		if otherKind == "" {
+			otherKind = secretsstorev1.SecretProviderClassKind
+		}
+		if other.Name == spcPodStatus.Name || !other.Status.Mounted || !other.GetDeletionTimestamp().IsZero() ||
+			otherKind != spcKind || other.Status.SecretProviderClassName != spcPodStatus.Status.SecretProviderClassName {
+			continue
+		}
+		t, otherTime := contentsTime(spcPodStatus), contentsTime(other)
+		if otherTime.After(t) || (otherTime.Equal(t) && other.Name < spcPodStatus.Name) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getSecret returns the secret with name and namespace, or nil if it doesn't exist
+func (r *SecretProviderClassPodStatusReconciler) getSecret(ctx context.Context, name, namespace string) (*v1.Secret, error) {
 	o := &v1.Secret{}
 	secretKey := types.NamespacedName{
 		Namespace: namespace,
@@ -489,12 +889,225 @@ func (r *SecretProviderClassPodStatusReconciler) secretExists(ctx context.Contex
 	}
 	err := r.Client.Get(ctx, secretKey, o)
 	if err == nil {
-		return true, nil
+		return o, nil
 	}
 	if apierrors.IsNotFound(err) {
-		return false, nil
+		return nil, nil
+	}
+	return nil, err
+}
+
+// updateK8sSecret converges the existing secret managed by the driver to the desired secret.
+// Labels and annotations in the desired secret are added or updated, the ones set by other
+// controllers are preserved. The data is replaced, which adds, changes and removes keys.
+// As the secret type is immutable, a secret with a different type is deleted and recreated. If the
+// secret can't be recreated, the deleted secret is restored and an error is returned.
+// It returns a description of the changes, which is empty if the secret is up to date.
+func updateK8sSecret(ctx context.Context, writer client.Writer, existing, desired *v1.Secret) ([]string, error) {
+	desired = desired.DeepCopy()
+	setAppliedKeys(desired)
+	if existing.Type != desired.Type {
+		uid := existing.GetUID()
+		if err := writer.Delete(ctx, existing, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		recreated := desired.DeepCopy()
+		recreated.OwnerReferences, _ = mergeOwnerReferences(existing.OwnerReferences, desired.OwnerReferences)
+		// keep the labels and annotations that weren't set by the driver
+		appliedLabels, appliedAnnotations := appliedKeys(existing, AppliedLabelsAnnotation), appliedKeys(existing, AppliedAnnotationsAnnotation)
+		for k, v := range existing.Labels {
+			if _, ok := recreated.Labels[k]; !ok && !appliedLabels.Has(k) {
+				recreated.Labels[k] = v
+			}
+		}
+		for k, v := range existing.Annotations {
+			if _, ok := recreated.Annotations[k]; !ok && !appliedAnnotations.Has(k) {
+				recreated.Annotations[k] = v
+			}
+		}
+		if err := createWithRetry(ctx, writer, recreated); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// the secret was recreated by another node, it's converged in the next reconcile
+				return nil, nil
+			}
+			// restore the deleted secret, so the secret isn't missing until the next reconcile
+			restored := existing.DeepCopy()
+			restored.ResourceVersion = ""
+			restored.UID = ""
+			restored.CreationTimestamp = metav1.Time{}
+			restored.ManagedFields = nil
+			if restoreErr := createWithRetry(ctx, writer, restored); restoreErr != nil && !apierrors.IsAlreadyExists(restoreErr) {
+				return nil, fmt.Errorf("failed to recreate secret with type %s, err: %+v, and failed to restore the secret with type %s, err: %+v", desired.Type, err, existing.Type, restoreErr)
+			}
+			return nil, fmt.Errorf("failed to recreate secret with type %s, restored the secret with type %s, err: %+v", desired.Type, existing.Type, err)
+		}
+		klog.InfoS("recreated Kubernetes secret with new type", "secret", klog.KObj(recreated), "type", recreated.Type)
+		return []string{fmt.Sprintf("type changed from %s to %s", existing.Type, desired.Type)}, nil
+	}
+
+	updated := existing.DeepCopy()
+	patch := client.MergeFromWithOptions(existing.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	var changes []string
+
+	changes = append(changes, metadataChanges(updated, existing, desired)...)
+
+	if ownerRefs, changed := mergeOwnerReferences(updated.OwnerReferences, desired.OwnerReferences); changed {
+		updated.OwnerReferences = ownerRefs
+		changes = append(changes, "owner references")
+	}
+
+	changes = append(changes, dataChanges(existing.Data, desired.Data)...)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	updated.Data = desired.Data
+
+	if err := writer.Patch(ctx, updated, patch); err != nil {
+		return nil, err
+	}
+	klog.InfoS("updated Kubernetes secret", "secret", klog.KObj(updated), "changes", changes)
+	return changes, nil
+}
+
+// createWithRetry creates the object, retrying the errors other than AlreadyExists
+func createWithRetry(ctx context.Context, writer client.Writer, obj client.Object) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return !apierrors.IsAlreadyExists(err)
+	}, func() error {
+		return writer.Create(ctx, obj)
+	})
+}
+
+// dataChanges returns a description of the keys added, removed and changed in the desired data
+func dataChanges(existing, desired map[string][]byte) []string {
+	var added, removed, changed []string
+	for k, v := range desired {
+		old, ok := existing[k]
+		if !ok {
+			added = append(added, k)
+		} else if !bytes.Equal(old, v) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range existing {
+		if _, ok := desired[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	var changes []string
+	for _, c := range []struct {
+		desc string
+		keys []string
+	}{{"data keys added", added}, {"data keys removed", removed}, {"data keys changed", changed}} {
+		if len(c.keys) > 0 {
+			sort.Strings(c.keys)
+			changes = append(changes, fmt.Sprintf("%s [%s]", c.desc, strings.Join(c.keys, ",")))
+		}
+	}
+	return changes
+}
+
+// metadataChanges converges the labels and annotations of updated to the desired object. The labels and
+// annotations previously applied by the driver to the existing object that aren't desired anymore are
+// removed, the others that weren't set by the driver are kept. It returns a description of the changes.
+func metadataChanges(updated, existing, desired metav1.Object) []string {
+	var changes []string
+	labels, annotations := updated.GetLabels(), updated.GetAnnotations()
+	if keys := mergeStringMap(&labels, desired.GetLabels()); len(keys) > 0 {
+		changes = append(changes, fmt.Sprintf("labels [%s]", strings.Join(keys, ",")))
+	}
+	if keys := removeStringMapKeys(labels, appliedKeys(existing, AppliedLabelsAnnotation), desired.GetLabels()); len(keys) > 0 {
+		changes = append(changes, fmt.Sprintf("labels removed [%s]", strings.Join(keys, ",")))
+	}
+	if keys := mergeStringMap(&annotations, desired.GetAnnotations()); len(keys) > 0 {
+		changes = append(changes, fmt.Sprintf("annotations [%s]", strings.Join(keys, ",")))
+	}
+	if keys := removeStringMapKeys(annotations, appliedKeys(existing, AppliedAnnotationsAnnotation), desired.GetAnnotations()); len(keys) > 0 {
+		changes = append(changes, fmt.Sprintf("annotations removed [%s]", strings.Join(keys, ",")))
+	}
+	updated.SetLabels(labels)
+	updated.SetAnnotations(annotations)
+	return changes
+}
+
+// setAppliedKeys records the keys of the labels and annotations of the object to sync, which are
+// all set by the driver, in the AppliedLabelsAnnotation and AppliedAnnotationsAnnotation
+func setAppliedKeys(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	// the applied keys annotations are always set, so they aren't recorded
+	delete(annotations, AppliedLabelsAnnotation)
+	delete(annotations, AppliedAnnotationsAnnotation)
+	appliedAnnotations := strings.Join(sets.StringKeySet(annotations).List(), ",")
+	annotations[AppliedLabelsAnnotation] = strings.Join(sets.StringKeySet(obj.GetLabels()).List(), ",")
+	annotations[AppliedAnnotationsAnnotation] = appliedAnnotations
+	obj.SetAnnotations(annotations)
+}
+
+// appliedKeys returns the keys recorded in the annotation by setAppliedKeys. It's empty for
+// the objects synced before the keys were recorded.
+func appliedKeys(obj metav1.Object, annotation string) sets.String {
+	keys := sets.NewString()
+	for _, k := range strings.Split(obj.GetAnnotations()[annotation], ",") {
+		if k != "" {
+			keys.Insert(k)
+		}
+	}
+	return keys
+}
+
+// removeStringMapKeys removes the keys in remove that aren't in keep from the dst map and
+// returns the sorted keys that were removed
+func removeStringMapKeys(dst map[string]string, remove sets.String, keep map[string]string) []string {
+	var keys []string
+	for _, k := range remove.List() {
+		if _, ok := keep[k]; ok {
+			continue
+		}
+		if _, ok := dst[k]; ok {
+			delete(dst, k)
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// mergeStringMap sets the values in src on the dst map and returns the sorted keys that were added or changed
+func mergeStringMap(dst *map[string]string, src map[string]string) []string {
+	var keys []string
+	for k, v := range src {
+		if old, ok := (*dst)[k]; ok && old == v {
+			continue
+		}
+		if *dst == nil {
+			*dst = make(map[string]string)
+		}
+		(*dst)[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeOwnerReferences returns the owner references with the ones in src that aren't set yet
+// added, and whether any were added
+func mergeOwnerReferences(dst, src []metav1.OwnerReference) ([]metav1.OwnerReference, bool) {
+	merged := dst
+	for _, ref := range src {
+		found := false
+		for _, or := range merged {
+			if or.UID == ref.UID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(append([]metav1.OwnerReference{}, merged...), ref)
+		}
 	}
-	return false, err
+	return merged, len(merged) != len(dst)
 }
 
 // generateEvent generates an event
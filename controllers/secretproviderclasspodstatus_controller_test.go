@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 
@@ -27,15 +28,18 @@ import (
 	. "github.com/onsi/gomega"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
 )
 
 var (
@@ -44,7 +48,7 @@ var (
 
 func setupScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
-	if err := v1alpha1.AddToScheme(scheme); err != nil {
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
@@ -65,16 +69,16 @@ func newSecret(name, namespace string, labels map[string]string, annotations map
 	}
 }
 
-func newSecretProviderClassPodStatus(name, namespace, node string) *v1alpha1.SecretProviderClassPodStatus {
-	return &v1alpha1.SecretProviderClassPodStatus{
+func newSecretProviderClassPodStatus(name, namespace, node string) *secretsstorev1.SecretProviderClassPodStatus {
+	return &secretsstorev1.SecretProviderClassPodStatus{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
-			Labels:          map[string]string{v1alpha1.InternalNodeLabel: node},
+			Labels:          map[string]string{secretsstorev1.InternalNodeLabel: node},
 			UID:             "72a0ecb8-c6e5-41e1-8da1-25e37ec61b26",
 			ResourceVersion: "73659",
 		},
-		Status: v1alpha1.SecretProviderClassPodStatusStatus{
+		Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 			PodName:                 "pod1",
 			TargetPath:              "/var/lib/kubelet/pods/d8771ddf-935a-4199-a20b-f35f71c1d9e7/volumes/kubernetes.io~csi/secrets-store-inline/mount",
 			SecretProviderClassName: "spc1",
@@ -83,15 +87,15 @@ func newSecretProviderClassPodStatus(name, namespace, node string) *v1alpha1.Sec
 	}
 }
 
-func newSecretProviderClass(name, namespace string) *v1alpha1.SecretProviderClass {
-	return &v1alpha1.SecretProviderClass{
+func newSecretProviderClass(name, namespace string) *secretsstorev1.SecretProviderClass {
+	return &secretsstorev1.SecretProviderClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 		},
-		Spec: v1alpha1.SecretProviderClassSpec{
+		Spec: secretsstorev1.SecretProviderClassSpec{
 			Provider: "provider1",
-			SecretObjects: []*v1alpha1.SecretObject{
+			SecretObjects: []*secretsstorev1.SecretObject{
 				{
 					SecretName: "secret1",
 					Type:       "Opaque",
@@ -116,6 +120,7 @@ func newReconciler(client client.Client, scheme *runtime.Scheme, nodeID string)
 		Client:        client,
 		reader:        client,
 		writer:        client,
+		apiReader:     client,
 		scheme:        scheme,
 		eventRecorder: fakeRecorder,
 		mutex:         &sync.Mutex{},
@@ -123,7 +128,7 @@ func newReconciler(client client.Client, scheme *runtime.Scheme, nodeID string)
 	}
 }
 
-func TestSecretExists(t *testing.T) {
+func TestGetSecret(t *testing.T) {
 	g := NewWithT(t)
 
 	scheme, err := setupScheme()
@@ -139,13 +144,258 @@ func TestSecretExists(t *testing.T) {
 	client := fake.NewFakeClientWithScheme(scheme, initObjects...)
 	reconciler := newReconciler(client, scheme, "node1")
 
-	exists, err := reconciler.secretExists(context.TODO(), "my-secret", "default")
-	g.Expect(exists).To(Equal(true))
+	secret, err := reconciler.getSecret(context.TODO(), "my-secret", "default")
 	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret).NotTo(BeNil())
+	g.Expect(secret.Labels).To(Equal(labels))
+
+	secret, err = reconciler.getSecret(context.TODO(), "my-secret2", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret).To(BeNil())
+}
+
+func TestUpdateK8sSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	existing := newSecret("my-secret", "default", map[string]string{SecretManagedLabel: "true", "other": "value"}, nil)
+	existing.Type = v1.SecretTypeOpaque
+	existing.Data = map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2"), "key3": []byte("value3")}
+	client := fake.NewFakeClientWithScheme(scheme, existing)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	desired := newSecret("my-secret", "default", map[string]string{SecretManagedLabel: "true", "environment": "test"}, map[string]string{"kubed.appscode.com/sync": "app=test"})
+	desired.ResourceVersion = ""
+	desired.Type = v1.SecretTypeOpaque
+	desired.Data = map[string][]byte{"key1": []byte("value1"), "key2": []byte("changed"), "key4": []byte("value4")}
+
+	current, err := reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	changes, err := updateK8sSecret(context.TODO(), reconciler.writer, current, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{
+		"labels [environment]",
+		"annotations [kubed.appscode.com/sync,secrets-store.csi.k8s.io/applied-annotations,secrets-store.csi.k8s.io/applied-labels]",
+		"data keys added [key4]",
+		"data keys removed [key3]",
+		"data keys changed [key2]",
+	}))
+
+	current, err = reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(current.Data).To(Equal(desired.Data))
+	// labels set by others are preserved
+	g.Expect(current.Labels).To(Equal(map[string]string{SecretManagedLabel: "true", "other": "value", "environment": "test"}))
+	g.Expect(current.Annotations).To(Equal(map[string]string{
+		"kubed.appscode.com/sync":    "app=test",
+		AppliedLabelsAnnotation:      "environment,secrets-store.csi.k8s.io/managed",
+		AppliedAnnotationsAnnotation: "kubed.appscode.com/sync",
+	}))
+
+	// no changes if the secret is up to date
+	changes, err = updateK8sSecret(context.TODO(), reconciler.writer, current, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(BeEmpty())
+
+	// the labels and annotations removed from the secret object are removed from the secret,
+	// the ones set by others are preserved
+	delete(desired.Labels, "environment")
+	delete(desired.Annotations, "kubed.appscode.com/sync")
+	changes, err = updateK8sSecret(context.TODO(), reconciler.writer, current, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{
+		"labels removed [environment]",
+		"annotations [secrets-store.csi.k8s.io/applied-annotations,secrets-store.csi.k8s.io/applied-labels]",
+		"annotations removed [kubed.appscode.com/sync]",
+	}))
+	current, err = reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(current.Labels).To(Equal(map[string]string{SecretManagedLabel: "true", "other": "value"}))
+	g.Expect(current.Annotations).NotTo(HaveKey("kubed.appscode.com/sync"))
+	changes, err = updateK8sSecret(context.TODO(), reconciler.writer, current, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(BeEmpty())
+
+	// the secret is recreated if the type changes
+	current.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "ReplicaSet", Name: "rs1", UID: "uid1"}}
+	desired.Type = v1.SecretTypeTLS
+	desired.Data = map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")}
+	changes, err = updateK8sSecret(context.TODO(), reconciler.writer, current, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{"type changed from Opaque to kubernetes.io/tls"}))
+
+	current, err = reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(current.Type).To(Equal(v1.SecretTypeTLS))
+	g.Expect(current.Data).To(Equal(desired.Data))
+	g.Expect(current.Labels).To(HaveKeyWithValue("other", "value"))
+	g.Expect(current.OwnerReferences).To(HaveLen(1))
+}
+
+// failingCreateClient fails to create the secrets with the type
+type failingCreateClient struct {
+	client.Client
+	secretType v1.SecretType
+}
+
+func (c *failingCreateClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if secret, ok := obj.(*v1.Secret); ok && secret.Type == c.secretType {
+		return errors.New("failed to create secret")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestUpdateK8sSecretRecreateFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	existing := newSecret("my-secret", "default", map[string]string{SecretManagedLabel: "true"}, nil)
+	existing.Type = v1.SecretTypeOpaque
+	existing.Data = map[string][]byte{"key1": []byte("value1")}
+	client := fake.NewFakeClientWithScheme(scheme, existing)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	desired := newSecret("my-secret", "default", map[string]string{SecretManagedLabel: "true"}, nil)
+	desired.ResourceVersion = ""
+	desired.Type = v1.SecretTypeTLS
+	desired.Data = map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")}
+
+	current, err := reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = updateK8sSecret(context.TODO(), &failingCreateClient{Client: client, secretType: v1.SecretTypeTLS}, current, desired)
+	g.Expect(err).To(HaveOccurred())
+
+	// the deleted secret is restored
+	restored, err := reconciler.getSecret(context.TODO(), "my-secret", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored).NotTo(BeNil())
+	g.Expect(restored.Type).To(Equal(v1.SecretTypeOpaque))
+	g.Expect(restored.Data).To(Equal(existing.Data))
+	g.Expect(restored.Labels).To(Equal(existing.Labels))
+}
+
+func TestIsSyncSource(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	rotated := metav1.Now()
+	newSPCPS := func(name, node string, lastRotation *metav1.Time) *secretsstorev1.SecretProviderClassPodStatus {
+		spcps := newSecretProviderClassPodStatus(name, "default", node)
+		spcps.UID = ""
+		spcps.ResourceVersion = ""
+		spcps.Status.LastSuccessfulRotationTime = lastRotation
+		return spcps
+	}
+	spcps1 := newSPCPS("pod1-default-spc1", "node1", nil)
+	spcps2 := newSPCPS("pod2-default-spc1", "node2", nil)
+	// the spc pod statuses of other spcs and the unmounted ones aren't sources
+	otherSPC := newSPCPS("pod3-default-spc2", "node2", &rotated)
+	otherSPC.Status.SecretProviderClassName = "spc2"
+	unmounted := newSPCPS("pod4-default-spc1", "node2", &rotated)
+	unmounted.Status.Mounted = false
+
+	client := fake.NewFakeClientWithScheme(scheme, spcps1, spcps2, otherSPC, unmounted)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	// the ties are broken by name
+	source, err := reconciler.isSyncSource(context.TODO(), spcps1, secretsstorev1.SecretProviderClassKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(source).To(BeTrue())
+	source, err = reconciler.isSyncSource(context.TODO(), spcps2, secretsstorev1.SecretProviderClassKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(source).To(BeFalse())
+
+	// the spc pod status with the newest rotation is the source
+	g.Expect(client.Get(context.TODO(), types.NamespacedName{Name: spcps2.Name, Namespace: "default"}, spcps2)).To(Succeed())
+	spcps2.Status.LastSuccessfulRotationTime = &rotated
+	g.Expect(client.Update(context.TODO(), spcps2)).To(Succeed())
+	source, err = reconciler.isSyncSource(context.TODO(), spcps1, secretsstorev1.SecretProviderClassKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(source).To(BeFalse())
+	source, err = reconciler.isSyncSource(context.TODO(), spcps2, secretsstorev1.SecretProviderClassKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(source).To(BeTrue())
+}
+
+func TestCleanupRemovedSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	synced := func(name, spcRef string, policy secretsstorev1.SecretRetentionPolicy) *v1.Secret {
+		secret := newSecret(name, "default", map[string]string{SecretManagedLabel: "true"}, map[string]string{
+			SecretProviderClassAnnotation:   spcRef,
+			SecretRetentionPolicyAnnotation: string(policy),
+		})
+		secret.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "uid1"}}
+		return secret
+	}
+	initObjects := []runtime.Object{
+		// still defined in the spc
+		synced("secret1", "SecretProviderClass/spc1", secretsstorev1.SecretRetentionPolicyDelete),
+		synced("removed", "SecretProviderClass/spc1", secretsstorev1.SecretRetentionPolicyDelete),
+		synced("retained", "SecretProviderClass/spc1", secretsstorev1.SecretRetentionPolicyRetain),
+		// synced from other secret provider classes
+		synced("other", "SecretProviderClass/spc2", secretsstorev1.SecretRetentionPolicyDelete),
+		synced("cluster", "ClusterSecretProviderClass/spc1", secretsstorev1.SecretRetentionPolicyDelete),
+		// not managed by the driver
+		newSecret("unmanaged", "default", nil, nil),
+	}
+	client := fake.NewFakeClientWithScheme(scheme, initObjects...)
+	reconciler := newReconciler(client, scheme, "node1")
+	recorder := record.NewFakeRecorder(10)
+	reconciler.eventRecorder = recorder
+
+	err = reconciler.cleanupRemovedSecrets(context.TODO(), newPod("pod1", "default", nil), newSecretProviderClass("spc1", "default"), "SecretProviderClass/spc1")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, name := range []string{"secret1", "other", "cluster", "unmanaged"} {
+		secret, err := reconciler.getSecret(context.TODO(), name, "default")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(secret).NotTo(BeNil(), name)
+	}
+	secret, err := reconciler.getSecret(context.TODO(), "removed", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret).To(BeNil())
 
-	exists, err = reconciler.secretExists(context.TODO(), "my-secret2", "default")
-	g.Expect(exists).To(Equal(false))
+	secret, err = reconciler.getSecret(context.TODO(), "retained", "default")
 	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret).NotTo(BeNil())
+	g.Expect(secret.Labels).NotTo(HaveKey(SecretManagedLabel))
+	g.Expect(secret.Annotations).NotTo(HaveKey(SecretProviderClassAnnotation))
+	g.Expect(secret.OwnerReferences).To(BeEmpty())
+	g.Expect(recorder.Events).To(HaveLen(2))
+}
+
+func TestSPCPodStatusesForSPC(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spcps := newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1")
+	otherSPC := newSecretProviderClassPodStatus("pod2-default-spc2", "default", "node1")
+	otherSPC.Status.SecretProviderClassName = "spc2"
+	cluster := newSecretProviderClassPodStatus("pod3-default-spc1", "default", "node1")
+	cluster.Status.SecretProviderClassKind = secretsstorev1.ClusterSecretProviderClassKind
+
+	client := fake.NewFakeClientWithScheme(scheme, spcps, otherSPC, cluster)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	requests := reconciler.spcPodStatusesForSPC(newSecretProviderClass("spc1", "default"))
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Name).To(Equal("pod1-default-spc1"))
+
+	requests = reconciler.spcPodStatusesForSPC(&secretsstorev1.ClusterSecretProviderClass{ObjectMeta: metav1.ObjectMeta{Name: "spc1"}})
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Name).To(Equal("pod3-default-spc1"))
 }
 
 func TestPatchSecretWithOwnerRef(t *testing.T) {
@@ -215,6 +465,91 @@ func TestCreateK8sSecret(t *testing.T) {
 	g.Expect(secret.Name).To(Equal("my-secret2"))
 }
 
+func TestUpdateSecretsSyncedCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	initObjects := []runtime.Object{
+		newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"),
+	}
+	client := fake.NewFakeClientWithScheme(scheme, initObjects...)
+	reconciler := newReconciler(client, scheme, "node1")
+
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: "pod1-default-spc1", Namespace: "default"}, spcps)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	reconciler.updateSecretsSyncedCondition(context.TODO(), spcps, errors.New("failed to create secret"))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: "pod1-default-spc1", Namespace: "default"}, spcps)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(meta.IsStatusConditionFalse(spcps.Status.Conditions, secretsstorev1.ConditionTypeSecretsSynced)).To(BeTrue())
+
+	reconciler.updateSecretsSyncedCondition(context.TODO(), spcps, nil)
+	err = client.Get(context.TODO(), types.NamespacedName{Name: "pod1-default-spc1", Namespace: "default"}, spcps)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(meta.IsStatusConditionTrue(spcps.Status.Conditions, secretsstorev1.ConditionTypeSecretsSynced)).To(BeTrue())
+}
+
+func TestReconcileSecretsStorePolicyViolation(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	allowSecretSync := false
+	initObjects := []runtime.Object{
+		newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"),
+		newSecretProviderClass("spc1", "default"),
+		newPod("pod1", "default", nil),
+		&secretsstorev1.SecretsStorePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "default"},
+			Spec:       secretsstorev1.SecretsStorePolicySpec{AllowSecretSync: &allowSecretSync},
+		},
+	}
+	client := fake.NewFakeClientWithScheme(scheme, initObjects...)
+	reconciler := newReconciler(client, scheme, "node1")
+	recorder := record.NewFakeRecorder(1)
+	reconciler.eventRecorder = recorder
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod1-default-spc1", Namespace: "default"}}
+	result, err := reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+	// the secret isn't synced
+	secret, err := reconciler.getSecret(context.TODO(), "secret1", "default")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret).To(BeNil())
+
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+	err = client.Get(context.TODO(), req.NamespacedName, spcps)
+	g.Expect(err).NotTo(HaveOccurred())
+	condition := meta.FindStatusCondition(spcps.Status.Conditions, secretsstorev1.ConditionTypeSecretsSynced)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(internalerrors.SecretsStorePolicyViolation))
+
+	event := <-recorder.Events
+	g.Expect(event).To(HavePrefix("Warning " + internalerrors.SecretsStorePolicyViolation))
+}
+
+func TestOnlyStatusConditionsChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	oldSPCPS := newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1")
+
+	newSPCPS := oldSPCPS.DeepCopy()
+	now := metav1.Now()
+	newSPCPS.Status.LastAttemptTime = &now
+	meta.SetStatusCondition(&newSPCPS.Status.Conditions, metav1.Condition{Type: secretsstorev1.ConditionTypeRotated, Status: metav1.ConditionTrue, Reason: secretsstorev1.ConditionReasonSucceeded})
+	g.Expect(onlyStatusConditionsChanged(oldSPCPS, newSPCPS)).To(BeTrue())
+
+	newSPCPS.Status.Objects = []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v2"}}
+	g.Expect(onlyStatusConditionsChanged(oldSPCPS, newSPCPS)).To(BeFalse())
+}
+
 func TestGenerateEvent(t *testing.T) {
 	g := NewWithT(t)
 
@@ -263,7 +598,7 @@ func TestPatcherForStaticPod(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 
 	g.Expect(len(secret.OwnerReferences)).To(Equal(1))
-	g.Expect(secret.OwnerReferences[0].APIVersion).To(Equal(v1alpha1.GroupVersion.String()))
+	g.Expect(secret.OwnerReferences[0].APIVersion).To(Equal(secretsstorev1.GroupVersion.String()))
 	g.Expect(secret.OwnerReferences[0].Kind).To(Equal("SecretProviderClassPodStatus"))
 	g.Expect(secret.OwnerReferences[0].Name).To(Equal("pod1-default-spc1"))
 }
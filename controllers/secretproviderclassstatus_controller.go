@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+const (
+	// spcNameIndexKey is the field index for the secret provider class name in the spc pod status
+	spcNameIndexKey = "status.secretProviderClassName"
+
+	// maxByPodStatuses is the maximum number of pods listed in the secret provider class status.
+	// The ready and failed pod counts always include all pods.
+	maxByPodStatuses = 100
+)
+
+// SecretProviderClassStatusReconciler aggregates the SecretProviderClassPodStatus objects
+// from all nodes in the SecretProviderClass status
+type SecretProviderClassStatusReconciler struct {
+	reader client.Reader
+	// statusWriter writes the status subresource of the secret provider classes
+	statusWriter client.StatusWriter
+	// statusUpdateInterval is the minimum interval between status updates of a secret provider class.
+	// Changes to the spc pod statuses within the interval are batched in a single update, which bounds
+	// the writes to the secret provider class irrespective of the number of pods using it.
+	statusUpdateInterval time.Duration
+
+	mutex sync.Mutex
+	// lastUpdate is the time of the last status update for each secret provider class
+	lastUpdate map[types.NamespacedName]time.Time
+}
+
+// NewSecretProviderClassStatusReconciler creates a new SecretProviderClassStatusReconciler
+func NewSecretProviderClassStatusReconciler(mgr manager.Manager, statusUpdateInterval time.Duration) *SecretProviderClassStatusReconciler {
+	return &SecretProviderClassStatusReconciler{
+		reader:               mgr.GetCache(),
+		statusWriter:         mgr.GetClient().Status(),
+		statusUpdateInterval: statusUpdateInterval,
+		lastUpdate:           make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// Reconcile updates the status of the secret provider class with the spc pod statuses
+// of all the pods using it.
+func (r *SecretProviderClassStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	spc := &secretsstorev1.SecretProviderClass{}
+	if err := r.reader.Get(ctx, req.NamespacedName, spc); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.mutex.Lock()
+			delete(r.lastUpdate, req.NamespacedName)
+			r.mutex.Unlock()
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "failed to get spc", "spc", req.NamespacedName.String(), "controller", "spcstatus")
+		return ctrl.Result{}, err
+	}
+
+	// batch the updates within the status update interval
+	r.mutex.Lock()
+	last, ok := r.lastUpdate[req.NamespacedName]
+	r.mutex.Unlock()
+	if ok {
+		if remaining := r.statusUpdateInterval - time.Since(last); remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	if err := r.reader.List(ctx, spcPodStatusList, client.InNamespace(req.Namespace), client.MatchingFields{spcNameIndexKey: req.Name}); err != nil {
+		klog.ErrorS(err, "failed to list spc pod statuses", "spc", klog.KObj(spc), "controller", "spcstatus")
+		return ctrl.Result{}, err
+	}
+
+	status := aggregateSecretProviderClassStatus(spcPodStatusList.Items)
+	if equality.Semantic.DeepEqual(spc.Status, status) {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(spc.DeepCopy())
+	spc.Status = status
+	if err := r.statusWriter.Patch(ctx, spc, patch); err != nil {
+		klog.ErrorS(err, "failed to update spc status", "spc", klog.KObj(spc), "controller", "spcstatus")
+		return ctrl.Result{}, err
+	}
+	r.mutex.Lock()
+	r.lastUpdate[req.NamespacedName] = time.Now()
+	r.mutex.Unlock()
+
+	klog.V(5).InfoS("updated spc status", "spc", klog.KObj(spc), "pods", status.TotalPods, "ready", status.ReadyPods, "failed", status.FailedPods, "controller", "spcstatus")
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller to reconcile the secret provider class
+// when any of its spc pod statuses change
+func (r *SecretProviderClassStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &secretsstorev1.SecretProviderClassPodStatus{}, spcNameIndexKey, func(obj client.Object) []string {
+		spcps, ok := obj.(*secretsstorev1.SecretProviderClassPodStatus)
+		if !ok || !usesNamespacedSPC(spcps) {
+			return nil
+		}
+		return []string{spcps.Status.SecretProviderClassName}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secretproviderclass-status").
+		For(&secretsstorev1.SecretProviderClass{}).
+		Watches(&source.Kind{Type: &secretsstorev1.SecretProviderClassPodStatus{}}, handler.EnqueueRequestsFromMapFunc(spcForSPCPodStatus)).
+		Complete(r)
+}
+
+// spcForSPCPodStatus maps the spc pod status to the secret provider class it references
+func spcForSPCPodStatus(obj client.Object) []reconcile.Request {
+	spcps, ok := obj.(*secretsstorev1.SecretProviderClassPodStatus)
+	if !ok || !usesNamespacedSPC(spcps) {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: spcps.Namespace, Name: spcps.Status.SecretProviderClassName}},
+	}
+}
+
+// usesNamespacedSPC returns true if the spc pod status references a secret provider class
+// in its namespace. Cluster secret provider classes aren't aggregated.
+func usesNamespacedSPC(spcps *secretsstorev1.SecretProviderClassPodStatus) bool {
+	if spcps.Status.SecretProviderClassName == "" {
+		return false
+	}
+	return spcps.Status.SecretProviderClassKind == "" || spcps.Status.SecretProviderClassKind == secretsstorev1.SecretProviderClassKind
+}
+
+// aggregateSecretProviderClassStatus returns the secret provider class status for the spc pod statuses.
+// Pods that are not ready are listed first, followed by the ready pods sorted by namespace and name.
+func aggregateSecretProviderClassStatus(spcPodStatuses []secretsstorev1.SecretProviderClassPodStatus) secretsstorev1.SecretProviderClassStatus {
+	status := secretsstorev1.SecretProviderClassStatus{}
+	byPod := make([]*secretsstorev1.ByPodStatus, 0, len(spcPodStatuses))
+	for i := range spcPodStatuses {
+		spcps := &spcPodStatuses[i]
+		bp := &secretsstorev1.ByPodStatus{
+			ID:        podUID(spcps),
+			Namespace: spcps.Namespace,
+			PodName:   spcps.Status.PodName,
+			NodeName:  spcps.GetLabels()[secretsstorev1.InternalNodeLabel],
+			Ready:     isSPCPodStatusReady(spcps),
+		}
+		// the object versions are sorted so the status is stable across reconciles
+		if len(spcps.Status.Objects) > 0 {
+			bp.Objects = append([]secretsstorev1.SecretProviderClassObject{}, spcps.Status.Objects...)
+			sort.Slice(bp.Objects, func(i, j int) bool { return bp.Objects[i].ID < bp.Objects[j].ID })
+		}
+		status.TotalPods++
+		if bp.Ready {
+			status.ReadyPods++
+		} else {
+			status.FailedPods++
+		}
+		byPod = append(byPod, bp)
+	}
+
+	sort.SliceStable(byPod, func(i, j int) bool {
+		if byPod[i].Ready != byPod[j].Ready {
+			return !byPod[i].Ready
+		}
+		if byPod[i].Namespace != byPod[j].Namespace {
+			return byPod[i].Namespace < byPod[j].Namespace
+		}
+		return byPod[i].PodName < byPod[j].PodName
+	})
+	if len(byPod) > maxByPodStatuses {
+		byPod = byPod[:maxByPodStatuses]
+	}
+	if len(byPod) > 0 {
+		status.ByPod = byPod
+	}
+	return status
+}
+
+// podUID returns the UID of the pod that owns the spc pod status
+func podUID(spcps *secretsstorev1.SecretProviderClassPodStatus) string {
+	for _, ref := range spcps.GetOwnerReferences() {
+		if ref.Kind == "Pod" {
+			return string(ref.UID)
+		}
+	}
+	return ""
+}
+
+// isSPCPodStatusReady returns true if the objects are mounted in the pod and
+// none of the spc pod status conditions are false
+func isSPCPodStatusReady(spcps *secretsstorev1.SecretProviderClassPodStatus) bool {
+	if !spcps.Status.Mounted {
+		return false
+	}
+	for _, condition := range spcps.Status.Conditions {
+		if condition.Status == metav1.ConditionFalse {
+			return false
+		}
+	}
+	return true
+}
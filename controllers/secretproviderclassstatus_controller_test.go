@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+func newStatusReconciler(c *fakeClientWrapper, interval time.Duration) *SecretProviderClassStatusReconciler {
+	return &SecretProviderClassStatusReconciler{
+		reader:               c,
+		statusWriter:         c.Status(),
+		statusUpdateInterval: interval,
+		lastUpdate:           make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// fakeClientWrapper counts the status patch calls made to the fake client
+type fakeClientWrapper struct {
+	client.Client
+	patches int
+}
+
+func (c *fakeClientWrapper) Status() client.StatusWriter {
+	return &fakeStatusWriterWrapper{StatusWriter: c.Client.Status(), c: c}
+}
+
+type fakeStatusWriterWrapper struct {
+	client.StatusWriter
+	c *fakeClientWrapper
+}
+
+func (w *fakeStatusWriterWrapper) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.c.patches++
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+func TestAggregateSecretProviderClassStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	ready := newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1")
+	ready.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "uid1"}}
+	ready.Status.Objects = []secretsstorev1.SecretProviderClassObject{{ID: "secret/b", Version: "v1"}, {ID: "secret/a", Version: "v1"}}
+
+	failed := newSecretProviderClassPodStatus("pod2-default-spc1", "default", "node2")
+	failed.Status.PodName = "pod2"
+	failed.Status.Conditions = []metav1.Condition{{Type: secretsstorev1.ConditionTypeRotated, Status: metav1.ConditionFalse, Reason: "FailedToRotate"}}
+
+	status := aggregateSecretProviderClassStatus([]secretsstorev1.SecretProviderClassPodStatus{*ready, *failed})
+	g.Expect(status.TotalPods).To(BeNumerically("==", 2))
+	g.Expect(status.ReadyPods).To(BeNumerically("==", 1))
+	g.Expect(status.FailedPods).To(BeNumerically("==", 1))
+	g.Expect(status.ByPod).To(HaveLen(2))
+	// pods that are not ready are listed first
+	g.Expect(status.ByPod[0].PodName).To(Equal("pod2"))
+	g.Expect(status.ByPod[0].NodeName).To(Equal("node2"))
+	g.Expect(status.ByPod[1]).To(Equal(&secretsstorev1.ByPodStatus{
+		ID:        "uid1",
+		Namespace: "default",
+		PodName:   "pod1",
+		NodeName:  "node1",
+		Objects:   []secretsstorev1.SecretProviderClassObject{{ID: "secret/a", Version: "v1"}, {ID: "secret/b", Version: "v1"}},
+		Ready:     true,
+	}))
+
+	// the pods listed in the status are limited
+	var spcPodStatuses []secretsstorev1.SecretProviderClassPodStatus
+	for i := 0; i < maxByPodStatuses+10; i++ {
+		spcPodStatuses = append(spcPodStatuses, *newSecretProviderClassPodStatus(fmt.Sprintf("pod%d-default-spc1", i), "default", "node1"))
+	}
+	status = aggregateSecretProviderClassStatus(spcPodStatuses)
+	g.Expect(status.TotalPods).To(BeNumerically("==", maxByPodStatuses+10))
+	g.Expect(status.ByPod).To(HaveLen(maxByPodStatuses))
+}
+
+func TestSecretProviderClassStatusReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	initObjects := []runtime.Object{
+		newSecretProviderClass("spc1", "default"),
+		newSecretProviderClassPodStatus("pod1-default-spc1", "default", "node1"),
+	}
+	c := &fakeClientWrapper{Client: fake.NewFakeClientWithScheme(scheme, initObjects...)}
+	reconciler := newStatusReconciler(c, time.Minute)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "spc1", Namespace: "default"}}
+
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.patches).To(Equal(1))
+
+	spc := &secretsstorev1.SecretProviderClass{}
+	err = c.Get(context.TODO(), req.NamespacedName, spc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spc.Status.TotalPods).To(BeNumerically("==", 1))
+	g.Expect(spc.Status.ReadyPods).To(BeNumerically("==", 1))
+
+	// changes within the status update interval are requeued instead of written
+	spcps := newSecretProviderClassPodStatus("pod2-default-spc1", "default", "node2")
+	spcps.ResourceVersion = ""
+	err = c.Create(context.TODO(), spcps)
+	g.Expect(err).NotTo(HaveOccurred())
+	result, err := reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	g.Expect(c.patches).To(Equal(1))
+
+	// after the interval the status is updated
+	reconciler.lastUpdate[req.NamespacedName] = time.Now().Add(-time.Minute)
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.patches).To(Equal(2))
+
+	// no update if the status hasn't changed
+	reconciler.lastUpdate[req.NamespacedName] = time.Now().Add(-time.Minute)
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.patches).To(Equal(2))
+}
@@ -0,0 +1,557 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/k8sutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/policyutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/secretutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcpsutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
+)
+
+const (
+	standaloneSyncFailedReason   = "StandaloneSyncFailed"
+	standaloneSyncCompleteReason = "StandaloneSyncComplete"
+
+	// defaultSyncPermission is the permission of the files written in the sync directory
+	// when the spc doesn't set a default mode
+	defaultSyncPermission os.FileMode = 0644
+
+	csiPodNamespace      = "csi.storage.k8s.io/pod.namespace"
+	csiPodServiceAccount = "csi.storage.k8s.io/serviceAccount.name"
+)
+
+// SecretProviderClassSyncReconciler syncs the secret and configmap objects of the secret provider
+// classes annotated for standalone sync, without a pod mounting the volume. The objects are fetched
+// with the provider Mount RPC into a private directory, which is removed once the objects are synced.
+// The state of the sync is recorded in a SecretProviderClassSyncStatus with the name of the spc.
+type SecretProviderClassSyncReconciler struct {
+	reader client.Reader
+	writer client.Writer
+	// secretReader reads the secret and service account referenced in the spc for the credentials
+	// of the provider, which aren't managed by the driver so they're not in the cache
+	secretReader    client.Reader
+	eventRecorder   record.EventRecorder
+	providerClients *secretsstore.PluginClientBuilder
+	// syncDir is the private directory the objects are written to, which should be an in-memory volume
+	syncDir string
+	// syncInterval is the interval between syncs of a secret provider class, which rotates the synced objects
+	syncInterval time.Duration
+}
+
+// NewSecretProviderClassSyncReconciler creates a new SecretProviderClassSyncReconciler
+func NewSecretProviderClassSyncReconciler(mgr manager.Manager, providerClients *secretsstore.PluginClientBuilder, syncDir string, syncInterval time.Duration) *SecretProviderClassSyncReconciler {
+	return &SecretProviderClassSyncReconciler{
+		reader:          mgr.GetCache(),
+		writer:          mgr.GetClient(),
+		secretReader:    mgr.GetAPIReader(),
+		eventRecorder:   mgr.GetEventRecorderFor("csi-secrets-store-standalone-sync"),
+		providerClients: providerClients,
+		syncDir:         syncDir,
+		syncInterval:    syncInterval,
+	}
+}
+
+// standaloneSyncEnabled returns true if the spc is annotated for standalone sync
+func standaloneSyncEnabled(obj client.Object) bool {
+	return obj.GetAnnotations()[secretsstorev1.StandaloneSyncAnnotation] == "true"
+}
+
+// Reconcile fetches the objects of the secret provider class from the provider and syncs them
+// as Kubernetes secrets and configmaps. The spc is requeued after the sync interval to rotate them.
+func (r *SecretProviderClassSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	spc := &secretsstorev1.SecretProviderClass{}
+	if err := r.reader.Get(ctx, req.NamespacedName, spc); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the sync status and synced objects are garbage collected with the spc
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "failed to get spc", "spc", req.NamespacedName.String(), "controller", "standalonesync")
+		return ctrl.Result{}, err
+	}
+
+	syncStatus := &secretsstorev1.SecretProviderClassSyncStatus{}
+	if err := r.reader.Get(ctx, req.NamespacedName, syncStatus); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "failed to get spc sync status", "spc", klog.KObj(spc), "controller", "standalonesync")
+			return ctrl.Result{}, err
+		}
+		syncStatus = nil
+	}
+
+	if !standaloneSyncEnabled(spc) {
+		// the synced objects are kept when the standalone sync is disabled, they're owned by the spc
+		if syncStatus != nil {
+			if err := r.writer.Delete(ctx, syncStatus); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// sync again after the interval unless the spc changed since the last successful sync
+	if syncStatus != nil && syncStatus.Status.LastSuccessfulSyncTime != nil {
+		synced := meta.FindStatusCondition(syncStatus.Status.Conditions, secretsstorev1.ConditionTypeSynced)
+		if synced != nil && synced.Status == metav1.ConditionTrue && synced.ObservedGeneration == spc.Generation {
+			if remaining := r.syncInterval - time.Since(syncStatus.Status.LastSuccessfulSyncTime.Time); remaining > 0 {
+				return ctrl.Result{RequeueAfter: remaining}, nil
+			}
+		}
+	}
+
+	if syncStatus == nil {
+		syncStatus = &secretsstorev1.SecretProviderClassSyncStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            spc.Name,
+				Namespace:       spc.Namespace,
+				OwnerReferences: []metav1.OwnerReference{spcOwnerReference(spc)},
+			},
+		}
+		if err := r.writer.Create(ctx, syncStatus); err != nil {
+			klog.ErrorS(err, "failed to create spc sync status", "spc", klog.KObj(spc), "controller", "standalonesync")
+			return ctrl.Result{}, err
+		}
+	}
+
+	patch := client.MergeFromWithOptions(syncStatus.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	syncErr := r.sync(ctx, spc, syncStatus)
+	if err := r.writer.Patch(ctx, syncStatus, patch); err != nil {
+		klog.ErrorS(err, "failed to update spc sync status", "spc", klog.KObj(spc), "controller", "standalonesync")
+		return ctrl.Result{}, err
+	}
+	if syncErr != nil {
+		klog.ErrorS(syncErr, "failed to sync spc", "spc", klog.KObj(spc), "controller", "standalonesync")
+		r.eventRecorder.Eventf(spc, corev1.EventTypeWarning, standaloneSyncFailedReason, "failed to sync spc %s/%s, err: %+v", spc.Namespace, spc.Name, syncErr)
+		// provider errors that aren't retryable are retried after the sync interval, and
+		// retryable provider errors after the retry-after duration returned by the provider
+		var providerErr *secretsstore.ProviderError
+		if errors.As(syncErr, &providerErr) {
+			if !providerErr.Retryable {
+				return ctrl.Result{RequeueAfter: r.syncInterval}, nil
+			}
+			if providerErr.RetryAfter > 0 {
+				return ctrl.Result{RequeueAfter: providerErr.RetryAfter}, nil
+			}
+		}
+		return ctrl.Result{}, syncErr
+	}
+
+	klog.InfoS("spc synced", "spc", klog.KObj(spc), "secrets", len(syncStatus.Status.SyncedSecrets), "configmaps", len(syncStatus.Status.SyncedConfigMaps), "controller", "standalonesync")
+	return ctrl.Result{RequeueAfter: r.syncInterval}, nil
+}
+
+// sync fetches the objects of the spc from the provider and syncs the secrets and configmaps,
+// recording the result in the sync status
+func (r *SecretProviderClassSyncReconciler) sync(ctx context.Context, spc *secretsstorev1.SecretProviderClass, syncStatus *secretsstorev1.SecretProviderClassSyncStatus) error {
+	now := metav1.Now()
+	syncStatus.Status.SecretProviderClassName = spc.Name
+	syncStatus.Status.LastAttemptTime = &now
+
+	files, objectVersions, errorReason, err := r.fetchObjects(ctx, spc, syncStatus)
+	defer os.RemoveAll(r.objectsDir(spc))
+	if err != nil {
+		spcpsutil.SetStatusCondition(&syncStatus.Status.Conditions, spc.Generation, secretsstorev1.ConditionTypeSynced, errorReason, err)
+		return err
+	}
+
+	spcRef := secretsstorev1.SecretProviderClassKind + "/" + spc.Name
+	ownerRef := spcOwnerReference(spc)
+	var errs []error
+
+	syncedSecrets, secretErrs := r.syncSecrets(ctx, spc, spcRef, ownerRef, files)
+	errs = append(errs, secretErrs...)
+	syncedConfigMaps, configMapErrs := r.syncConfigMaps(ctx, spc, spcRef, ownerRef, files)
+	errs = append(errs, configMapErrs...)
+	if err := r.cleanupRemovedObjects(ctx, spc, spcRef); err != nil {
+		errs = append(errs, err)
+	}
+
+	var ov []secretsstorev1.SecretProviderClassObject
+	for k, v := range objectVersions {
+		ov = append(ov, secretsstorev1.SecretProviderClassObject{ID: strings.TrimSpace(k), Version: strings.TrimSpace(v)})
+	}
+	sort.Slice(ov, func(i, j int) bool { return ov[i].ID < ov[j].ID })
+	if !equality.Semantic.DeepEqual(syncStatus.Status.Objects, ov) && syncStatus.Status.Objects != nil {
+		r.eventRecorder.Eventf(spc, corev1.EventTypeNormal, standaloneSyncCompleteReason, "successfully rotated objects for spc %s/%s", spc.Namespace, spc.Name)
+	}
+	syncStatus.Status.Objects = ov
+	syncStatus.Status.SyncedSecrets = syncedSecrets
+	syncStatus.Status.SyncedConfigMaps = syncedConfigMaps
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("failed to sync one or more objects, err: %+v", errs)
+		spcpsutil.SetStatusCondition(&syncStatus.Status.Conditions, spc.Generation, secretsstorev1.ConditionTypeSynced, internalerrors.FailedToSyncSecret, err)
+		return err
+	}
+	spcpsutil.SetStatusCondition(&syncStatus.Status.Conditions, spc.Generation, secretsstorev1.ConditionTypeSynced, "", nil)
+	syncStatus.Status.LastSuccessfulSyncTime = &now
+	return nil
+}
+
+// standaloneSyncAllowed returns true if the service account or secret lists the spc in its
+// standalone sync allowed annotation
+func standaloneSyncAllowed(obj metav1.Object, spcName string) bool {
+	for _, name := range strings.Split(obj.GetAnnotations()[secretsstorev1.StandaloneSyncAllowedAnnotation], ",") {
+		if strings.TrimSpace(name) == spcName {
+			return true
+		}
+	}
+	return false
+}
+
+// objectsDir returns the private directory the objects of the spc are written to
+func (r *SecretProviderClassSyncReconciler) objectsDir(spc *secretsstorev1.SecretProviderClass) string {
+	return filepath.Join(r.syncDir, spc.Namespace, spc.Name)
+}
+
+// fetchObjects calls the provider Mount RPC for the spc in the same way as the mount of a volume,
+// with the namespace of the spc and the service account and secret in its annotations in place of
+// the pod and the volume. The files returned by the provider are written to the private directory.
+// It returns the written files and the object versions, or the error reason and the error.
+func (r *SecretProviderClassSyncReconciler) fetchObjects(ctx context.Context, spc *secretsstorev1.SecretProviderClass, syncStatus *secretsstorev1.SecretProviderClassSyncStatus) (map[string]string, map[string]string, string, error) {
+	// the service account must allow the spc, as it's used without a pod that's authorized to use it
+	serviceAccount := spc.GetAnnotations()[secretsstorev1.StandaloneSyncServiceAccountAnnotation]
+	if serviceAccount != "" {
+		sa := &corev1.ServiceAccount{}
+		if err := r.secretReader.Get(ctx, types.NamespacedName{Namespace: spc.Namespace, Name: serviceAccount}, sa); err != nil {
+			return nil, nil, internalerrors.StandaloneSyncNotAllowed, fmt.Errorf("failed to get service account %s/%s, err: %+v", spc.Namespace, serviceAccount, err)
+		}
+		if !standaloneSyncAllowed(sa, spc.Name) {
+			return nil, nil, internalerrors.StandaloneSyncNotAllowed, fmt.Errorf("service account %s/%s doesn't allow the standalone sync of spc %s with the %s annotation", spc.Namespace, serviceAccount, spc.Name, secretsstorev1.StandaloneSyncAllowedAnnotation)
+		}
+	}
+	// there's no pod, so the placeholders in the parameters are expanded with the namespace of
+	// the spc and the service account in its annotation, and pod labels and annotations aren't set
+	parameters, err := k8sutil.ExpandParameters(spc.Spec.Parameters, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: spc.Namespace},
+		Spec:       corev1.PodSpec{ServiceAccountName: serviceAccount},
+	})
+	if err != nil {
+		return nil, nil, internalerrors.FailedToExpandParameters, err
+	}
+	if err = spcutil.AddParametersFrom(ctx, spcutil.NewParametersSource(r.secretReader), spc, parameters); err != nil {
+		return nil, nil, internalerrors.ParametersFromNotFound, err
+	}
+	// enforce the secrets store policies in the namespace of the spc before the provider is called
+	if err = policyutil.Enforce(ctx, r.reader, spc.Namespace, spc, parameters); err != nil {
+		var violation *policyutil.ViolationError
+		if errors.As(err, &violation) {
+			r.eventRecorder.Event(spc, corev1.EventTypeWarning, internalerrors.SecretsStorePolicyViolation, err.Error())
+			return nil, nil, internalerrors.SecretsStorePolicyViolation, err
+		}
+		return nil, nil, internalerrors.FailedToMount, err
+	}
+	parameters[csiPodNamespace] = spc.Namespace
+	parameters[csiPodServiceAccount] = serviceAccount
+	paramsJSON, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, nil, internalerrors.FailedToMount, fmt.Errorf("failed to marshal parameters, err: %+v", err)
+	}
+
+	permission := defaultSyncPermission
+	if spc.Spec.DefaultMode != nil {
+		permission = os.FileMode(*spc.Spec.DefaultMode)
+	}
+	permissionJSON, err := json.Marshal(permission)
+	if err != nil {
+		return nil, nil, internalerrors.FailedToMount, fmt.Errorf("failed to marshal permission, err: %+v", err)
+	}
+
+	secretData := make(map[string]string)
+	if secretName := spc.GetAnnotations()[secretsstorev1.StandaloneSyncSecretRefAnnotation]; secretName != "" {
+		secret := &corev1.Secret{}
+		if err := r.secretReader.Get(ctx, types.NamespacedName{Namespace: spc.Namespace, Name: secretName}, secret); err != nil {
+			return nil, nil, internalerrors.NodePublishSecretRefNotFound, fmt.Errorf("failed to get secret %s/%s, err: %+v", spc.Namespace, secretName, err)
+		}
+		// the secret is read with the permissions of the controller, so it must allow the spc
+		if !standaloneSyncAllowed(secret, spc.Name) {
+			return nil, nil, internalerrors.StandaloneSyncNotAllowed, fmt.Errorf("secret %s/%s doesn't allow the standalone sync of spc %s with the %s annotation", spc.Namespace, secretName, spc.Name, secretsstorev1.StandaloneSyncAllowedAnnotation)
+		}
+		for k, v := range secret.Data {
+			secretData[k] = string(v)
+		}
+	}
+	secretsJSON, err := json.Marshal(secretData)
+	if err != nil {
+		return nil, nil, internalerrors.FailedToMount, fmt.Errorf("failed to marshal secret data, err: %+v", err)
+	}
+
+	oldObjectVersions := make(map[string]string, len(syncStatus.Status.Objects))
+	for _, obj := range syncStatus.Status.Objects {
+		oldObjectVersions[obj.ID] = obj.Version
+	}
+
+	dir := r.objectsDir(spc)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, internalerrors.FileWriteError, fmt.Errorf("failed to create sync directory, err: %+v", err)
+	}
+
+	providerName := string(spc.Spec.Provider)
+	providerClient, err := r.providerClients.Get(ctx, providerName)
+	if err != nil {
+		errorReason := secretsstore.LookupErrorReason(err)
+		err = fmt.Errorf("failed to lookup provider client: %q, err: %w", providerName, err)
+		spcpsutil.SetProviderHealthyStatusCondition(&syncStatus.Status.Conditions, spc.Generation, errorReason, err)
+		return nil, nil, errorReason, err
+	}
+	objects, errorReason, err := secretsstore.MountContent(ctx, providerClient, string(paramsJSON), string(secretsJSON), dir, string(permissionJSON), oldObjectVersions, r.providerClients.WriteOptions(providerName, secretsstore.NewWriteOptions(spc.Spec, nil)))
+	spcpsutil.SetProviderHealthyStatusCondition(&syncStatus.Status.Conditions, spc.Generation, errorReason, err)
+	if err != nil {
+		return nil, nil, errorReason, err
+	}
+
+	files, err := fileutil.GetMountedFiles(dir)
+	if err != nil {
+		return nil, nil, internalerrors.FileWriteError, fmt.Errorf("failed to get synced files, err: %+v", err)
+	}
+	// providers that write the files to the target path themselves can't access the private
+	// directory, so only the files returned in the mount response are available
+	if len(files) == 0 && len(objects) > 0 {
+		return nil, nil, internalerrors.MountResponseFilesMissing, fmt.Errorf("provider %s didn't return the files in the mount response, which is required for the standalone sync", providerName)
+	}
+	return files, secretsstore.ObjectVersions(objects), "", nil
+}
+
+// syncSecrets creates or updates the secrets defined in the secret objects of the spc. Existing secrets
+// that aren't managed by the driver are skipped. It returns the names of the synced secrets and the errors
+// for the secrets that couldn't be synced.
+func (r *SecretProviderClassSyncReconciler) syncSecrets(ctx context.Context, spc *secretsstorev1.SecretProviderClass, spcRef string, ownerRef metav1.OwnerReference, files map[string]string) ([]string, []error) {
+	var synced []string
+	var errs []error
+	for _, secretObj := range spc.Spec.SecretObjects {
+		if secretObj == nil {
+			continue
+		}
+		secretName := strings.TrimSpace(secretObj.SecretName)
+		if err := secretutil.ValidateSecretObject(*secretObj); err != nil {
+			errs = append(errs, fmt.Errorf("failed to validate secret object in spc %s/%s, err: %+v", spc.Namespace, spc.Name, err))
+			continue
+		}
+		secretType := secretutil.GetSecretType(strings.TrimSpace(secretObj.Type))
+		datamap, err := secretutil.GetSecretData(secretObj.Data, secretType, files, spc.Spec.Templates)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get data in spc %s/%s for secret %s, err: %+v", spc.Namespace, spc.Name, secretName, err))
+			continue
+		}
+
+		desired := &corev1.Secret{
+			ObjectMeta: syncedObjectMeta(spc.Namespace, secretName, spcRef, secretObj.Labels, secretObj.Annotations, secretObj.RetentionPolicy, ownerRef),
+			Type:       secretType,
+			Data:       datamap,
+		}
+		existing := &corev1.Secret{}
+		err = r.reader.Get(ctx, types.NamespacedName{Namespace: spc.Namespace, Name: secretName}, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to check if secret %s exists, err: %+v", secretName, err))
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			if err := r.writer.Create(ctx, desired); err != nil {
+				errs = append(errs, fmt.Errorf("failed to create secret %s, err: %+v", secretName, err))
+				continue
+			}
+			klog.InfoS("successfully created Kubernetes secret", "secret", klog.KObj(desired), "spc", klog.KObj(spc), "controller", "standalonesync")
+			synced = append(synced, secretName)
+			continue
+		}
+
+		if existing.GetLabels()[SecretManagedLabel] != "true" {
+			klog.V(5).InfoS("secret is not managed by the driver, skipping update", "secret", klog.KObj(existing), "spc", klog.KObj(spc), "controller", "standalonesync")
+			continue
+		}
+		changes, err := updateK8sSecret(ctx, r.writer, existing, desired)
+		if err != nil {
+			r.eventRecorder.Eventf(spc, corev1.EventTypeWarning, secretUpdateFailedReason, "failed to update secret %s/%s, err: %+v", spc.Namespace, secretName, err)
+			errs = append(errs, fmt.Errorf("failed to update secret %s, err: %+v", secretName, err))
+			continue
+		}
+		if len(changes) > 0 {
+			r.eventRecorder.Eventf(spc, corev1.EventTypeNormal, secretUpdatedReason, "updated secret %s/%s to match spc %s: %s", spc.Namespace, secretName, spc.Name, strings.Join(changes, ", "))
+		}
+		synced = append(synced, secretName)
+	}
+	return synced, errs
+}
+
+// syncConfigMaps creates or updates the configmaps defined in the configmap objects of the spc
+// in the same way as syncSecrets
+func (r *SecretProviderClassSyncReconciler) syncConfigMaps(ctx context.Context, spc *secretsstorev1.SecretProviderClass, spcRef string, ownerRef metav1.OwnerReference, files map[string]string) ([]string, []error) {
+	var synced []string
+	var errs []error
+	for _, configMapObj := range spc.Spec.ConfigMapObjects {
+		if configMapObj == nil {
+			continue
+		}
+		configMapName := strings.TrimSpace(configMapObj.ConfigMapName)
+		if err := secretutil.ValidateConfigMapObject(*configMapObj); err != nil {
+			errs = append(errs, fmt.Errorf("failed to validate configmap object in spc %s/%s, err: %+v", spc.Namespace, spc.Name, err))
+			continue
+		}
+		data, binaryData, err := secretutil.GetConfigMapData(configMapObj.Data, files, spc.Spec.Templates)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get data in spc %s/%s for configmap %s, err: %+v", spc.Namespace, spc.Name, configMapName, err))
+			continue
+		}
+
+		desired := &corev1.ConfigMap{
+			ObjectMeta: syncedObjectMeta(spc.Namespace, configMapName, spcRef, configMapObj.Labels, configMapObj.Annotations, configMapObj.RetentionPolicy, ownerRef),
+			Data:       data,
+			BinaryData: binaryData,
+		}
+		existing := &corev1.ConfigMap{}
+		err = r.reader.Get(ctx, types.NamespacedName{Namespace: spc.Namespace, Name: configMapName}, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to check if configmap %s exists, err: %+v", configMapName, err))
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			if err := r.writer.Create(ctx, desired); err != nil {
+				errs = append(errs, fmt.Errorf("failed to create configmap %s, err: %+v", configMapName, err))
+				continue
+			}
+			klog.InfoS("successfully created Kubernetes configmap", "configmap", klog.KObj(desired), "spc", klog.KObj(spc), "controller", "standalonesync")
+			synced = append(synced, configMapName)
+			continue
+		}
+
+		if existing.GetLabels()[SecretManagedLabel] != "true" {
+			klog.V(5).InfoS("configmap is not managed by the driver, skipping update", "configmap", klog.KObj(existing), "spc", klog.KObj(spc), "controller", "standalonesync")
+			continue
+		}
+		changes, err := updateK8sConfigMap(ctx, r.writer, existing, desired)
+		if err != nil {
+			r.eventRecorder.Eventf(spc, corev1.EventTypeWarning, configMapUpdateFailedReason, "failed to update configmap %s/%s, err: %+v", spc.Namespace, configMapName, err)
+			errs = append(errs, fmt.Errorf("failed to update configmap %s, err: %+v", configMapName, err))
+			continue
+		}
+		if len(changes) > 0 {
+			r.eventRecorder.Eventf(spc, corev1.EventTypeNormal, configMapUpdatedReason, "updated configmap %s/%s to match spc %s: %s", spc.Namespace, configMapName, spc.Name, strings.Join(changes, ", "))
+		}
+		synced = append(synced, configMapName)
+	}
+	return synced, errs
+}
+
+// cleanupRemovedObjects deletes or retains the secrets and configmaps synced from the spc
+// that are no longer defined in it, based on their retention policy
+func (r *SecretProviderClassSyncReconciler) cleanupRemovedObjects(ctx context.Context, spc *secretsstorev1.SecretProviderClass, spcRef string) error {
+	secretNames := make(map[string]struct{}, len(spc.Spec.SecretObjects))
+	for _, secretObj := range spc.Spec.SecretObjects {
+		if secretObj != nil {
+			secretNames[strings.TrimSpace(secretObj.SecretName)] = struct{}{}
+		}
+	}
+	secretList := &corev1.SecretList{}
+	if err := r.reader.List(ctx, secretList, client.InNamespace(spc.Namespace), client.MatchingLabels{SecretManagedLabel: "true"}); err != nil {
+		return err
+	}
+	secrets := make([]client.Object, 0, len(secretList.Items))
+	for i := range secretList.Items {
+		secrets = append(secrets, &secretList.Items[i])
+	}
+	if err := cleanupRemovedObjects(ctx, r.writer, r.eventRecorder, spc, spc, spcRef, syncedSecret, secrets, secretNames); err != nil {
+		return err
+	}
+
+	configMapNames := make(map[string]struct{}, len(spc.Spec.ConfigMapObjects))
+	for _, configMapObj := range spc.Spec.ConfigMapObjects {
+		if configMapObj != nil {
+			configMapNames[strings.TrimSpace(configMapObj.ConfigMapName)] = struct{}{}
+		}
+	}
+	configMapList := &corev1.ConfigMapList{}
+	if err := r.reader.List(ctx, configMapList, client.InNamespace(spc.Namespace), client.MatchingLabels{SecretManagedLabel: "true"}); err != nil {
+		return err
+	}
+	configMaps := make([]client.Object, 0, len(configMapList.Items))
+	for i := range configMapList.Items {
+		configMaps = append(configMaps, &configMapList.Items[i])
+	}
+	return cleanupRemovedObjects(ctx, r.writer, r.eventRecorder, spc, spc, spcRef, syncedConfigMap, configMaps, configMapNames)
+}
+
+// syncedObjectMeta returns the metadata of a secret or configmap synced from the spc, with the
+// labels and annotations of the object in the spc and the ones used by the driver to manage it
+func syncedObjectMeta(namespace, name, spcRef string, labels, annotations map[string]string, policy secretsstorev1.SecretRetentionPolicy, ownerRef metav1.OwnerReference) metav1.ObjectMeta {
+	objMeta := metav1.ObjectMeta{
+		Namespace:       namespace,
+		Name:            name,
+		Labels:          make(map[string]string, len(labels)+1),
+		Annotations:     make(map[string]string, len(annotations)+2),
+		OwnerReferences: []metav1.OwnerReference{ownerRef},
+	}
+	for k, v := range labels {
+		objMeta.Labels[k] = v
+	}
+	for k, v := range annotations {
+		objMeta.Annotations[k] = v
+	}
+	objMeta.Labels[SecretManagedLabel] = "true"
+	objMeta.Annotations[SecretProviderClassAnnotation] = spcRef
+	objMeta.Annotations[SecretRetentionPolicyAnnotation] = string(retentionPolicy(policy))
+	setAppliedKeys(&objMeta)
+	return objMeta
+}
+
+// spcOwnerReference returns the owner reference to the spc for the objects synced from it,
+// so they're garbage collected when the spc is deleted
+func spcOwnerReference(spc *secretsstorev1.SecretProviderClass) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: secretsstorev1.GroupVersion.String(),
+		Kind:       secretsstorev1.SecretProviderClassKind,
+		Name:       spc.Name,
+		UID:        spc.UID,
+	}
+}
+
+// SetupWithManager sets up the controller to sync the secret provider classes when they're
+// created, their spec changes or they're annotated for standalone sync
+func (r *SecretProviderClassSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secretproviderclass-sync").
+		For(&secretsstorev1.SecretProviderClass{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+	providerfake "sigs.k8s.io/secrets-store-csi-driver/provider/fake"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+func newSyncReconciler(t *testing.T, c client.Client, files []*v1alpha1.File, objects map[string]string) *SecretProviderClassSyncReconciler {
+	socketPath := t.TempDir()
+	server, err := providerfake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider1.sock"))
+	if err != nil {
+		t.Fatalf("failed to create provider server, err: %+v", err)
+	}
+	server.SetObjects(objects)
+	server.SetFiles(files)
+	if err := server.Start(); err != nil {
+		t.Fatalf("failed to start provider server, err: %+v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	providerClients := secretsstore.NewPluginClientBuilder(socketPath)
+	t.Cleanup(providerClients.Cleanup)
+	return &SecretProviderClassSyncReconciler{
+		reader:          c,
+		writer:          c,
+		secretReader:    c,
+		eventRecorder:   record.NewFakeRecorder(10),
+		providerClients: providerClients,
+		syncDir:         t.TempDir(),
+		syncInterval:    time.Minute,
+	}
+}
+
+func newStandaloneSyncSPC() *secretsstorev1.SecretProviderClass {
+	spc := newSecretProviderClass("spc1", "default")
+	spc.UID = "spc-uid"
+	spc.Annotations = map[string]string{secretsstorev1.StandaloneSyncAnnotation: "true"}
+	spc.Spec.SecretObjects[0].Data = []*secretsstorev1.SecretObjectData{{ObjectName: "password", Key: "password"}}
+	spc.Spec.ConfigMapObjects = []*secretsstorev1.ConfigMapObject{
+		{ConfigMapName: "ca", Data: []*secretsstorev1.SecretObjectData{{ObjectName: "ca", Key: "ca.crt"}}},
+	}
+	return spc
+}
+
+func TestSecretProviderClassSyncReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spc := newStandaloneSyncSPC()
+	c := fake.NewFakeClientWithScheme(scheme, spc)
+	files := []*v1alpha1.File{
+		{Path: "password", Mode: 0644, Contents: []byte("secret")},
+		{Path: "ca", Mode: 0644, Contents: []byte("ca-bundle")},
+	}
+	reconciler := newSyncReconciler(t, c, files, map[string]string{"password": "v1", "ca": "v1"})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "spc1"}}
+	result, err := reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+	// the secret and configmap are synced and owned by the spc
+	secret := &v1.Secret{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+	g.Expect(secret.Labels).To(HaveKeyWithValue(SecretManagedLabel, "true"))
+	g.Expect(secret.Annotations).To(HaveKeyWithValue(SecretProviderClassAnnotation, "SecretProviderClass/spc1"))
+	g.Expect(secret.OwnerReferences).To(ConsistOf(spcOwnerReference(spc)))
+	configMap := &v1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "ca"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(Equal(map[string]string{"ca.crt": "ca-bundle"}))
+
+	// the sync is recorded in the sync status and the private directory is removed
+	syncStatus := &secretsstorev1.SecretProviderClassSyncStatus{}
+	g.Expect(c.Get(context.TODO(), req.NamespacedName, syncStatus)).To(Succeed())
+	g.Expect(syncStatus.OwnerReferences).To(ConsistOf(spcOwnerReference(spc)))
+	g.Expect(syncStatus.Status.SyncedSecrets).To(Equal([]string{"secret1"}))
+	g.Expect(syncStatus.Status.SyncedConfigMaps).To(Equal([]string{"ca"}))
+	g.Expect(syncStatus.Status.Objects).To(Equal([]secretsstorev1.SecretProviderClassObject{{ID: "ca", Version: "v1"}, {ID: "password", Version: "v1"}}))
+	g.Expect(syncStatus.Status.LastSuccessfulSyncTime).NotTo(BeNil())
+	g.Expect(meta.IsStatusConditionTrue(syncStatus.Status.Conditions, secretsstorev1.ConditionTypeSynced)).To(BeTrue())
+	g.Expect(meta.IsStatusConditionTrue(syncStatus.Status.Conditions, secretsstorev1.ConditionTypeProviderHealthy)).To(BeTrue())
+	_, err = os.Stat(filepath.Join(reconciler.syncDir, "default", "spc1"))
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+
+	// the spc isn't synced again within the sync interval
+	result, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically("<", time.Minute))
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+	// the sync status is deleted when the standalone sync is disabled and the synced objects are kept
+	g.Expect(c.Get(context.TODO(), req.NamespacedName, spc)).To(Succeed())
+	spc.Annotations = nil
+	g.Expect(c.Update(context.TODO(), spc)).To(Succeed())
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	err = c.Get(context.TODO(), req.NamespacedName, syncStatus)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, secret)).To(Succeed())
+}
+
+func TestSecretProviderClassSyncReconcileExistingSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spc := newStandaloneSyncSPC()
+	spc.Spec.ConfigMapObjects = nil
+	existing := newSecret("secret1", "default", map[string]string{SecretManagedLabel: "true"}, nil)
+	existing.ResourceVersion = ""
+	existing.Data = map[string][]byte{"password": []byte("old")}
+	existing.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "pod-uid"}}
+	c := fake.NewFakeClientWithScheme(scheme, spc, existing)
+	reconciler := newSyncReconciler(t, c, []*v1alpha1.File{{Path: "password", Mode: 0644, Contents: []byte("secret")}}, map[string]string{"password": "v1"})
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "spc1"}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the managed secret is updated and the spc is added to its owners
+	secret := &v1.Secret{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+	g.Expect(secret.OwnerReferences).To(HaveLen(2))
+}
+
+func TestSecretProviderClassSyncReconcileFilesMissing(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	c := fake.NewFakeClientWithScheme(scheme, newStandaloneSyncSPC())
+	// the provider returns the object versions without the files
+	reconciler := newSyncReconciler(t, c, nil, map[string]string{"password": "v1"})
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "spc1"}}
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).To(HaveOccurred())
+
+	syncStatus := &secretsstorev1.SecretProviderClassSyncStatus{}
+	g.Expect(c.Get(context.TODO(), req.NamespacedName, syncStatus)).To(Succeed())
+	synced := meta.FindStatusCondition(syncStatus.Status.Conditions, secretsstorev1.ConditionTypeSynced)
+	g.Expect(synced).NotTo(BeNil())
+	g.Expect(synced.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(synced.Reason).To(Equal(internalerrors.MountResponseFilesMissing))
+	g.Expect(syncStatus.Status.LastSuccessfulSyncTime).To(BeNil())
+	g.Expect(syncStatus.Status.LastAttemptTime).NotTo(BeNil())
+
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, &v1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestSecretProviderClassSyncReconcileNotAllowed(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	spc := newStandaloneSyncSPC()
+	spc.Spec.ConfigMapObjects = nil
+	spc.Annotations[secretsstorev1.StandaloneSyncServiceAccountAnnotation] = "sa1"
+	spc.Annotations[secretsstorev1.StandaloneSyncSecretRefAnnotation] = "creds"
+	sa := &v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa1", Namespace: "default"}}
+	creds := newSecret("creds", "default", nil, nil)
+	creds.ResourceVersion = ""
+	c := fake.NewFakeClientWithScheme(scheme, spc, sa, creds)
+	reconciler := newSyncReconciler(t, c, []*v1alpha1.File{{Path: "password", Mode: 0644, Contents: []byte("secret")}}, map[string]string{"password": "v1"})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "spc1"}}
+
+	expectNotAllowed := func() {
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		g.Expect(err).To(HaveOccurred())
+		syncStatus := &secretsstorev1.SecretProviderClassSyncStatus{}
+		g.Expect(c.Get(context.TODO(), req.NamespacedName, syncStatus)).To(Succeed())
+		synced := meta.FindStatusCondition(syncStatus.Status.Conditions, secretsstorev1.ConditionTypeSynced)
+		g.Expect(synced).NotTo(BeNil())
+		g.Expect(synced.Reason).To(Equal(internalerrors.StandaloneSyncNotAllowed))
+		err = c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, &v1.Secret{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+
+	// the service account doesn't allow the spc
+	expectNotAllowed()
+
+	// the service account allows another spc
+	sa.Annotations = map[string]string{secretsstorev1.StandaloneSyncAllowedAnnotation: "spc2"}
+	g.Expect(c.Update(context.TODO(), sa)).To(Succeed())
+	expectNotAllowed()
+
+	// the service account allows the spc, but the secret doesn't
+	sa.Annotations[secretsstorev1.StandaloneSyncAllowedAnnotation] = "spc2, spc1"
+	g.Expect(c.Update(context.TODO(), sa)).To(Succeed())
+	expectNotAllowed()
+
+	// the service account and secret allow the spc
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "creds"}, creds)).To(Succeed())
+	creds.Annotations = map[string]string{secretsstorev1.StandaloneSyncAllowedAnnotation: "spc1"}
+	g.Expect(c.Update(context.TODO(), creds)).To(Succeed())
+	_, err = reconciler.Reconcile(context.TODO(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "secret1"}, &v1.Secret{})).To(Succeed())
+}
@@ -15,7 +15,8 @@ limitations under the License.
 */
 
 // Package syncsecret holds the RBAC permission annotations for the controller
-// to sync k8s secrets so that they can be built and applied separately.
+// to sync k8s secrets and configmaps so that they can be built and applied separately.
 package syncsecret
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
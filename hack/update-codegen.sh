@@ -27,7 +27,7 @@ GOBIN="$(go env GOBIN)"
 gobin="${GOBIN:-$(go env GOPATH)/bin}"
 
 OUTPUT_PKG=sigs.k8s.io/secrets-store-csi-driver/pkg/client
-FQ_APIS=sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1
+FQ_APIS=sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1,sigs.k8s.io/secrets-store-csi-driver/apis/v1
 APIS_PKG=sigs.k8s.io/secrets-store-csi-driver
 CLIENTSET_NAME=versioned
 CLIENTSET_PKG_NAME=clientset
@@ -64,7 +64,7 @@ echo "Generating informers at ${OUTPUT_PKG}/informers"
          "${COMMON_FLAGS}"
 
 echo "Generating register at ${FQ_APIS}"
-"${gobin}/register-gen" --output-package "${FQ_APIS}" --input-dirs ${FQ_APIS} "${COMMON_FLAGS}"
+"${gobin}/register-gen" --output-package "${APIS_PKG}/apis" --input-dirs "${FQ_APIS}" "${COMMON_FLAGS}"
 
 # reference from https://github.com/servicemeshinterface/smi-sdk-go/blob/master/hack/update-codegen.sh
 # replace secretsstore.csi.x-k8s.io with secrets-store.csi.x-k8s.io after code generation
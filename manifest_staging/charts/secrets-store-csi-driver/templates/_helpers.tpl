@@ -47,3 +47,25 @@ Return the appropriate apiVersion for CSIDriver.
 {{- print "storage.k8s.io/v1beta1" -}}
 {{- end -}}
 {{- end -}}
+
+{{/*
+Return the minimum provider versions as comma-separated provider=version pairs.
+*/}}
+{{- define "sscd.minProviderVersions" -}}
+{{- $versions := list -}}
+{{- range $provider, $version := .Values.minProviderVersions -}}
+{{- $versions = append $versions (printf "%s=%s" $provider $version) -}}
+{{- end -}}
+{{- join "," $versions -}}
+{{- end -}}
+
+{{/*
+Return the provider maximum mount sizes as comma-separated provider=size pairs.
+*/}}
+{{- define "sscd.providerMaxMountSizes" -}}
+{{- $sizes := list -}}
+{{- range $provider, $size := .Values.providerMaxMountSizes -}}
+{{- $sizes = append $sizes (printf "%s=%s" $provider $size) -}}
+{{- end -}}
+{{- join "," $sizes -}}
+{{- end -}}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	rest "k8s.io/client-go/rest"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned/scheme"
+)
+
+type SecretsstoreV1Interface interface {
+	RESTClient() rest.Interface
+	ClusterSecretProviderClassesGetter
+	SecretProviderClassesGetter
+	SecretProviderClassPodStatusesGetter
+	SecretProviderClassSyncStatusesGetter
+	SecretsStorePoliciesGetter
+}
+
+// SecretsstoreV1Client is used to interact with features provided by the secrets-store.csi.x-k8s.io group.
+type SecretsstoreV1Client struct {
+	restClient rest.Interface
+}
+
+func (c *SecretsstoreV1Client) ClusterSecretProviderClasses() ClusterSecretProviderClassInterface {
+	return newClusterSecretProviderClasses(c)
+}
+
+func (c *SecretsstoreV1Client) SecretProviderClasses(namespace string) SecretProviderClassInterface {
+	return newSecretProviderClasses(c, namespace)
+}
+
+func (c *SecretsstoreV1Client) SecretProviderClassPodStatuses(namespace string) SecretProviderClassPodStatusInterface {
+	return newSecretProviderClassPodStatuses(c, namespace)
+}
+
+func (c *SecretsstoreV1Client) SecretProviderClassSyncStatuses(namespace string) SecretProviderClassSyncStatusInterface {
+	return newSecretProviderClassSyncStatuses(c, namespace)
+}
+
+func (c *SecretsstoreV1Client) SecretsStorePolicies(namespace string) SecretsStorePolicyInterface {
+	return newSecretsStorePolicies(c, namespace)
+}
+
+// NewForConfig creates a new SecretsstoreV1Client for the given config.
+func NewForConfig(c *rest.Config) (*SecretsstoreV1Client, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	client, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretsstoreV1Client{client}, nil
+}
+
+// NewForConfigOrDie creates a new SecretsstoreV1Client for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(c *rest.Config) *SecretsstoreV1Client {
+	client, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// New creates a new SecretsstoreV1Client for the given RESTClient.
+func New(c rest.Interface) *SecretsstoreV1Client {
+	return &SecretsstoreV1Client{c}
+}
+
+func setConfigDefaults(config *rest.Config) error {
+	gv := v1.SchemeGroupVersion
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	return nil
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *SecretsstoreV1Client) RESTClient() rest.Interface {
+	if c == nil {
+		return nil
+	}
+	return c.restClient
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	scheme "sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned/scheme"
+)
+
+// ClusterSecretProviderClassesGetter has a method to return a ClusterSecretProviderClassInterface.
+// A group's client should implement this interface.
+type ClusterSecretProviderClassesGetter interface {
+	ClusterSecretProviderClasses() ClusterSecretProviderClassInterface
+}
+
+// ClusterSecretProviderClassInterface has methods to work with ClusterSecretProviderClass resources.
+type ClusterSecretProviderClassInterface interface {
+	Create(ctx context.Context, clusterSecretProviderClass *v1.ClusterSecretProviderClass, opts metav1.CreateOptions) (*v1.ClusterSecretProviderClass, error)
+	Update(ctx context.Context, clusterSecretProviderClass *v1.ClusterSecretProviderClass, opts metav1.UpdateOptions) (*v1.ClusterSecretProviderClass, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ClusterSecretProviderClass, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ClusterSecretProviderClassList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterSecretProviderClass, err error)
+	ClusterSecretProviderClassExpansion
+}
+
+// clusterSecretProviderClasses implements ClusterSecretProviderClassInterface
+type clusterSecretProviderClasses struct {
+	client rest.Interface
+}
+
+// newClusterSecretProviderClasses returns a ClusterSecretProviderClasses
+func newClusterSecretProviderClasses(c *SecretsstoreV1Client) *clusterSecretProviderClasses {
+	return &clusterSecretProviderClasses{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterSecretProviderClass, and returns the corresponding clusterSecretProviderClass object, and an error if there is any.
+func (c *clusterSecretProviderClasses) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ClusterSecretProviderClass, err error) {
+	result = &v1.ClusterSecretProviderClass{}
+	err = c.client.Get().
+		Resource("clustersecretproviderclasses").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterSecretProviderClasses that match those selectors.
+func (c *clusterSecretProviderClasses) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ClusterSecretProviderClassList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ClusterSecretProviderClassList{}
+	err = c.client.Get().
+		Resource("clustersecretproviderclasses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterSecretProviderClasses.
+func (c *clusterSecretProviderClasses) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clustersecretproviderclasses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clusterSecretProviderClass and creates it.  Returns the server's representation of the clusterSecretProviderClass, and an error, if there is any.
+func (c *clusterSecretProviderClasses) Create(ctx context.Context, clusterSecretProviderClass *v1.ClusterSecretProviderClass, opts metav1.CreateOptions) (result *v1.ClusterSecretProviderClass, err error) {
+	result = &v1.ClusterSecretProviderClass{}
+	err = c.client.Post().
+		Resource("clustersecretproviderclasses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterSecretProviderClass).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterSecretProviderClass and updates it. Returns the server's representation of the clusterSecretProviderClass, and an error, if there is any.
+func (c *clusterSecretProviderClasses) Update(ctx context.Context, clusterSecretProviderClass *v1.ClusterSecretProviderClass, opts metav1.UpdateOptions) (result *v1.ClusterSecretProviderClass, err error) {
+	result = &v1.ClusterSecretProviderClass{}
+	err = c.client.Put().
+		Resource("clustersecretproviderclasses").
+		Name(clusterSecretProviderClass.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterSecretProviderClass).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterSecretProviderClass and deletes it. Returns an error if one occurs.
+func (c *clusterSecretProviderClasses) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clustersecretproviderclasses").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterSecretProviderClasses) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clustersecretproviderclasses").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterSecretProviderClass.
+func (c *clusterSecretProviderClasses) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterSecretProviderClass, err error) {
+	result = &v1.ClusterSecretProviderClass{}
+	err = c.client.Patch(pt).
+		Resource("clustersecretproviderclasses").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned/typed/apis/v1"
+)
+
+type FakeSecretsstoreV1 struct {
+	*testing.Fake
+}
+
+func (c *FakeSecretsstoreV1) ClusterSecretProviderClasses() v1.ClusterSecretProviderClassInterface {
+	return &FakeClusterSecretProviderClasses{c}
+}
+
+func (c *FakeSecretsstoreV1) SecretProviderClasses(namespace string) v1.SecretProviderClassInterface {
+	return &FakeSecretProviderClasses{c, namespace}
+}
+
+func (c *FakeSecretsstoreV1) SecretProviderClassPodStatuses(namespace string) v1.SecretProviderClassPodStatusInterface {
+	return &FakeSecretProviderClassPodStatuses{c, namespace}
+}
+
+func (c *FakeSecretsstoreV1) SecretProviderClassSyncStatuses(namespace string) v1.SecretProviderClassSyncStatusInterface {
+	return &FakeSecretProviderClassSyncStatuses{c, namespace}
+}
+
+func (c *FakeSecretsstoreV1) SecretsStorePolicies(namespace string) v1.SecretsStorePolicyInterface {
+	return &FakeSecretsStorePolicies{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeSecretsstoreV1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	apisv1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// FakeClusterSecretProviderClasses implements ClusterSecretProviderClassInterface
+type FakeClusterSecretProviderClasses struct {
+	Fake *FakeSecretsstoreV1
+}
+
+var clustersecretproviderclassesResource = schema.GroupVersionResource{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Resource: "clustersecretproviderclasses"}
+
+var clustersecretproviderclassesKind = schema.GroupVersionKind{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Kind: "ClusterSecretProviderClass"}
+
+// Get takes name of the clusterSecretProviderClass, and returns the corresponding clusterSecretProviderClass object, and an error if there is any.
+func (c *FakeClusterSecretProviderClasses) Get(ctx context.Context, name string, options v1.GetOptions) (result *apisv1.ClusterSecretProviderClass, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clustersecretproviderclassesResource, name), &apisv1.ClusterSecretProviderClass{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.ClusterSecretProviderClass), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterSecretProviderClasses that match those selectors.
+func (c *FakeClusterSecretProviderClasses) List(ctx context.Context, opts v1.ListOptions) (result *apisv1.ClusterSecretProviderClassList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clustersecretproviderclassesResource, clustersecretproviderclassesKind, opts), &apisv1.ClusterSecretProviderClassList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &apisv1.ClusterSecretProviderClassList{ListMeta: obj.(*apisv1.ClusterSecretProviderClassList).ListMeta}
+	for _, item := range obj.(*apisv1.ClusterSecretProviderClassList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterSecretProviderClasses.
+func (c *FakeClusterSecretProviderClasses) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clustersecretproviderclassesResource, opts))
+}
+
+// Create takes the representation of a clusterSecretProviderClass and creates it.  Returns the server's representation of the clusterSecretProviderClass, and an error, if there is any.
+func (c *FakeClusterSecretProviderClasses) Create(ctx context.Context, clusterSecretProviderClass *apisv1.ClusterSecretProviderClass, opts v1.CreateOptions) (result *apisv1.ClusterSecretProviderClass, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clustersecretproviderclassesResource, clusterSecretProviderClass), &apisv1.ClusterSecretProviderClass{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.ClusterSecretProviderClass), err
+}
+
+// Update takes the representation of a clusterSecretProviderClass and updates it. Returns the server's representation of the clusterSecretProviderClass, and an error, if there is any.
+func (c *FakeClusterSecretProviderClasses) Update(ctx context.Context, clusterSecretProviderClass *apisv1.ClusterSecretProviderClass, opts v1.UpdateOptions) (result *apisv1.ClusterSecretProviderClass, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clustersecretproviderclassesResource, clusterSecretProviderClass), &apisv1.ClusterSecretProviderClass{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.ClusterSecretProviderClass), err
+}
+
+// Delete takes name of the clusterSecretProviderClass and deletes it. Returns an error if one occurs.
+func (c *FakeClusterSecretProviderClasses) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(clustersecretproviderclassesResource, name), &apisv1.ClusterSecretProviderClass{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterSecretProviderClasses) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clustersecretproviderclassesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &apisv1.ClusterSecretProviderClassList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterSecretProviderClass.
+func (c *FakeClusterSecretProviderClasses) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *apisv1.ClusterSecretProviderClass, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clustersecretproviderclassesResource, name, pt, data, subresources...), &apisv1.ClusterSecretProviderClass{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.ClusterSecretProviderClass), err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	apisv1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// FakeSecretProviderClassSyncStatuses implements SecretProviderClassSyncStatusInterface
+type FakeSecretProviderClassSyncStatuses struct {
+	Fake *FakeSecretsstoreV1
+	ns   string
+}
+
+var secretproviderclasssyncstatusesResource = schema.GroupVersionResource{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Resource: "secretproviderclasssyncstatuses"}
+
+var secretproviderclasssyncstatusesKind = schema.GroupVersionKind{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Kind: "SecretProviderClassSyncStatus"}
+
+// Get takes name of the secretProviderClassSyncStatus, and returns the corresponding secretProviderClassSyncStatus object, and an error if there is any.
+func (c *FakeSecretProviderClassSyncStatuses) Get(ctx context.Context, name string, options v1.GetOptions) (result *apisv1.SecretProviderClassSyncStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(secretproviderclasssyncstatusesResource, c.ns, name), &apisv1.SecretProviderClassSyncStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretProviderClassSyncStatus), err
+}
+
+// List takes label and field selectors, and returns the list of SecretProviderClassSyncStatuses that match those selectors.
+func (c *FakeSecretProviderClassSyncStatuses) List(ctx context.Context, opts v1.ListOptions) (result *apisv1.SecretProviderClassSyncStatusList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(secretproviderclasssyncstatusesResource, secretproviderclasssyncstatusesKind, c.ns, opts), &apisv1.SecretProviderClassSyncStatusList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &apisv1.SecretProviderClassSyncStatusList{ListMeta: obj.(*apisv1.SecretProviderClassSyncStatusList).ListMeta}
+	for _, item := range obj.(*apisv1.SecretProviderClassSyncStatusList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested secretProviderClassSyncStatuses.
+func (c *FakeSecretProviderClassSyncStatuses) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(secretproviderclasssyncstatusesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a secretProviderClassSyncStatus and creates it.  Returns the server's representation of the secretProviderClassSyncStatus, and an error, if there is any.
+func (c *FakeSecretProviderClassSyncStatuses) Create(ctx context.Context, secretProviderClassSyncStatus *apisv1.SecretProviderClassSyncStatus, opts v1.CreateOptions) (result *apisv1.SecretProviderClassSyncStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(secretproviderclasssyncstatusesResource, c.ns, secretProviderClassSyncStatus), &apisv1.SecretProviderClassSyncStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretProviderClassSyncStatus), err
+}
+
+// Update takes the representation of a secretProviderClassSyncStatus and updates it. Returns the server's representation of the secretProviderClassSyncStatus, and an error, if there is any.
+func (c *FakeSecretProviderClassSyncStatuses) Update(ctx context.Context, secretProviderClassSyncStatus *apisv1.SecretProviderClassSyncStatus, opts v1.UpdateOptions) (result *apisv1.SecretProviderClassSyncStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(secretproviderclasssyncstatusesResource, c.ns, secretProviderClassSyncStatus), &apisv1.SecretProviderClassSyncStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretProviderClassSyncStatus), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeSecretProviderClassSyncStatuses) UpdateStatus(ctx context.Context, secretProviderClassSyncStatus *apisv1.SecretProviderClassSyncStatus, opts v1.UpdateOptions) (*apisv1.SecretProviderClassSyncStatus, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(secretproviderclasssyncstatusesResource, "status", c.ns, secretProviderClassSyncStatus), &apisv1.SecretProviderClassSyncStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretProviderClassSyncStatus), err
+}
+
+// Delete takes name of the secretProviderClassSyncStatus and deletes it. Returns an error if one occurs.
+func (c *FakeSecretProviderClassSyncStatuses) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(secretproviderclasssyncstatusesResource, c.ns, name), &apisv1.SecretProviderClassSyncStatus{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSecretProviderClassSyncStatuses) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(secretproviderclasssyncstatusesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &apisv1.SecretProviderClassSyncStatusList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched secretProviderClassSyncStatus.
+func (c *FakeSecretProviderClassSyncStatuses) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *apisv1.SecretProviderClassSyncStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(secretproviderclasssyncstatusesResource, c.ns, name, pt, data, subresources...), &apisv1.SecretProviderClassSyncStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretProviderClassSyncStatus), err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	apisv1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// FakeSecretsStorePolicies implements SecretsStorePolicyInterface
+type FakeSecretsStorePolicies struct {
+	Fake *FakeSecretsstoreV1
+	ns   string
+}
+
+var secretsstorepoliciesResource = schema.GroupVersionResource{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Resource: "secretsstorepolicies"}
+
+var secretsstorepoliciesKind = schema.GroupVersionKind{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Kind: "SecretsStorePolicy"}
+
+// Get takes name of the secretsStorePolicy, and returns the corresponding secretsStorePolicy object, and an error if there is any.
+func (c *FakeSecretsStorePolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *apisv1.SecretsStorePolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(secretsstorepoliciesResource, c.ns, name), &apisv1.SecretsStorePolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretsStorePolicy), err
+}
+
+// List takes label and field selectors, and returns the list of SecretsStorePolicies that match those selectors.
+func (c *FakeSecretsStorePolicies) List(ctx context.Context, opts v1.ListOptions) (result *apisv1.SecretsStorePolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(secretsstorepoliciesResource, secretsstorepoliciesKind, c.ns, opts), &apisv1.SecretsStorePolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &apisv1.SecretsStorePolicyList{ListMeta: obj.(*apisv1.SecretsStorePolicyList).ListMeta}
+	for _, item := range obj.(*apisv1.SecretsStorePolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested secretsStorePolicies.
+func (c *FakeSecretsStorePolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(secretsstorepoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a secretsStorePolicy and creates it.  Returns the server's representation of the secretsStorePolicy, and an error, if there is any.
+func (c *FakeSecretsStorePolicies) Create(ctx context.Context, secretsStorePolicy *apisv1.SecretsStorePolicy, opts v1.CreateOptions) (result *apisv1.SecretsStorePolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(secretsstorepoliciesResource, c.ns, secretsStorePolicy), &apisv1.SecretsStorePolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretsStorePolicy), err
+}
+
+// Update takes the representation of a secretsStorePolicy and updates it. Returns the server's representation of the secretsStorePolicy, and an error, if there is any.
+func (c *FakeSecretsStorePolicies) Update(ctx context.Context, secretsStorePolicy *apisv1.SecretsStorePolicy, opts v1.UpdateOptions) (result *apisv1.SecretsStorePolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(secretsstorepoliciesResource, c.ns, secretsStorePolicy), &apisv1.SecretsStorePolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretsStorePolicy), err
+}
+
+// Delete takes name of the secretsStorePolicy and deletes it. Returns an error if one occurs.
+func (c *FakeSecretsStorePolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(secretsstorepoliciesResource, c.ns, name), &apisv1.SecretsStorePolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSecretsStorePolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(secretsstorepoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &apisv1.SecretsStorePolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched secretsStorePolicy.
+func (c *FakeSecretsStorePolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *apisv1.SecretsStorePolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(secretsstorepoliciesResource, c.ns, name, pt, data, subresources...), &apisv1.SecretsStorePolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*apisv1.SecretsStorePolicy), err
+}
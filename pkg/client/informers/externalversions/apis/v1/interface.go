@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	internalinterfaces "sigs.k8s.io/secrets-store-csi-driver/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// ClusterSecretProviderClasses returns a ClusterSecretProviderClassInformer.
+	ClusterSecretProviderClasses() ClusterSecretProviderClassInformer
+	// SecretProviderClasses returns a SecretProviderClassInformer.
+	SecretProviderClasses() SecretProviderClassInformer
+	// SecretProviderClassPodStatuses returns a SecretProviderClassPodStatusInformer.
+	SecretProviderClassPodStatuses() SecretProviderClassPodStatusInformer
+	// SecretProviderClassSyncStatuses returns a SecretProviderClassSyncStatusInformer.
+	SecretProviderClassSyncStatuses() SecretProviderClassSyncStatusInformer
+	// SecretsStorePolicies returns a SecretsStorePolicyInformer.
+	SecretsStorePolicies() SecretsStorePolicyInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ClusterSecretProviderClasses returns a ClusterSecretProviderClassInformer.
+func (v *version) ClusterSecretProviderClasses() ClusterSecretProviderClassInformer {
+	return &clusterSecretProviderClassInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// SecretProviderClasses returns a SecretProviderClassInformer.
+func (v *version) SecretProviderClasses() SecretProviderClassInformer {
+	return &secretProviderClassInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SecretProviderClassPodStatuses returns a SecretProviderClassPodStatusInformer.
+func (v *version) SecretProviderClassPodStatuses() SecretProviderClassPodStatusInformer {
+	return &secretProviderClassPodStatusInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SecretProviderClassSyncStatuses returns a SecretProviderClassSyncStatusInformer.
+func (v *version) SecretProviderClassSyncStatuses() SecretProviderClassSyncStatusInformer {
+	return &secretProviderClassSyncStatusInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SecretsStorePolicies returns a SecretsStorePolicyInformer.
+func (v *version) SecretsStorePolicies() SecretsStorePolicyInformer {
+	return &secretsStorePolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
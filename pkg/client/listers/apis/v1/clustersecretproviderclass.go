@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// ClusterSecretProviderClassLister helps list ClusterSecretProviderClasses.
+// All objects returned here must be treated as read-only.
+type ClusterSecretProviderClassLister interface {
+	// List lists all ClusterSecretProviderClasses in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ClusterSecretProviderClass, err error)
+	// Get retrieves the ClusterSecretProviderClass from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ClusterSecretProviderClass, error)
+	ClusterSecretProviderClassListerExpansion
+}
+
+// clusterSecretProviderClassLister implements the ClusterSecretProviderClassLister interface.
+type clusterSecretProviderClassLister struct {
+	indexer cache.Indexer
+}
+
+// NewClusterSecretProviderClassLister returns a new ClusterSecretProviderClassLister.
+func NewClusterSecretProviderClassLister(indexer cache.Indexer) ClusterSecretProviderClassLister {
+	return &clusterSecretProviderClassLister{indexer: indexer}
+}
+
+// List lists all ClusterSecretProviderClasses in the indexer.
+func (s *clusterSecretProviderClassLister) List(selector labels.Selector) (ret []*v1.ClusterSecretProviderClass, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ClusterSecretProviderClass))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClusterSecretProviderClass from the index for a given name.
+func (s *clusterSecretProviderClassLister) Get(name string) (*v1.ClusterSecretProviderClass, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("clustersecretproviderclass"), name)
+	}
+	return obj.(*v1.ClusterSecretProviderClass), nil
+}
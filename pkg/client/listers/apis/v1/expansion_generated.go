@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+// ClusterSecretProviderClassListerExpansion allows custom methods to be added to
+// ClusterSecretProviderClassLister.
+type ClusterSecretProviderClassListerExpansion interface{}
+
+// SecretProviderClassListerExpansion allows custom methods to be added to
+// SecretProviderClassLister.
+type SecretProviderClassListerExpansion interface{}
+
+// SecretProviderClassNamespaceListerExpansion allows custom methods to be added to
+// SecretProviderClassNamespaceLister.
+type SecretProviderClassNamespaceListerExpansion interface{}
+
+// SecretProviderClassPodStatusListerExpansion allows custom methods to be added to
+// SecretProviderClassPodStatusLister.
+type SecretProviderClassPodStatusListerExpansion interface{}
+
+// SecretProviderClassPodStatusNamespaceListerExpansion allows custom methods to be added to
+// SecretProviderClassPodStatusNamespaceLister.
+type SecretProviderClassPodStatusNamespaceListerExpansion interface{}
+
+// SecretProviderClassSyncStatusListerExpansion allows custom methods to be added to
+// SecretProviderClassSyncStatusLister.
+type SecretProviderClassSyncStatusListerExpansion interface{}
+
+// SecretProviderClassSyncStatusNamespaceListerExpansion allows custom methods to be added to
+// SecretProviderClassSyncStatusNamespaceLister.
+type SecretProviderClassSyncStatusNamespaceListerExpansion interface{}
+
+// SecretsStorePolicyListerExpansion allows custom methods to be added to
+// SecretsStorePolicyLister.
+type SecretsStorePolicyListerExpansion interface{}
+
+// SecretsStorePolicyNamespaceListerExpansion allows custom methods to be added to
+// SecretsStorePolicyNamespaceLister.
+type SecretsStorePolicyNamespaceListerExpansion interface{}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// SecretProviderClassSyncStatusLister helps list SecretProviderClassSyncStatuses.
+// All objects returned here must be treated as read-only.
+type SecretProviderClassSyncStatusLister interface {
+	// List lists all SecretProviderClassSyncStatuses in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.SecretProviderClassSyncStatus, err error)
+	// SecretProviderClassSyncStatuses returns an object that can list and get SecretProviderClassSyncStatuses.
+	SecretProviderClassSyncStatuses(namespace string) SecretProviderClassSyncStatusNamespaceLister
+	SecretProviderClassSyncStatusListerExpansion
+}
+
+// secretProviderClassSyncStatusLister implements the SecretProviderClassSyncStatusLister interface.
+type secretProviderClassSyncStatusLister struct {
+	indexer cache.Indexer
+}
+
+// NewSecretProviderClassSyncStatusLister returns a new SecretProviderClassSyncStatusLister.
+func NewSecretProviderClassSyncStatusLister(indexer cache.Indexer) SecretProviderClassSyncStatusLister {
+	return &secretProviderClassSyncStatusLister{indexer: indexer}
+}
+
+// List lists all SecretProviderClassSyncStatuses in the indexer.
+func (s *secretProviderClassSyncStatusLister) List(selector labels.Selector) (ret []*v1.SecretProviderClassSyncStatus, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SecretProviderClassSyncStatus))
+	})
+	return ret, err
+}
+
+// SecretProviderClassSyncStatuses returns an object that can list and get SecretProviderClassSyncStatuses.
+func (s *secretProviderClassSyncStatusLister) SecretProviderClassSyncStatuses(namespace string) SecretProviderClassSyncStatusNamespaceLister {
+	return secretProviderClassSyncStatusNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SecretProviderClassSyncStatusNamespaceLister helps list and get SecretProviderClassSyncStatuses.
+// All objects returned here must be treated as read-only.
+type SecretProviderClassSyncStatusNamespaceLister interface {
+	// List lists all SecretProviderClassSyncStatuses in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.SecretProviderClassSyncStatus, err error)
+	// Get retrieves the SecretProviderClassSyncStatus from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.SecretProviderClassSyncStatus, error)
+	SecretProviderClassSyncStatusNamespaceListerExpansion
+}
+
+// secretProviderClassSyncStatusNamespaceLister implements the SecretProviderClassSyncStatusNamespaceLister
+// interface.
+type secretProviderClassSyncStatusNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SecretProviderClassSyncStatuses in the indexer for a given namespace.
+func (s secretProviderClassSyncStatusNamespaceLister) List(selector labels.Selector) (ret []*v1.SecretProviderClassSyncStatus, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SecretProviderClassSyncStatus))
+	})
+	return ret, err
+}
+
+// Get retrieves the SecretProviderClassSyncStatus from the indexer for a given namespace and name.
+func (s secretProviderClassSyncStatusNamespaceLister) Get(name string) (*v1.SecretProviderClassSyncStatus, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("secretproviderclasssyncstatus"), name)
+	}
+	return obj.(*v1.SecretProviderClassSyncStatus), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// SecretsStorePolicyLister helps list SecretsStorePolicies.
+// All objects returned here must be treated as read-only.
+type SecretsStorePolicyLister interface {
+	// List lists all SecretsStorePolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.SecretsStorePolicy, err error)
+	// SecretsStorePolicies returns an object that can list and get SecretsStorePolicies.
+	SecretsStorePolicies(namespace string) SecretsStorePolicyNamespaceLister
+	SecretsStorePolicyListerExpansion
+}
+
+// secretsStorePolicyLister implements the SecretsStorePolicyLister interface.
+type secretsStorePolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewSecretsStorePolicyLister returns a new SecretsStorePolicyLister.
+func NewSecretsStorePolicyLister(indexer cache.Indexer) SecretsStorePolicyLister {
+	return &secretsStorePolicyLister{indexer: indexer}
+}
+
+// List lists all SecretsStorePolicies in the indexer.
+func (s *secretsStorePolicyLister) List(selector labels.Selector) (ret []*v1.SecretsStorePolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SecretsStorePolicy))
+	})
+	return ret, err
+}
+
+// SecretsStorePolicies returns an object that can list and get SecretsStorePolicies.
+func (s *secretsStorePolicyLister) SecretsStorePolicies(namespace string) SecretsStorePolicyNamespaceLister {
+	return secretsStorePolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SecretsStorePolicyNamespaceLister helps list and get SecretsStorePolicies.
+// All objects returned here must be treated as read-only.
+type SecretsStorePolicyNamespaceLister interface {
+	// List lists all SecretsStorePolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.SecretsStorePolicy, err error)
+	// Get retrieves the SecretsStorePolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.SecretsStorePolicy, error)
+	SecretsStorePolicyNamespaceListerExpansion
+}
+
+// secretsStorePolicyNamespaceLister implements the SecretsStorePolicyNamespaceLister
+// interface.
+type secretsStorePolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SecretsStorePolicies in the indexer for a given namespace.
+func (s secretsStorePolicyNamespaceLister) List(selector labels.Selector) (ret []*v1.SecretsStorePolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SecretsStorePolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the SecretsStorePolicy from the indexer for a given namespace and name.
+func (s secretsStorePolicyNamespaceLister) Get(name string) (*v1.SecretsStorePolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("secretsstorepolicy"), name)
+	}
+	return obj.(*v1.SecretsStorePolicy), nil
+}
@@ -21,6 +21,12 @@ const (
 	FailedToEnsureMountPoint = "FailedToEnsureMountPoint"
 	// IncompatibleProviderVersion error
 	IncompatibleProviderVersion = "IncompatibleProviderVersion"
+	// ProviderUnhealthy error
+	// Indicates the provider failed the threshold of consecutive health checks.
+	ProviderUnhealthy = "ProviderUnhealthy"
+	// ProviderCircuitOpen error
+	// Indicates the circuit breaker of the provider is open after repeated failed calls.
+	ProviderCircuitOpen = "ProviderCircuitOpen"
 	// ProviderError error
 	ProviderError = "ProviderError"
 	// FailedToMount error
@@ -45,4 +51,32 @@ const (
 	PodVolumeNotFound = "PodVolumeNotFound"
 	// FileWriteError error
 	FileWriteError = "FileWriteError"
+	// FileTransformError error
+	// Indicates the file transforms in the secret provider class could not be applied to the files returned by the provider.
+	FileTransformError = "FileTransformError"
+	// TemplateRenderError error
+	// Indicates the templates in the secret provider class could not be rendered with the mounted objects.
+	TemplateRenderError = "TemplateRenderError"
+	// FailedToExpandParameters error
+	// Indicates the placeholders in the secret provider class parameters could not be expanded with the pod metadata.
+	FailedToExpandParameters = "FailedToExpandParameters"
+	// ParametersFromNotFound error
+	// Indicates a configmap or secret referenced in the secret provider class parametersFrom could not be read.
+	ParametersFromNotFound = "ParametersFromNotFound"
+	// SecretsStorePolicyViolation error
+	// Indicates a secrets store policy in the namespace doesn't allow the provider, the parameters or the secret sync of the secret provider class.
+	SecretsStorePolicyViolation = "SecretsStorePolicyViolation"
+	// FailedToSyncSecret error
+	// Indicates one or more secret objects in the secret provider class could not be synced as Kubernetes secrets.
+	// #nosec G101 (Ref: https://github.com/securego/gosec/issues/295)
+	FailedToSyncSecret = "FailedToSyncSecret"
+	// MountResponseFilesMissing error
+	// Indicates the provider didn't return the files in the mount response, which is required for the standalone sync.
+	MountResponseFilesMissing = "MountResponseFilesMissing"
+	// MountResponseTooLarge error
+	// Indicates the files streamed by the provider exceed the maximum mount size of the provider.
+	MountResponseTooLarge = "MountResponseTooLarge"
+	// StandaloneSyncNotAllowed error
+	// Indicates the service account or secret named in the secret provider class annotations doesn't allow the standalone sync of the secret provider class.
+	StandaloneSyncNotAllowed = "StandaloneSyncNotAllowed"
 )
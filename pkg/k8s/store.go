@@ -34,6 +34,7 @@ import (
 // Informer holds the shared index informers
 type Informer struct {
 	NodePublishSecretRefSecret cache.SharedIndexInformer
+	ParametersFromConfigMap    cache.SharedIndexInformer
 }
 
 // Lister holds the object lister
@@ -41,10 +42,12 @@ type Lister struct {
 	NodePublishSecretRefSecret SecretLister
 }
 
-// Store for secrets with label 'secrets-store.csi.k8s.io/used'
+// Store for secrets and configmaps with label 'secrets-store.csi.k8s.io/used'
 type Store interface {
 	// GetNodePublishSecretRefSecret returns the NodePublishSecretRef secret matching name and namespace
 	GetNodePublishSecretRefSecret(name, namespace string) (*v1.Secret, error)
+	// AddEventHandler adds the event handler to the secret and configmap informers
+	AddEventHandler(handler cache.ResourceEventHandler)
 	// Run initializes and runs the informers
 	Run(stopCh <-chan struct{}) error
 }
@@ -54,7 +57,7 @@ type k8sStore struct {
 	listers   *Lister
 }
 
-// New returns store.Store for NodePublishSecretRefSecret
+// New returns store.Store for NodePublishSecretRefSecret and ParametersFromConfigMap
 func New(kubeClient kubernetes.Interface, resyncPeriod time.Duration, filteredWatchSecret bool) (Store, error) {
 	store := &k8sStore{
 		informers: &Informer{},
@@ -63,6 +66,7 @@ func New(kubeClient kubernetes.Interface, resyncPeriod time.Duration, filteredWa
 
 	store.informers.NodePublishSecretRefSecret = newNodePublishSecretRefSecretInformer(kubeClient, resyncPeriod, filteredWatchSecret)
 	store.listers.NodePublishSecretRefSecret.Store = store.informers.NodePublishSecretRefSecret.GetStore()
+	store.informers.ParametersFromConfigMap = newParametersFromConfigMapInformer(kubeClient, resyncPeriod, filteredWatchSecret)
 
 	return store, nil
 }
@@ -77,11 +81,19 @@ func (s k8sStore) GetNodePublishSecretRefSecret(name, namespace string) (*v1.Sec
 	return s.listers.NodePublishSecretRefSecret.GetWithKey(fmt.Sprintf("%s/%s", namespace, name))
 }
 
+// AddEventHandler adds the event handler to the secret and configmap informers
+func (s k8sStore) AddEventHandler(handler cache.ResourceEventHandler) {
+	s.informers.NodePublishSecretRefSecret.AddEventHandler(handler)
+	s.informers.ParametersFromConfigMap.AddEventHandler(handler)
+}
+
 func (i *Informer) run(stopCh <-chan struct{}) error {
 	go i.NodePublishSecretRefSecret.Run(stopCh)
+	go i.ParametersFromConfigMap.Run(stopCh)
 
 	synced := []cache.InformerSynced{
 		i.NodePublishSecretRefSecret.HasSynced,
+		i.ParametersFromConfigMap.HasSynced,
 	}
 	if !cache.WaitForCacheSync(stopCh, synced...) {
 		return fmt.Errorf("failed to sync informer caches")
@@ -104,8 +116,24 @@ func newNodePublishSecretRefSecretInformer(kubeClient kubernetes.Interface, resy
 	)
 }
 
+// newParametersFromConfigMapInformer returns a secret provider class parametersFrom configmap informer
+func newParametersFromConfigMapInformer(kubeClient kubernetes.Interface, resyncPeriod time.Duration, filteredWatch bool) cache.SharedIndexInformer {
+	var tweakListOptionsFunc internalinterfaces.TweakListOptionsFunc
+	if filteredWatch {
+		tweakListOptionsFunc = usedFilterForSecret()
+	}
+	return coreInformers.NewFilteredConfigMapInformer(
+		kubeClient,
+		v1.NamespaceAll,
+		resyncPeriod,
+		cache.Indexers{},
+		tweakListOptionsFunc,
+	)
+}
+
 // usedFilterForSecret returns tweak options to filter using used label (secrets-store.csi.k8s.io/used=true).
-// this label will need to be configured by user for NodePublishSecretRef secrets.
+// this label will need to be configured by user for NodePublishSecretRef secrets and the
+// parametersFrom secrets and configmaps.
 func usedFilterForSecret() internalinterfaces.TweakListOptionsFunc {
 	return func(options *metav1.ListOptions) {
 		options.LabelSelector = fmt.Sprintf("%s=true", controllers.SecretUsedLabel)
@@ -19,6 +19,7 @@ package rotation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -29,7 +30,9 @@ import (
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 
@@ -43,7 +46,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	"sigs.k8s.io/secrets-store-csi-driver/controllers"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned"
 	secretsStoreClient "sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned"
@@ -52,7 +55,10 @@ import (
 	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/k8sutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/policyutil"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/secretutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcpsutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/version"
 )
 
@@ -65,6 +71,9 @@ const (
 	k8sSecretRotationFailedReason   = "SecretRotationFailed"
 	k8sSecretRotationCompleteReason = "SecretRotationComplete"
 
+	k8sConfigMapRotationFailedReason   = "ConfigMapRotationFailed"
+	k8sConfigMapRotationCompleteReason = "ConfigMapRotationComplete"
+
 	csipodname      = "csi.storage.k8s.io/pod.name"
 	csipodnamespace = "csi.storage.k8s.io/pod.namespace"
 	csipoduid       = "csi.storage.k8s.io/pod.uid"
@@ -82,19 +91,27 @@ type Reconciler struct {
 	eventRecorder        record.EventRecorder
 	kubeClient           kubernetes.Interface
 	crdClient            versioned.Interface
-	// cache contains v1.Pod, v1alpha1.SecretProviderClassPodStatus (both filtered on *nodeID),
-	// v1.Secret (filtered on secrets-store.csi.k8s.io/managed=true)
+	// cache contains v1.Pod, secretsstorev1.SecretProviderClassPodStatus (both filtered on *nodeID),
+	// v1.Secret and v1.ConfigMap (filtered on secrets-store.csi.k8s.io/managed=true)
 	cache client.Reader
+	// reader reads from the api server the parametersFrom configmaps and secrets,
+	// as node publish does, so both paths see the same sources
+	reader client.Reader
 	// secretStore stores Secret (filtered on secrets-store.csi.k8s.io/used=true)
 	secretStore k8s.Store
+	// schedule contains the time the next rotation is due for the spc pod statuses in the queue
+	schedule schedule
+	// watches contains the providers with an open Watch stream
+	watches watches
 }
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // These permissions are required for secret rotation + nodePublishSecretRef
 // TODO (aramase) remove this as part of https://github.com/kubernetes-sigs/secrets-store-csi-driver/issues/585
 
 // NewReconciler returns a new reconciler for rotation
-func NewReconciler(client client.Reader, s *runtime.Scheme, providerVolumePath, nodeName string, rotationPollInterval time.Duration, providerClients *secretsstore.PluginClientBuilder, filteredWatchSecret bool) (*Reconciler, error) {
+func NewReconciler(client, reader client.Reader, s *runtime.Scheme, providerVolumePath, nodeName string, rotationPollInterval time.Duration, providerClients *secretsstore.PluginClientBuilder, filteredWatchSecret bool) (*Reconciler, error) {
 	config, err := buildConfig()
 	if err != nil {
 		return nil, err
@@ -121,6 +138,7 @@ func NewReconciler(client client.Reader, s *runtime.Scheme, providerVolumePath,
 		crdClient:            crdClient,
 		// cache store Pod,
 		cache:       client,
+		reader:      reader,
 		secretStore: secretStore,
 	}, nil
 }
@@ -130,9 +148,19 @@ func (r *Reconciler) Run(stopCh <-chan struct{}) {
 	defer r.queue.ShutDown()
 	klog.Infof("starting rotation reconciler with poll interval: %s", r.rotationPollInterval)
 
-	ticker := time.NewTicker(r.rotationPollInterval)
+	// the provider watch streams are closed when the reconciler stops
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// each spc pod status is rotated on its own timer, the ticker only schedules the rotation for
+	// the spc pod statuses that aren't scheduled yet
+	ticker := time.NewTicker(discoveryInterval(r.rotationPollInterval))
 	defer ticker.Stop()
 
+	// apply the changes to the parametersFrom configmaps and secrets without waiting for the poll interval
+	r.secretStore.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: r.enqueueForParametersSource,
+	})
 	if err := r.secretStore.Run(stopCh); err != nil {
 		klog.Fatalf("failed to run informers for rotation reconciler, err: %+v", err)
 	}
@@ -147,20 +175,104 @@ func (r *Reconciler) Run(stopCh <-chan struct{}) {
 		case <-stopCh:
 			return
 		case <-ticker.C:
-			// The spc pod status informer is configured to do a filtered list watch of spc pod statuses
-			// labeled for the same node as the driver. LIST will only return the filtered results.
-			spcPodStatusList := &v1alpha1.SecretProviderClassPodStatusList{}
-			err := r.cache.List(context.Background(), spcPodStatusList)
-			if err != nil {
-				klog.ErrorS(err, "failed to list secret provider class pod status for node", "controller", "rotation")
-				continue
-			}
-			for i := range spcPodStatusList.Items {
-				key, err := cache.MetaNamespaceKeyFunc(&spcPodStatusList.Items[i])
-				if err == nil {
-					r.queue.Add(key)
-				}
-			}
+			r.scheduleRotations()
+			// the objects updates pushed by the providers are rotated without waiting
+			// for the poll interval, which remains the fallback
+			r.ensureWatches(ctx)
+		}
+	}
+}
+
+// scheduleRotations schedules the next rotation for the spc pod statuses that aren't
+// scheduled yet and removes the spc pod statuses that no longer exist from the schedule
+func (r *Reconciler) scheduleRotations() {
+	// The spc pod status informer is configured to do a filtered list watch of spc pod statuses
+	// labeled for the same node as the driver. LIST will only return the filtered results.
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	err := r.cache.List(context.Background(), spcPodStatusList)
+	if err != nil {
+		klog.ErrorS(err, "failed to list secret provider class pod status for node", "controller", "rotation")
+		return
+	}
+	now := time.Now()
+	keys := make(map[string]struct{}, len(spcPodStatusList.Items))
+	for i := range spcPodStatusList.Items {
+		key, err := cache.MetaNamespaceKeyFunc(&spcPodStatusList.Items[i])
+		if err != nil {
+			continue
+		}
+		keys[key] = struct{}{}
+		if _, ok := r.schedule.get(key); ok {
+			continue
+		}
+		r.scheduleAfter(key, nextRotationDelay(&spcPodStatusList.Items[i], r.rotationPollInterval, now))
+	}
+	r.schedule.retain(keys)
+}
+
+// scheduleAfter adds the key to the queue after the delay and records when the rotation is due,
+// so the earlier timers for the key are skipped
+func (r *Reconciler) scheduleAfter(key string, delay time.Duration) {
+	r.schedule.set(key, time.Now().Add(delay))
+	r.queue.AddAfter(key, delay)
+}
+
+// scheduleNextRotation schedules the next rotation after the spc pod status was reconciled
+func (r *Reconciler) scheduleNextRotation(key string, spcps *secretsstorev1.SecretProviderClassPodStatus, err error) {
+	delay := nextRotationDelay(spcps, r.rotationPollInterval, time.Now())
+	// the provider error isn't retryable, wait for the poll interval
+	if err != nil {
+		delay = r.rotationPollInterval
+	}
+	// don't call the provider in a loop if it keeps returning refresh times in the past
+	if minDelay := minDuration(minRotationDelay, r.rotationPollInterval); delay < minDelay {
+		delay = minDelay
+	}
+	klog.V(5).InfoS("scheduled next rotation", "spcps", key, "delay", delay, "controller", "rotation")
+	r.scheduleAfter(key, delay)
+}
+
+// enqueueForParametersSource adds the spc pod statuses for the secret provider classes that reference the
+// updated configmap or secret in parametersFrom to the queue
+func (r *Reconciler) enqueueForParametersSource(oldObj, newObj interface{}) {
+	var kind string
+	switch newObj.(type) {
+	case *v1.ConfigMap:
+		kind = "ConfigMap"
+	case *v1.Secret:
+		kind = "Secret"
+	default:
+		return
+	}
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return
+	}
+	// skip the periodic resync of the informers
+	if oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+		return
+	}
+
+	ctx := context.Background()
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	if err := r.cache.List(ctx, spcPodStatusList, client.InNamespace(newMeta.GetNamespace())); err != nil {
+		klog.ErrorS(err, "failed to list secret provider class pod status", "namespace", newMeta.GetNamespace(), "controller", "rotation")
+		return
+	}
+	for i := range spcPodStatusList.Items {
+		spcps := &spcPodStatusList.Items[i]
+		spc, err := spcutil.GetSecretProviderClass(ctx, r.cache, spcps.Status.SecretProviderClassKind, spcps.Status.SecretProviderClassName, spcps.Namespace)
+		if err != nil || !spcutil.ReferencesParametersSource(spc, kind, newMeta.GetName()) {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(spcps)
+		if err == nil {
+			klog.V(3).InfoS("parametersFrom source updated", "kind", kind, "name", newMeta.GetName(), "spcps", key, "controller", "rotation")
+			r.scheduleAfter(key, 0)
 		}
 	}
 }
@@ -177,14 +289,20 @@ func (r *Reconciler) processNextItem() bool {
 	ctx := context.Background()
 	var err error
 
-	key, quit := r.queue.Get()
+	item, quit := r.queue.Get()
 	if quit {
 		return false
 	}
-	defer r.queue.Done(key)
+	defer r.queue.Done(item)
+	key := item.(string)
+
+	// skip the timers that were superseded by a later schedule for the key
+	if due, ok := r.schedule.get(key); ok && time.Now().Before(due) {
+		return true
+	}
 
-	spcps := &v1alpha1.SecretProviderClassPodStatus{}
-	keyParts := strings.Split(key.(string), "/")
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+	keyParts := strings.Split(key, "/")
 	if len(keyParts) < 2 {
 		err = fmt.Errorf("key is not in correct format. expected key format is namespace/name")
 	} else {
@@ -200,7 +318,7 @@ func (r *Reconciler) processNextItem() bool {
 
 	if err != nil {
 		// set the log level to 5 so we don't spam the logs with spc pod status not found
-		klog.V(5).ErrorS(err, "failed to get spc pod status", "spcps", key.(string), "controller", "rotation")
+		klog.V(5).ErrorS(err, "failed to get spc pod status", "spcps", key, "controller", "rotation")
 		rateLimited := false
 		// If the error is that spc pod status not found in cache, only retry
 		// with a limit instead of infinite retries.
@@ -225,11 +343,13 @@ func (r *Reconciler) processNextItem() bool {
 	}
 
 	klog.V(3).InfoS("reconciler completed", "spcps", klog.KObj(spcps), "controller", "rotation")
-	r.handleError(err, key, false)
+	if !r.handleError(err, key, false) {
+		r.scheduleNextRotation(key, spcps, err)
+	}
 	return true
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProviderClassPodStatus) (err error) {
+func (r *Reconciler) reconcile(ctx context.Context, spcps *secretsstorev1.SecretProviderClassPodStatus) (err error) {
 	begin := time.Now()
 	errorReason := internalerrors.FailedToRotate
 	// requiresUpdate is set to true when the new object versions differ from the current object versions
@@ -269,23 +389,55 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 		return nil
 	}
 
+	// secretSyncStarted is set to true once the mounted contents have been rotated
+	// and the secret objects in the spc are being synced as K8s secrets
+	var secretSyncStarted bool
+	original := spcps.DeepCopy()
+	// record the outcome of the rotation in the spc pod status conditions. The spc pod status
+	// is patched once at the end of the reconcile with the object versions, conditions and timestamps.
+	defer func() {
+		if secretSyncStarted {
+			spcpsutil.SetCondition(spcps, secretsstorev1.ConditionTypeSecretsSynced, internalerrors.FailedToSyncSecret, err)
+		} else {
+			spcpsutil.SetCondition(spcps, secretsstorev1.ConditionTypeRotated, errorReason, err)
+		}
+		now := metav1.Now()
+		spcps.Status.LastAttemptTime = &now
+		// the timestamps are only written with another change, as the spc pod status
+		// would be patched on every poll otherwise
+		if spcpsutil.OnlyTimestampsChanged(original, spcps) {
+			return
+		}
+
+		patchFn := func() (bool, error) {
+			if patchErr := r.patchSecretProviderClassPodStatus(ctx, original, spcps); patchErr != nil {
+				klog.ErrorS(patchErr, "failed to update spc pod status", "spcps", klog.KObj(spcps), "controller", "rotation")
+				return false, nil
+			}
+			return true, nil
+		}
+		if patchErr := wait.ExponentialBackoff(wait.Backoff{
+			Steps:    5,
+			Duration: 1 * time.Millisecond,
+			Factor:   1.0,
+			Jitter:   0.1,
+		}, patchFn); patchErr != nil {
+			r.generateEvent(pod, v1.EventTypeWarning, mountRotationFailedReason, fmt.Sprintf("failed to update spc pod status %s, err: %+v", spcps.Name, patchErr))
+			if err == nil {
+				err = fmt.Errorf("failed to update spc pod status, err: %+v", patchErr)
+			}
+		}
+	}()
+
 	// get the secret provider class which pod status is referencing from manager's cache
-	spc := &v1alpha1.SecretProviderClass{}
-	err = r.cache.Get(
-		ctx,
-		client.ObjectKey{
-			Namespace: spcps.Namespace,
-			Name:      spcps.Status.SecretProviderClassName,
-		},
-		spc,
-	)
+	spc, err := spcutil.GetSecretProviderClass(ctx, r.cache, spcps.Status.SecretProviderClassKind, spcps.Status.SecretProviderClassName, spcps.Namespace)
 	if err != nil {
 		errorReason = internalerrors.SecretProviderClassNotFound
 		return fmt.Errorf("failed to get secret provider class %s/%s, err: %+v", spcps.Namespace, spcps.Status.SecretProviderClassName, err)
 	}
 
 	// determine which pod volume this is associated with
-	podVol := k8sutil.SPCVolume(pod, spc.Name)
+	podVol := k8sutil.SPCVolumeForKind(pod, spcps.Status.SecretProviderClassKind, spc.Name)
 	if podVol == nil {
 		errorReason = internalerrors.PodVolumeNotFound
 		return fmt.Errorf("could not find secret provider class pod status volume for pod %s/%s", pod.Namespace, pod.Name)
@@ -301,9 +453,26 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 		return fmt.Errorf("could not find secret provider class pod status volume for pod %s/%s", pod.Namespace, pod.Name)
 	}
 
-	parameters := make(map[string]string)
-	if spc.Spec.Parameters != nil {
-		parameters = spc.Spec.Parameters
+	// expand the placeholders for the pod metadata in the spc parameters in the same way as the mount
+	parameters, err := k8sutil.ExpandParameters(spc.Spec.Parameters, pod)
+	if err != nil {
+		errorReason = internalerrors.FailedToExpandParameters
+		return fmt.Errorf("failed to expand parameters for pod %s/%s, err: %+v", pod.Namespace, pod.Name, err)
+	}
+	// add the keys of the configmaps and secrets referenced in parametersFrom, read from the
+	// api server like in node publish
+	if err = spcutil.AddParametersFrom(ctx, spcutil.NewParametersSource(r.reader), spc, parameters); err != nil {
+		errorReason = internalerrors.ParametersFromNotFound
+		return fmt.Errorf("failed to get parametersFrom for pod %s/%s, err: %+v", pod.Namespace, pod.Name, err)
+	}
+	// enforce the secrets store policies in the pod namespace before the provider is called
+	if err = policyutil.Enforce(ctx, r.cache, pod.Namespace, spc, parameters); err != nil {
+		var violation *policyutil.ViolationError
+		if errors.As(err, &violation) {
+			errorReason = internalerrors.SecretsStorePolicyViolation
+			r.generateEvent(pod, v1.EventTypeWarning, internalerrors.SecretsStorePolicyViolation, err.Error())
+		}
+		return fmt.Errorf("failed to enforce secrets store policies for pod %s/%s, err: %w", pod.Namespace, pod.Name, err)
 	}
 	// Set these parameters to mimic the exact same attributes we get as part of NodePublishVolumeRequest
 	parameters[csipodname] = pod.Name
@@ -315,11 +484,21 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 	if err != nil {
 		return fmt.Errorf("failed to marshal parameters, err: %+v", err)
 	}
-	permissionJSON, err := json.Marshal(permission)
+	filePermission := permission
+	if spc.Spec.DefaultMode != nil {
+		filePermission = os.FileMode(*spc.Spec.DefaultMode)
+	}
+	permissionJSON, err := json.Marshal(filePermission)
 	if err != nil {
 		return fmt.Errorf("failed to marshal permission, err: %+v", err)
 	}
 
+	// the rotated files are owned by the same group as the files written on mount
+	fsGroup, err := k8sutil.FSGroup(pod, podVol.CSI.VolumeAttributes)
+	if err != nil {
+		return fmt.Errorf("failed to get fsGroup for pod %s/%s, err: %+v", pod.Namespace, pod.Name, err)
+	}
+
 	// check if the volume pertaining to the current spc is using nodePublishSecretRef for
 	// accessing external secrets store
 	nodePublishSecretRef := podVol.CSI.NodePublishSecretRef
@@ -365,18 +544,23 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 	providerName = string(spc.Spec.Provider)
 	providerClient, err := r.providerClients.Get(ctx, providerName)
 	if err != nil {
-		errorReason = internalerrors.FailedToLookupProviderGRPCClient
-		r.generateEvent(pod, v1.EventTypeWarning, mountRotationFailedReason, fmt.Sprintf("failed to lookup provider client: %q", providerName))
-		return fmt.Errorf("failed to lookup provider client: %q", providerName)
+		errorReason = secretsstore.LookupErrorReason(err)
+		r.generateEvent(pod, v1.EventTypeWarning, mountRotationFailedReason, fmt.Sprintf("failed to lookup provider client: %q, err: %v", providerName, err))
+		err = fmt.Errorf("failed to lookup provider client: %q, err: %w", providerName, err)
+		spcpsutil.SetProviderHealthyCondition(spcps, errorReason, err)
+		return err
 	}
-	newObjectVersions, errorReason, err := secretsstore.MountContent(ctx, providerClient, string(paramsJSON), string(secretsJSON), spcps.Status.TargetPath, string(permissionJSON), oldObjectVersions)
+	var newObjects []secretsstorev1.SecretProviderClassObject
+	newObjects, errorReason, err = secretsstore.MountContent(ctx, providerClient, string(paramsJSON), string(secretsJSON), spcps.Status.TargetPath, string(permissionJSON), oldObjectVersions, r.providerClients.WriteOptions(providerName, secretsstore.NewWriteOptions(spc.Spec, fsGroup)))
+	spcpsutil.SetProviderHealthyCondition(spcps, errorReason, err)
 	if err != nil {
 		r.generateEvent(pod, v1.EventTypeWarning, mountRotationFailedReason, fmt.Sprintf("provider mount err: %+v", err))
-		return fmt.Errorf("failed to rotate objects for pod %s/%s, err: %+v", spcps.Namespace, spcps.Status.PodName, err)
+		return fmt.Errorf("failed to rotate objects for pod %s/%s, err: %w", spcps.Namespace, spcps.Status.PodName, err)
 	}
 
 	// compare the old object versions and new object versions to check if any of the objects
 	// have been updated by the provider
+	newObjectVersions := secretsstore.ObjectVersions(newObjects)
 	for k, v := range newObjectVersions {
 		version, ok := oldObjectVersions[strings.TrimSpace(k)]
 		if ok && strings.TrimSpace(version) == strings.TrimSpace(v) {
@@ -401,37 +585,25 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 		// generate an event for successful mount update
 		r.generateEvent(pod, v1.EventTypeNormal, mountRotationCompleteReason, fmt.Sprintf("successfully rotated mounted contents for spc %s/%s", spc.Namespace, spc.Name))
 		klog.InfoS("updating versions in spc pod status", "spcps", klog.KObj(spcps), "controller", "rotation")
-
-		var ov []v1alpha1.SecretProviderClassObject
-		for k, v := range newObjectVersions {
-			ov = append(ov, v1alpha1.SecretProviderClassObject{ID: strings.TrimSpace(k), Version: strings.TrimSpace(v)})
-		}
-		spcps.Status.Objects = ov
-
-		updateFn := func() (bool, error) {
-			err = r.updateSecretProviderClassPodStatus(ctx, spcps)
-			if err != nil {
-				klog.ErrorS(err, "failed to update latest versions in spc pod status", "spcps", klog.KObj(spcps), "controller", "rotation")
-				return false, nil
-			}
-			return true, nil
-		}
-
-		if err := wait.ExponentialBackoff(wait.Backoff{
-			Steps:    5,
-			Duration: 1 * time.Millisecond,
-			Factor:   1.0,
-			Jitter:   0.1,
-		}, updateFn); err != nil {
-			r.generateEvent(pod, v1.EventTypeWarning, mountRotationFailedReason, fmt.Sprintf("failed to update versions in spc pod status %s, err: %+v", spc.Name, err))
-			return fmt.Errorf("failed to update spc pod status, err: %+v", err)
-		}
 	}
+	// the objects are updated even if the versions are unchanged, as the provider
+	// can return new expiry and refresh times for the same versions
+	for i := range newObjects {
+		newObjects[i].ID = strings.TrimSpace(newObjects[i].ID)
+		newObjects[i].Version = strings.TrimSpace(newObjects[i].Version)
+	}
+	spcps.Status.Objects = newObjects
+
+	// the mounted contents are up to date with the provider
+	spcpsutil.SetCondition(spcps, secretsstorev1.ConditionTypeRotated, "", nil)
+	now := metav1.Now()
+	spcps.Status.LastSuccessfulRotationTime = &now
 
-	if len(spc.Spec.SecretObjects) == 0 {
-		klog.InfoS("spc doesn't contain secret objects", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "controller", "rotation")
+	if len(spc.Spec.SecretObjects) == 0 && len(spc.Spec.ConfigMapObjects) == 0 {
+		klog.InfoS("spc doesn't contain secret or configmap objects", "spc", klog.KObj(spc), "pod", klog.KObj(pod), "controller", "rotation")
 		return nil
 	}
+	secretSyncStarted = true
 	files, err := fileutil.GetMountedFiles(spcps.Status.TargetPath)
 	if err != nil {
 		r.generateEvent(pod, v1.EventTypeWarning, k8sSecretRotationFailedReason, fmt.Sprintf("failed to get mounted files, err: %+v", err))
@@ -449,7 +621,7 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 
 		secretType := secretutil.GetSecretType(strings.TrimSpace(secretObj.Type))
 		var datamap map[string][]byte
-		if datamap, err = secretutil.GetSecretData(secretObj.Data, secretType, files); err != nil {
+		if datamap, err = secretutil.GetSecretData(secretObj.Data, secretType, files, spc.Spec.Templates); err != nil {
 			r.generateEvent(pod, v1.EventTypeWarning, k8sSecretRotationFailedReason, fmt.Sprintf("failed to get data in spc %s/%s for secret %s, err: %+v", spc.Namespace, spc.Name, secretName, err))
 			klog.ErrorS(err, "failed to get data in spc for secret", "spc", klog.KObj(spc), "secret", klog.ObjectRef{Namespace: spc.Namespace, Name: secretName}, "controller", "rotation")
 			errs = append(errs, err)
@@ -479,11 +651,55 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 			r.generateEvent(pod, v1.EventTypeWarning, k8sSecretRotationFailedReason, fmt.Sprintf("failed to patch secret %s with new data, err: %+v", secretName, err))
 			// continue to ensure error in a single secret doesn't block the updates
 			// for all other secret objects defined in SPC
+			errs = append(errs, fmt.Errorf("failed to patch secret %s with new data, err: %+v", secretName, err))
 			continue
 		}
 		r.generateEvent(pod, v1.EventTypeNormal, k8sSecretRotationCompleteReason, fmt.Sprintf("successfully rotated K8s secret %s", secretName))
 	}
 
+	for _, configMapObj := range spc.Spec.ConfigMapObjects {
+		configMapName := strings.TrimSpace(configMapObj.ConfigMapName)
+
+		if err = secretutil.ValidateConfigMapObject(*configMapObj); err != nil {
+			r.generateEvent(pod, v1.EventTypeWarning, k8sConfigMapRotationFailedReason, fmt.Sprintf("failed validation for configmap object in spc %s/%s, err: %+v", spc.Namespace, spc.Name, err))
+			klog.ErrorS(err, "failed validation for configmap object in spc", "spc", klog.KObj(spc), "controller", "rotation")
+			errs = append(errs, err)
+			continue
+		}
+
+		data, binaryData, err := secretutil.GetConfigMapData(configMapObj.Data, files, spc.Spec.Templates)
+		if err != nil {
+			r.generateEvent(pod, v1.EventTypeWarning, k8sConfigMapRotationFailedReason, fmt.Sprintf("failed to get data in spc %s/%s for configmap %s, err: %+v", spc.Namespace, spc.Name, configMapName, err))
+			klog.ErrorS(err, "failed to get data in spc for configmap", "spc", klog.KObj(spc), "configmap", klog.ObjectRef{Namespace: spc.Namespace, Name: configMapName}, "controller", "rotation")
+			errs = append(errs, err)
+			continue
+		}
+
+		patchFn := func() (bool, error) {
+			// patch configmap data with the new contents
+			if err := r.patchConfigMap(ctx, configMapName, spcps.Namespace, data, binaryData); err != nil {
+				if apierrors.IsForbidden(err) {
+					klog.Warning(controllers.SyncSecretForbiddenWarning)
+				}
+				klog.ErrorS(err, "failed to patch configmap data", "configmap", klog.ObjectRef{Namespace: spc.Namespace, Name: configMapName}, "spc", klog.KObj(spc), "controller", "rotation")
+				return false, nil
+			}
+			return true, nil
+		}
+
+		if err := wait.ExponentialBackoff(wait.Backoff{
+			Steps:    5,
+			Duration: 1 * time.Millisecond,
+			Factor:   1.0,
+			Jitter:   0.1,
+		}, patchFn); err != nil {
+			r.generateEvent(pod, v1.EventTypeWarning, k8sConfigMapRotationFailedReason, fmt.Sprintf("failed to patch configmap %s with new data, err: %+v", configMapName, err))
+			errs = append(errs, fmt.Errorf("failed to patch configmap %s with new data, err: %+v", configMapName, err))
+			continue
+		}
+		r.generateEvent(pod, v1.EventTypeNormal, k8sConfigMapRotationCompleteReason, fmt.Sprintf("successfully rotated K8s configmap %s", configMapName))
+	}
+
 	// for errors with individual secret objects in spc, we continue to the next secret object
 	// to prevent error with one secret from affecting rotation of all other k8s secret
 	// this consolidation of errors within the loop determines if the spc pod status still needs
@@ -495,10 +711,14 @@ func (r *Reconciler) reconcile(ctx context.Context, spcps *v1alpha1.SecretProvid
 	return nil
 }
 
-// updateSecretProviderClassPodStatus updates secret provider class pod status
-func (r *Reconciler) updateSecretProviderClassPodStatus(ctx context.Context, spcPodStatus *v1alpha1.SecretProviderClassPodStatus) error {
-	// update the secret provider class pod status
-	_, err := r.crdClient.SecretsstoreV1alpha1().SecretProviderClassPodStatuses(spcPodStatus.Namespace).Update(ctx, spcPodStatus, metav1.UpdateOptions{})
+// patchSecretProviderClassPodStatus patches secret provider class pod status with the
+// changes from the original object
+func (r *Reconciler) patchSecretProviderClassPodStatus(ctx context.Context, original, spcPodStatus *secretsstorev1.SecretProviderClassPodStatus) error {
+	patch, err := client.MergeFrom(original).Data(spcPodStatus)
+	if err != nil {
+		return fmt.Errorf("failed to create patch, err: %+v", err)
+	}
+	_, err = r.crdClient.SecretsstoreV1().SecretProviderClassPodStatuses(spcPodStatus.Namespace).Patch(ctx, spcPodStatus.Name, types.MergePatchType, patch, metav1.PatchOptions{})
 	return err
 }
 
@@ -558,26 +778,66 @@ func (r *Reconciler) patchSecret(ctx context.Context, name, namespace string, da
 	return err
 }
 
-// handleError requeue the key after 10s if there is an error while processing
-func (r *Reconciler) handleError(err error, key interface{}, rateLimited bool) {
+// patchConfigMap patches configmap with the new data and binaryData and returns error if any.
+// As for secrets, a configmap that doesn't exist is created by the secretproviderclasspodstatus controller.
+func (r *Reconciler) patchConfigMap(ctx context.Context, name, namespace string, data map[string]string, binaryData map[string][]byte) error {
+	configMap := &v1.ConfigMap{}
+	if err := r.cache.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(configMap.Data, data) && equality.Semantic.DeepEqual(configMap.BinaryData, binaryData) {
+		return nil
+	}
+
+	newConfigMap := configMap.DeepCopy()
+	newConfigMap.Data = data
+	newConfigMap.BinaryData = binaryData
+	patch, err := client.MergeFrom(configMap).Data(newConfigMap)
+	if err != nil {
+		return fmt.Errorf("failed to create patch, err: %+v", err)
+	}
+	_, err = r.kubeClient.CoreV1().ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// handleError requeue the key after 10s if there is an error while processing. Provider errors
+// that aren't retryable aren't requeued, as the objects are rotated again in the next poll, and
+// retryable provider errors are requeued after the retry-after duration returned by the provider.
+// It returns true if the key was requeued.
+func (r *Reconciler) handleError(err error, key string, rateLimited bool) bool {
 	if err == nil {
 		r.queue.Forget(key)
-		return
+		return false
 	}
 	if !rateLimited {
-		r.queue.AddAfter(key, 10*time.Second)
-		return
+		var providerErr *secretsstore.ProviderError
+		if errors.As(err, &providerErr) {
+			if !providerErr.Retryable {
+				klog.V(5).InfoS("provider error isn't retryable, waiting for the next rotation poll", "spcps", key, "code", providerErr.Code)
+				r.queue.Forget(key)
+				return false
+			}
+			if providerErr.RetryAfter > 0 {
+				r.scheduleAfter(key, providerErr.RetryAfter)
+				return true
+			}
+		}
+		r.scheduleAfter(key, 10*time.Second)
+		return true
 	}
 	// if the requeue for key is rate limited and the number of times the key
 	// has been added back to queue exceeds the default allowed limit, then do nothing.
 	// this is done to prevent infinitely adding the key the queue in scenarios where
 	// the key was added to the queue because of an error but has since been deleted.
 	if r.queue.NumRequeues(key) < maxNumOfRequeues {
+		r.schedule.set(key, time.Now())
 		r.queue.AddRateLimited(key)
-		return
+		return true
 	}
 	klog.InfoS("retry budget exceeded, dropping from queue", "spcps", key)
 	r.queue.Forget(key)
+	r.schedule.remove(key)
+	return false
 }
 
 // generateEvent generates an event
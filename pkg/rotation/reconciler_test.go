@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/workqueue"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -44,13 +45,15 @@ import (
 	controllerfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	"sigs.k8s.io/secrets-store-csi-driver/controllers"
 	secretsStoreFakeClient "sigs.k8s.io/secrets-store-csi-driver/pkg/client/clientset/versioned/fake"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/k8s"
 	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
 	providerfake "sigs.k8s.io/secrets-store-csi-driver/provider/fake"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
 )
 
 var (
@@ -59,7 +62,7 @@ var (
 
 func setupScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
-	if err := v1alpha1.AddToScheme(scheme); err != nil {
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
@@ -84,6 +87,7 @@ func newTestReconciler(client client.Reader, s *runtime.Scheme, kubeClient kuber
 		kubeClient:           kubeClient,
 		crdClient:            crdClient,
 		cache:                client,
+		reader:               client,
 		secretStore:          secretStore,
 	}, nil
 }
@@ -94,8 +98,8 @@ func TestReconcileError(t *testing.T) {
 	tests := []struct {
 		name                                  string
 		rotationPollInterval                  time.Duration
-		secretProviderClassPodStatusToProcess *v1alpha1.SecretProviderClassPodStatus
-		secretProviderClassToAdd              *v1alpha1.SecretProviderClass
+		secretProviderClassPodStatusToProcess *secretsstorev1.SecretProviderClassPodStatus
+		secretProviderClassToAdd              *secretsstorev1.SecretProviderClass
 		podToAdd                              *v1.Pod
 		socketPath                            string
 		secretToAdd                           *v1.Secret
@@ -106,18 +110,18 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "secret provider class not found",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{},
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{},
 			podToAdd:                 &v1.Pod{},
 			socketPath:               getTempTestDir(t),
 			secretToAdd:              &v1.Secret{},
@@ -126,26 +130,26 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "failed to get pod",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "spc1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.SecretProviderClassSpec{
-					SecretObjects: []*v1alpha1.SecretObject{
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					SecretObjects: []*secretsstorev1.SecretObject{
 						{
-							Data: []*v1alpha1.SecretObjectData{
+							Data: []*secretsstorev1.SecretObjectData{
 								{
 									ObjectName: "object1",
 									Key:        "foo",
@@ -163,27 +167,27 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "failed to get NodePublishSecretRef secret",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 					TargetPath:              getTestTargetPath(t, "foo", "csi-volume"),
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "spc1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.SecretProviderClassSpec{
-					SecretObjects: []*v1alpha1.SecretObject{
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					SecretObjects: []*secretsstorev1.SecretObject{
 						{
-							Data: []*v1alpha1.SecretObjectData{
+							Data: []*secretsstorev1.SecretObjectData{
 								{
 									ObjectName: "object1",
 									Key:        "foo",
@@ -225,17 +229,17 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "failed to validate targetpath UID",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 					TargetPath:              getTestTargetPath(t, "bad-uid", "csi-volume"),
-					Objects: []v1alpha1.SecretProviderClassObject{
+					Objects: []secretsstorev1.SecretProviderClassObject{
 						{
 							ID:      "secret/object1",
 							Version: "v1",
@@ -243,15 +247,15 @@ func TestReconcileError(t *testing.T) {
 					},
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "spc1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.SecretProviderClassSpec{
-					SecretObjects: []*v1alpha1.SecretObject{
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					SecretObjects: []*secretsstorev1.SecretObject{
 						{
-							Data: []*v1alpha1.SecretObjectData{
+							Data: []*secretsstorev1.SecretObjectData{
 								{
 									ObjectName: "object1",
 									Key:        "foo",
@@ -298,17 +302,17 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "failed to validate targetpath volume name",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 					TargetPath:              getTestTargetPath(t, "foo", "bad-volume-name"),
-					Objects: []v1alpha1.SecretProviderClassObject{
+					Objects: []secretsstorev1.SecretProviderClassObject{
 						{
 							ID:      "secret/object1",
 							Version: "v1",
@@ -316,15 +320,15 @@ func TestReconcileError(t *testing.T) {
 					},
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "spc1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.SecretProviderClassSpec{
-					SecretObjects: []*v1alpha1.SecretObject{
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					SecretObjects: []*secretsstorev1.SecretObject{
 						{
-							Data: []*v1alpha1.SecretObjectData{
+							Data: []*secretsstorev1.SecretObjectData{
 								{
 									ObjectName: "object1",
 									Key:        "foo",
@@ -371,27 +375,27 @@ func TestReconcileError(t *testing.T) {
 		{
 			name:                 "failed to lookup provider client",
 			rotationPollInterval: 60 * time.Second,
-			secretProviderClassPodStatusToProcess: &v1alpha1.SecretProviderClassPodStatus{
+			secretProviderClassPodStatusToProcess: &secretsstorev1.SecretProviderClassPodStatus{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "pod1-default-spc1",
 					Namespace: "default",
-					Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+					Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 				},
-				Status: v1alpha1.SecretProviderClassPodStatusStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 					SecretProviderClassName: "spc1",
 					PodName:                 "pod1",
 					TargetPath:              getTestTargetPath(t, "foo", "csi-volume"),
 				},
 			},
-			secretProviderClassToAdd: &v1alpha1.SecretProviderClass{
+			secretProviderClassToAdd: &secretsstorev1.SecretProviderClass{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "spc1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.SecretProviderClassSpec{
-					SecretObjects: []*v1alpha1.SecretObject{
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					SecretObjects: []*secretsstorev1.SecretObject{
 						{
-							Data: []*v1alpha1.SecretObjectData{
+							Data: []*secretsstorev1.SecretObjectData{
 								{
 									ObjectName: "object1",
 									Key:        "foo",
@@ -516,17 +520,17 @@ func TestReconcileNoError(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		secretProviderClassPodStatusToProcess := &v1alpha1.SecretProviderClassPodStatus{
+		secretProviderClassPodStatusToProcess := &secretsstorev1.SecretProviderClassPodStatus{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "pod1-default-spc1",
 				Namespace: "default",
-				Labels:    map[string]string{v1alpha1.InternalNodeLabel: "nodeName"},
+				Labels:    map[string]string{secretsstorev1.InternalNodeLabel: "nodeName"},
 			},
-			Status: v1alpha1.SecretProviderClassPodStatusStatus{
+			Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 				SecretProviderClassName: "spc1",
 				PodName:                 "pod1",
 				TargetPath:              getTestTargetPath(t, "foo", "csi-volume"),
-				Objects: []v1alpha1.SecretProviderClassObject{
+				Objects: []secretsstorev1.SecretProviderClassObject{
 					{
 						ID:      "secret/object1",
 						Version: "v1",
@@ -534,15 +538,15 @@ func TestReconcileNoError(t *testing.T) {
 				},
 			},
 		}
-		secretProviderClassToAdd := &v1alpha1.SecretProviderClass{
+		secretProviderClassToAdd := &secretsstorev1.SecretProviderClass{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "spc1",
 				Namespace: "default",
 			},
-			Spec: v1alpha1.SecretProviderClassSpec{
-				SecretObjects: []*v1alpha1.SecretObject{
+			Spec: secretsstorev1.SecretProviderClassSpec{
+				SecretObjects: []*secretsstorev1.SecretObject{
 					{
-						Data: []*v1alpha1.SecretObjectData{
+						Data: []*secretsstorev1.SecretObjectData{
 							{
 								ObjectName: "object1",
 								Key:        "foo",
@@ -627,10 +631,16 @@ func TestReconcileNoError(t *testing.T) {
 		g.Expect(err).NotTo(HaveOccurred())
 
 		// validate the secret provider class pod status versions have been updated
-		updatedSPCPodStatus := &v1alpha1.SecretProviderClassPodStatus{}
-		updatedSPCPodStatus, err = crdClient.SecretsstoreV1alpha1().SecretProviderClassPodStatuses(v1.NamespaceDefault).Get(context.TODO(), "pod1-default-spc1", metav1.GetOptions{})
+		updatedSPCPodStatus := &secretsstorev1.SecretProviderClassPodStatus{}
+		updatedSPCPodStatus, err = crdClient.SecretsstoreV1().SecretProviderClassPodStatuses(v1.NamespaceDefault).Get(context.TODO(), "pod1-default-spc1", metav1.GetOptions{})
 		g.Expect(err).NotTo(HaveOccurred())
-		g.Expect(updatedSPCPodStatus.Status.Objects).To(Equal([]v1alpha1.SecretProviderClassObject{{ID: "secret/object1", Version: "v2"}}))
+		g.Expect(updatedSPCPodStatus.Status.Objects).To(Equal([]secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v2"}}))
+		// validate the conditions and timestamps have been updated
+		for _, conditionType := range []string{secretsstorev1.ConditionTypeRotated, secretsstorev1.ConditionTypeSecretsSynced, secretsstorev1.ConditionTypeProviderHealthy} {
+			g.Expect(meta.IsStatusConditionTrue(updatedSPCPodStatus.Status.Conditions, conditionType)).To(BeTrue())
+		}
+		g.Expect(updatedSPCPodStatus.Status.LastSuccessfulRotationTime).NotTo(BeNil())
+		g.Expect(updatedSPCPodStatus.Status.LastAttemptTime).NotTo(BeNil())
 
 		// validate the secret data has been updated to the latest value
 		updatedSecret := &v1.Secret{}
@@ -645,6 +655,15 @@ func TestReconcileNoError(t *testing.T) {
 			<-fakeRecorder.Events
 		}
 
+		// the spc pod status isn't patched again if only the timestamps changed
+		patches := countPatches(crdClient.Actions())
+		err = testReconciler.reconcile(context.TODO(), updatedSPCPodStatus)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(countPatches(crdClient.Actions())).To(Equal(patches))
+		for len(fakeRecorder.Events) > 0 {
+			<-fakeRecorder.Events
+		}
+
 		// test with pod being terminated
 		podToAdd.DeletionTimestamp = &metav1.Time{Time: time.Now()}
 		kubeClient = fake.NewSimpleClientset(podToAdd, test.nodePublishSecretRefSecretToAdd)
@@ -783,6 +802,91 @@ func TestPatchSecret(t *testing.T) {
 	}
 }
 
+func TestPatchConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "configmap1",
+			Namespace:       "default",
+			ResourceVersion: "16172",
+			Labels: map[string]string{
+				controllers.SecretManagedLabel: "true",
+			},
+		},
+		Data: map[string]string{"ca.crt": "old", "removed": "value"},
+	}
+	kubeClient := fake.NewSimpleClientset(configMap)
+	client := controllerfake.NewFakeClientWithScheme(scheme, configMap)
+	testReconciler, err := newTestReconciler(client, scheme, kubeClient, secretsStoreFakeClient.NewSimpleClientset(), 60*time.Second, "", false)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = testReconciler.patchConfigMap(context.TODO(), "notfound", v1.NamespaceDefault, nil, nil)
+	g.Expect(err).To(HaveOccurred())
+
+	data := map[string]string{"ca.crt": "new"}
+	binaryData := map[string][]byte{"key.der": {0xb5, 0xeb, 0x2d}}
+	err = testReconciler.patchConfigMap(context.TODO(), "configmap1", v1.NamespaceDefault, data, binaryData)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	updated, err := kubeClient.CoreV1().ConfigMaps(v1.NamespaceDefault).Get(context.TODO(), "configmap1", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Data).To(Equal(data))
+	g.Expect(updated.BinaryData).To(Equal(binaryData))
+}
+
+func TestEnqueueForParametersSource(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newSPCPodStatus := func(name, spcName string) *secretsstorev1.SecretProviderClassPodStatus {
+		return &secretsstorev1.SecretProviderClassPodStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     secretsstorev1.SecretProviderClassPodStatusStatus{SecretProviderClassName: spcName, PodName: "pod1"},
+		}
+	}
+	initObjects := []runtime.Object{
+		&secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+			Spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				ParametersFrom: []*secretsstorev1.ParametersFromSource{
+					{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+				},
+			},
+		},
+		&secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "spc2", Namespace: "default"},
+			Spec:       secretsstorev1.SecretProviderClassSpec{Provider: "provider1"},
+		},
+		newSPCPodStatus("pod1-default-spc1", "spc1"),
+		newSPCPodStatus("pod1-default-spc2", "spc2"),
+	}
+	client := controllerfake.NewFakeClientWithScheme(scheme, initObjects...)
+	testReconciler, err := newTestReconciler(client, scheme, nil, nil, 60*time.Second, "", false)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	oldConfigMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "vault-config", Namespace: "default", ResourceVersion: "1"}}
+	// the resync of the informer isn't a change
+	testReconciler.enqueueForParametersSource(oldConfigMap, oldConfigMap.DeepCopy())
+	g.Expect(testReconciler.queue.Len()).To(Equal(0))
+	// a secret with the same name isn't a parametersFrom source
+	testReconciler.enqueueForParametersSource(&v1.Secret{ObjectMeta: oldConfigMap.ObjectMeta}, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "vault-config", Namespace: "default", ResourceVersion: "2"}})
+	g.Expect(testReconciler.queue.Len()).To(Equal(0))
+
+	newConfigMap := oldConfigMap.DeepCopy()
+	newConfigMap.ResourceVersion = "2"
+	testReconciler.enqueueForParametersSource(oldConfigMap, newConfigMap)
+	g.Expect(testReconciler.queue.Len()).To(Equal(1))
+	key, _ := testReconciler.queue.Get()
+	g.Expect(key).To(Equal("default/pod1-default-spc1"))
+}
+
 func TestHandleError(t *testing.T) {
 	g := NewWithT(t)
 
@@ -809,6 +913,22 @@ func TestHandleError(t *testing.T) {
 	g.Expect(testReconciler.queue.Len()).To(Equal(1))
 }
 
+func TestHandleErrorProviderError(t *testing.T) {
+	g := NewWithT(t)
+
+	testReconciler, err := newTestReconciler(nil, nil, nil, nil, 60*time.Second, "", false)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// provider errors that aren't retryable wait for the next rotation poll
+	testReconciler.handleError(fmt.Errorf("failed to rotate, err: %w", &secretsstore.ProviderError{Code: "SecretNotFound"}), "key1", false)
+	// retryable provider errors are requeued after the retry-after duration
+	testReconciler.handleError(fmt.Errorf("failed to rotate, err: %w", &secretsstore.ProviderError{Code: "Throttled", Retryable: true, RetryAfter: time.Second}), "key2", false)
+	time.Sleep(2 * time.Second)
+	g.Expect(testReconciler.queue.Len()).To(Equal(1))
+	key, _ := testReconciler.queue.Get()
+	g.Expect(key).To(Equal("key2"))
+}
+
 func getTempTestDir(t *testing.T) string {
 	tmpDir, err := os.MkdirTemp("", "ut")
 	if err != nil {
@@ -825,3 +945,194 @@ func getTestTargetPath(t *testing.T, uid, vol string) string {
 	}
 	return path
 }
+
+func TestNextRotationDelay(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	lastRotation := metav1.NewTime(now.Add(-10 * time.Second))
+	refreshAfter := metav1.NewTime(now.Add(20 * time.Second))
+	pastRefresh := metav1.NewTime(now.Add(-time.Second))
+
+	tests := []struct {
+		name     string
+		spcps    *secretsstorev1.SecretProviderClassPodStatus
+		maxDelay time.Duration
+	}{
+		{
+			name: "poll interval after the creation",
+			spcps: &secretsstorev1.SecretProviderClassPodStatus{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)},
+			},
+			maxDelay: time.Minute,
+		},
+		{
+			name: "poll interval after the last rotation",
+			spcps: &secretsstorev1.SecretProviderClassPodStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{LastSuccessfulRotationTime: &lastRotation},
+			},
+			maxDelay: 50 * time.Second,
+		},
+		{
+			name: "earliest refresh time of the objects",
+			spcps: &secretsstorev1.SecretProviderClassPodStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+					LastSuccessfulRotationTime: &lastRotation,
+					Objects: []secretsstorev1.SecretProviderClassObject{
+						{ID: "secret/object1", Version: "v1"},
+						{ID: "secret/object2", Version: "v1", RefreshAfter: &refreshAfter},
+					},
+				},
+			},
+			maxDelay: 20 * time.Second,
+		},
+		{
+			name: "refresh time in the past",
+			spcps: &secretsstorev1.SecretProviderClassPodStatus{
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+					LastSuccessfulRotationTime: &lastRotation,
+					Objects:                    []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v1", RefreshAfter: &pastRefresh}},
+				},
+			},
+			maxDelay: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delay := nextRotationDelay(test.spcps, time.Minute, now)
+			g.Expect(delay).To(BeNumerically("<=", test.maxDelay))
+			// at most 10% jitter is subtracted
+			g.Expect(delay).To(BeNumerically(">=", test.maxDelay-test.maxDelay/10))
+		})
+	}
+}
+
+func TestScheduleRotations(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	refreshAfter := metav1.NewTime(time.Now().Add(-time.Second))
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1-default-spc1", Namespace: "default"},
+		Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+			Objects: []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v1", RefreshAfter: &refreshAfter}},
+		},
+	}
+	client := controllerfake.NewFakeClientWithScheme(scheme, spcps)
+	testReconciler, err := newTestReconciler(client, scheme, nil, nil, 60*time.Second, "", false)
+	g.Expect(err).NotTo(HaveOccurred())
+	testReconciler.schedule.set("default/deleted", time.Now().Add(time.Minute))
+
+	// the spc pod status with a refresh time in the past is due now and the
+	// deleted spc pod status is removed from the schedule
+	testReconciler.scheduleRotations()
+	g.Eventually(testReconciler.queue.Len).Should(Equal(1))
+	_, ok := testReconciler.schedule.get("default/deleted")
+	g.Expect(ok).To(BeFalse())
+
+	// the spc pod status isn't scheduled again while its rotation is pending
+	testReconciler.schedule.set("default/pod1-default-spc1", time.Now().Add(time.Minute))
+	testReconciler.scheduleRotations()
+	due, ok := testReconciler.schedule.get("default/pod1-default-spc1")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(due).To(BeTemporally(">", time.Now().Add(50*time.Second)))
+
+	// the superseded timer is skipped without reconciling the spc pod status
+	g.Expect(testReconciler.processNextItem()).To(BeTrue())
+	g.Expect(testReconciler.queue.Len()).To(Equal(0))
+}
+
+func TestWatchProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newSPCPodStatus := func(name, podName, spcName, objectID string) *secretsstorev1.SecretProviderClassPodStatus {
+		return &secretsstorev1.SecretProviderClassPodStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+				SecretProviderClassName: spcName,
+				PodName:                 podName,
+				Objects:                 []secretsstorev1.SecretProviderClassObject{{ID: objectID, Version: "v1"}},
+			},
+		}
+	}
+	initObjects := []runtime.Object{
+		&secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+			Spec:       secretsstorev1.SecretProviderClassSpec{Provider: "provider1"},
+		},
+		&secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "spc2", Namespace: "default"},
+			Spec:       secretsstorev1.SecretProviderClassSpec{Provider: "provider2"},
+		},
+		newSPCPodStatus("pod1-default-spc1", "pod1", "spc1", "secret/object1"),
+		newSPCPodStatus("pod2-default-spc1", "pod2", "spc1", "secret/object2"),
+		// the spc pod status of another provider isn't rotated for the same object id
+		newSPCPodStatus("pod1-default-spc2", "pod1", "spc2", "secret/object1"),
+	}
+	client := controllerfake.NewFakeClientWithScheme(scheme, initObjects...)
+
+	socketPath := getTempTestDir(t)
+	defer os.RemoveAll(socketPath)
+	testReconciler, err := newTestReconciler(client, scheme, nil, nil, 60*time.Second, socketPath, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer testReconciler.providerClients.Cleanup()
+
+	server, err := providerfake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider1.sock"))
+	g.Expect(err).NotTo(HaveOccurred())
+	server.SetCapabilities([]string{v1alpha1.CapabilityWatch})
+	g.Expect(server.Start()).To(Succeed())
+	defer server.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the provider isn't watched until the driver is connected to it
+	testReconciler.ensureWatches(ctx)
+	g.Expect(testReconciler.watches.add("provider1")).To(BeTrue())
+	testReconciler.watches.remove("provider1")
+
+	_, err = testReconciler.providerClients.Get(ctx, "provider1")
+	g.Expect(err).NotTo(HaveOccurred())
+	testReconciler.ensureWatches(ctx)
+	g.Expect(testReconciler.watches.add("provider1")).To(BeFalse())
+
+	server.SendWatchEvent(&v1alpha1.WatchResponse{ObjectIds: []string{"secret/object1"}, Namespace: "default"})
+	g.Eventually(testReconciler.queue.Len, 5*time.Second).Should(Equal(1))
+	key, _ := testReconciler.queue.Get()
+	g.Expect(key).To(Equal("default/pod1-default-spc1"))
+	testReconciler.queue.Done(key)
+
+	// the notification for a pod rotates all its objects of the provider
+	server.SendWatchEvent(&v1alpha1.WatchResponse{Namespace: "default", PodName: "pod2"})
+	g.Eventually(testReconciler.queue.Len, 5*time.Second).Should(Equal(1))
+	key, _ = testReconciler.queue.Get()
+	g.Expect(key).To(Equal("default/pod2-default-spc1"))
+	testReconciler.queue.Done(key)
+
+	// the stream is closed when the reconciler stops
+	cancel()
+	g.Eventually(func() bool {
+		if !testReconciler.watches.add("provider1") {
+			return false
+		}
+		testReconciler.watches.remove("provider1")
+		return true
+	}, 5*time.Second).Should(BeTrue())
+}
+
+// countPatches returns the number of patch actions
+func countPatches(actions []clienttesting.Action) int {
+	var patches int
+	for _, action := range actions {
+		if action.GetVerb() == "patch" {
+			patches++
+		}
+	}
+	return patches
+}
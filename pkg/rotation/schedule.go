@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+)
+
+const (
+	// maxDiscoveryInterval is the maximum interval to list the spc pod statuses
+	// and schedule the rotation for the new ones
+	maxDiscoveryInterval = 30 * time.Second
+	// rotationJitterFactor is the maximum fraction of the delay until the next rotation
+	// that's subtracted from it, so the spc pod statuses mounted at the same time don't
+	// all call the provider at once
+	rotationJitterFactor = 0.1
+	// minRotationDelay is the minimum delay until the next rotation after the spc pod status
+	// was reconciled
+	minRotationDelay = 10 * time.Second
+)
+
+// schedule tracks when the next rotation is due for each spc pod status key.
+// The zero value is ready to use.
+type schedule struct {
+	mu  sync.Mutex
+	due map[string]time.Time
+}
+
+// set records the time the next rotation is due for the key
+func (s *schedule) set(key string, due time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.due == nil {
+		s.due = make(map[string]time.Time)
+	}
+	s.due[key] = due
+}
+
+// get returns the time the next rotation is due for the key
+func (s *schedule) get(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due, ok := s.due[key]
+	return due, ok
+}
+
+// remove removes the key, so the next time the key is processed isn't skipped
+func (s *schedule) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.due, key)
+}
+
+// retain removes the keys that aren't in keys
+func (s *schedule) retain(keys map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.due {
+		if _, ok := keys[key]; !ok {
+			delete(s.due, key)
+		}
+	}
+}
+
+// nextRotationDelay returns the delay until the next rotation of the spc pod status. The
+// next rotation is at most the poll interval after the last successful rotation (or the
+// creation of the spc pod status), and earlier if the provider returned an earlier refresh
+// time for any of the mounted objects. Up to 10% of the delay is subtracted as jitter.
+func nextRotationDelay(spcps *secretsstorev1.SecretProviderClassPodStatus, pollInterval time.Duration, now time.Time) time.Duration {
+	last := spcps.CreationTimestamp.Time
+	if spcps.Status.LastSuccessfulRotationTime != nil {
+		last = spcps.Status.LastSuccessfulRotationTime.Time
+	}
+	next := last.Add(pollInterval)
+	if refresh := secretsstore.NextRefreshTime(spcps.Status.Objects); refresh != nil && refresh.Before(next) {
+		next = *refresh
+	}
+	delay := next.Sub(now)
+	if delay <= 0 {
+		return 0
+	}
+	return delay - time.Duration(rand.Float64()*rotationJitterFactor*float64(delay)) // #nosec G404 jitter doesn't need a secure random
+}
+
+// discoveryInterval returns the interval to list the spc pod statuses and schedule the
+// rotation for the ones that aren't scheduled yet
+func discoveryInterval(pollInterval time.Duration) time.Duration {
+	if pollInterval < maxDiscoveryInterval {
+		return pollInterval
+	}
+	return maxDiscoveryInterval
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
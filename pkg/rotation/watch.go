@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+// watches tracks the providers with an open Watch stream. The zero value is ready to use.
+type watches struct {
+	mu        sync.Mutex
+	providers map[string]struct{}
+}
+
+// add returns false if the provider already has an open Watch stream
+func (w *watches) add(provider string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.providers[provider]; ok {
+		return false
+	}
+	if w.providers == nil {
+		w.providers = make(map[string]struct{})
+	}
+	w.providers[provider] = struct{}{}
+	return true
+}
+
+func (w *watches) remove(provider string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.providers, provider)
+}
+
+// ensureWatches opens a Watch stream for the connected providers with the Watch capability
+// that don't have one yet. The streams that end are opened again on the next call.
+func (r *Reconciler) ensureWatches(ctx context.Context) {
+	for _, provider := range r.providerClients.Providers() {
+		if !r.providerClients.HasCapability(provider, v1alpha1.CapabilityWatch) || !r.watches.add(provider) {
+			continue
+		}
+		go func(provider string) {
+			defer r.watches.remove(provider)
+			if err := r.watch(ctx, provider); err != nil && ctx.Err() == nil {
+				klog.ErrorS(err, "provider watch stream failed", "provider", provider, "controller", "rotation")
+			}
+		}(provider)
+	}
+}
+
+// watch receives the notifications from the provider's Watch stream until the stream ends
+// and rotates the affected spc pod statuses
+func (r *Reconciler) watch(ctx context.Context, provider string) error {
+	providerClient, err := r.providerClients.Get(ctx, provider)
+	if err != nil {
+		return err
+	}
+	stream, err := providerClient.Watch(ctx, &v1alpha1.WatchRequest{Version: "v1alpha1"})
+	if err != nil {
+		return err
+	}
+	klog.InfoS("watching provider for object updates", "provider", provider, "controller", "rotation")
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.enqueueForWatchEvent(ctx, provider, resp)
+	}
+}
+
+// enqueueForWatchEvent rotates the spc pod statuses of the provider selected by the watch notification
+func (r *Reconciler) enqueueForWatchEvent(ctx context.Context, provider string, event *v1alpha1.WatchResponse) {
+	var opts []client.ListOption
+	if event.GetNamespace() != "" {
+		opts = append(opts, client.InNamespace(event.GetNamespace()))
+	}
+	spcPodStatusList := &secretsstorev1.SecretProviderClassPodStatusList{}
+	if err := r.cache.List(ctx, spcPodStatusList, opts...); err != nil {
+		klog.ErrorS(err, "failed to list secret provider class pod status", "provider", provider, "controller", "rotation")
+		return
+	}
+	for i := range spcPodStatusList.Items {
+		spcps := &spcPodStatusList.Items[i]
+		if !matchesWatchEvent(spcps, event) {
+			continue
+		}
+		spc, err := spcutil.GetSecretProviderClass(ctx, r.cache, spcps.Status.SecretProviderClassKind, spcps.Status.SecretProviderClassName, spcps.Namespace)
+		if err != nil || string(spc.Spec.Provider) != provider {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(spcps)
+		if err == nil {
+			klog.V(3).InfoS("provider notified object updates", "provider", provider, "objects", event.GetObjectIds(), "spcps", key, "controller", "rotation")
+			r.scheduleAfter(key, 0)
+		}
+	}
+}
+
+// matchesWatchEvent returns true if the spc pod status is selected by the pod, secret provider
+// class and object ids set in the watch notification
+func matchesWatchEvent(spcps *secretsstorev1.SecretProviderClassPodStatus, event *v1alpha1.WatchResponse) bool {
+	if event.GetPodName() != "" && event.GetPodName() != spcps.Status.PodName {
+		return false
+	}
+	if event.GetSecretProviderClass() != "" && event.GetSecretProviderClass() != spcps.Status.SecretProviderClassName {
+		return false
+	}
+	if len(event.GetObjectIds()) == 0 {
+		return true
+	}
+	for _, id := range event.GetObjectIds() {
+		for _, obj := range spcps.Status.Objects {
+			if obj.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
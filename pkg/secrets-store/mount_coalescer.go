@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+// mountGroup coalesces concurrent identical mount requests into a single call to the
+// provider, with singleflight semantics. The zero value is ready to use.
+type mountGroup struct {
+	mu    sync.Mutex
+	calls map[string]*mountCall
+	// providerWrites has the providers that returned no files, as they write the files
+	// to the target path of the request themselves
+	providerWrites map[string]bool
+}
+
+// mountCall is an in-flight mount request, whose response is shared with the identical requests
+type mountCall struct {
+	done        chan struct{}
+	resp        *v1alpha1.MountResponse
+	errorReason string
+	err         error
+}
+
+// do calls mount for the key, or waits for the response of the in-flight call for the key.
+// shared is true if the response is from the call of another request.
+func (g *mountGroup) do(ctx context.Context, key string, mount func() (*v1alpha1.MountResponse, string, error)) (resp *v1alpha1.MountResponse, errorReason string, shared bool, err error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.resp, call.errorReason, true, call.err
+		case <-ctx.Done():
+			return nil, callErrorReason(ctx.Err()), true, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*mountCall)
+	}
+	call := &mountCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.resp, call.errorReason, call.err = mount()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+	return call.resp, call.errorReason, false, call.err
+}
+
+// setProviderWrites records if the provider writes the files to the target path itself
+func (g *mountGroup) setProviderWrites(provider string, writes bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.providerWrites == nil {
+		g.providerWrites = make(map[string]bool)
+	}
+	g.providerWrites[provider] = writes
+}
+
+// forget removes what's known about the provider, e.g. when a new provider process starts
+func (g *mountGroup) forget(provider string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.providerWrites, provider)
+}
+
+func (g *mountGroup) getProviderWrites(provider string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.providerWrites[provider]
+}
+
+// coalesceMount calls the provider with callMount. The concurrent identical requests with write
+// options from the PluginClientBuilder are coalesced into a single call, whose response is
+// shared by the requests and written to the target path of each request.
+func coalesceMount(ctx context.Context, client v1alpha1.CSIDriverProviderClient, req *v1alpha1.MountRequest, opts WriteOptions) (*v1alpha1.MountResponse, string, error) {
+	if opts.mounts == nil || opts.mounts.getProviderWrites(opts.provider) {
+		return callMount(ctx, client, req, opts)
+	}
+	key, ok := mountKey(opts.provider, req, opts)
+	if !ok {
+		return callMount(ctx, client, req, opts)
+	}
+
+	resp, errorReason, shared, err := opts.mounts.do(ctx, key, func() (*v1alpha1.MountResponse, string, error) {
+		resp, errorReason, err := callMount(ctx, client, req, opts)
+		if err == nil && newProviderError(resp.GetError()) == nil {
+			opts.mounts.setProviderWrites(opts.provider, len(resp.GetFiles()) == 0)
+		}
+		return resp, errorReason, err
+	})
+	if !shared {
+		return resp, errorReason, err
+	}
+	switch {
+	case ctx.Err() != nil:
+		return resp, errorReason, err
+	case err != nil && status.Code(err) == codes.Canceled:
+		// the request that called the provider was cancelled
+		return callMount(ctx, client, req, opts)
+	case err == nil && newProviderError(resp.GetError()) == nil && len(resp.GetFiles()) == 0:
+		// the provider wrote the files to the target path of the request that called the provider
+		return callMount(ctx, client, req, opts)
+	}
+	klog.V(3).InfoS("coalesced mount request", "provider", opts.provider, "targetPath", req.GetTargetPath())
+	return resp, errorReason, err
+}
+
+// mountKey returns the key of the mount request for the provider, and false if the request
+// can't be coalesced. The key has the pod namespace and service account, so the requests of
+// different identities are never coalesced, and a hash of the request without the attributes
+// specific to the pod.
+func mountKey(provider string, req *v1alpha1.MountRequest, opts WriteOptions) (string, bool) {
+	var attributes map[string]string
+	if err := json.Unmarshal([]byte(req.GetAttributes()), &attributes); err != nil {
+		return "", false
+	}
+	namespace, serviceAccount := attributes[csipodnamespace], attributes[csipodsa]
+	if namespace == "" || serviceAccount == "" {
+		return "", false
+	}
+	// the service account tokens are requested for each pod, and are for the same service account
+	delete(attributes, csipodname)
+	delete(attributes, csipoduid)
+	delete(attributes, csipodsatokens)
+
+	objectVersions := make(map[string]string, len(req.GetCurrentObjectVersion()))
+	for _, ov := range req.GetCurrentObjectVersion() {
+		objectVersions[ov.GetId()] = ov.GetVersion()
+	}
+	data, err := json.Marshal(struct {
+		Attributes     map[string]string
+		Secrets        string
+		Permission     string
+		ObjectVersions map[string]string
+		Stream         bool
+		MaxSize        int64
+	}{attributes, req.GetSecrets(), req.GetPermission(), objectVersions, opts.Stream, opts.MaxSize})
+	if err != nil {
+		return "", false
+	}
+	hash := sha256.Sum256(data)
+	return provider + "/" + namespace + "/" + serviceAccount + "/" + hex.EncodeToString(hash[:]), true
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+func mountAttributes(t *testing.T, podName, namespace, serviceAccount string) string {
+	t.Helper()
+	attributes, err := json.Marshal(map[string]string{
+		"secrets":       "[foo]",
+		csipodname:      podName,
+		csipodnamespace: namespace,
+		csipoduid:       podName + "-uid",
+		csipodsa:        serviceAccount,
+		csipodsatokens:  podName + "-token",
+	})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	return string(attributes)
+}
+
+func TestMountKey(t *testing.T) {
+	key := func(attributes, secrets string) (string, bool) {
+		return mountKey("provider1", &v1alpha1.MountRequest{Attributes: attributes, Secrets: secrets, Permission: "420", TargetPath: "/tmp/" + attributes}, WriteOptions{})
+	}
+	pod1, ok := key(mountAttributes(t, "pod1", "default", "sa1"), "{}")
+	if !ok {
+		t.Fatalf("expected the request to be coalesced")
+	}
+
+	// the pods of the same service account have the same key
+	if pod2, ok := key(mountAttributes(t, "pod2", "default", "sa1"), "{}"); !ok || pod2 != pod1 {
+		t.Errorf("expected the requests of the same service account to have the same key, got: %s, %s", pod1, pod2)
+	}
+
+	tests := []struct {
+		name       string
+		attributes string
+		secrets    string
+	}{
+		{name: "other service account", attributes: mountAttributes(t, "pod1", "default", "sa2"), secrets: "{}"},
+		{name: "other namespace", attributes: mountAttributes(t, "pod1", "ns2", "sa1"), secrets: "{}"},
+		{name: "other node publish secrets", attributes: mountAttributes(t, "pod1", "default", "sa1"), secrets: `{"clientid":"id"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, ok := key(test.attributes, test.secrets); !ok || got == pod1 {
+				t.Errorf("expected a different key, got: %s", got)
+			}
+		})
+	}
+
+	// the requests without a service account aren't coalesced
+	if _, ok := key(`{"secrets":"[foo]"}`, "{}"); ok {
+		t.Errorf("expected the request without a service account not to be coalesced")
+	}
+}
+
+func TestMountContent_Coalesced(t *testing.T) {
+	tests := []struct {
+		name             string
+		files            []*v1alpha1.File
+		expectedRequests int
+	}{
+		{
+			name:             "the files are written to each target path",
+			files:            []*v1alpha1.File{{Path: "foo", Mode: 0644, Contents: []byte("foo")}},
+			expectedRequests: 2,
+		},
+		{
+			name:             "the provider writes the files to the target path",
+			expectedRequests: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			socketPath := tmpdir.New(t, "", "ut")
+			pool := NewPluginClientBuilder(socketPath)
+			defer pool.Cleanup()
+
+			server, cleanup := fakeServer(t, socketPath, "provider1")
+			defer cleanup()
+			server.SetObjects(map[string]string{"foo": "v1"})
+			server.SetFiles(test.files)
+			server.SetMountDelay(500 * time.Millisecond)
+			server.SetCapabilities([]string{v1alpha1.CapabilityMountCoalescing})
+			if err := server.Start(); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+			client, err := pool.Get(context.Background(), "provider1")
+			if err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+
+			// three pods of sa1 and a pod of sa2 mount at the same time
+			pods := []struct{ name, serviceAccount string }{{"pod1", "sa1"}, {"pod2", "sa1"}, {"pod3", "sa1"}, {"pod4", "sa2"}}
+			targetPaths := make([]string, len(pods))
+			errs := make([]error, len(pods))
+			var wg sync.WaitGroup
+			for i, pod := range pods {
+				targetPaths[i] = tmpdir.New(t, "", "ut")
+				wg.Add(1)
+				attributes := mountAttributes(t, pod.name, "default", pod.serviceAccount)
+				go func(i int) {
+					defer wg.Done()
+					_, _, errs[i] = MountContent(context.Background(), client, attributes, "{}", targetPaths[i], "420", nil, pool.WriteOptions("provider1", WriteOptions{}))
+				}(i)
+				if i == 0 {
+					waitFor(t, func() bool { return len(server.MountRequests()) == 1 })
+				}
+			}
+			wg.Wait()
+
+			for i, err := range errs {
+				if err != nil {
+					t.Fatalf("expected err to be nil for %s, got: %+v", pods[i].name, err)
+				}
+			}
+			if got := len(server.MountRequests()); got != test.expectedRequests {
+				t.Errorf("expected %d mount requests, got: %d", test.expectedRequests, got)
+			}
+			if len(test.files) == 0 {
+				return
+			}
+			for i, targetPath := range targetPaths {
+				if _, err := os.Stat(filepath.Join(targetPath, "foo")); err != nil {
+					t.Errorf("expected the file to be written for %s, got: %+v", pods[i].name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginClientBuilder_WriteOptionsCoalescing(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []string
+		disabled     bool
+		coalesced    bool
+	}{
+		{
+			name: "provider without the capability",
+		},
+		{
+			name:         "provider with the capability",
+			capabilities: []string{v1alpha1.CapabilityMountCoalescing},
+			coalesced:    true,
+		},
+		{
+			name:         "provider that can unmount",
+			capabilities: []string{v1alpha1.CapabilityMountCoalescing, v1alpha1.CapabilityUnmount},
+		},
+		{
+			name:         "disabled in the provider config",
+			capabilities: []string{v1alpha1.CapabilityMountCoalescing},
+			disabled:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			socketPath := tmpdir.New(t, "", "ut")
+			pool := NewPluginClientBuilder(socketPath)
+			defer pool.Cleanup()
+			if err := pool.SetProviderConfig(&ProviderConfig{Default: ProviderCallConfig{DisableMountCoalescing: test.disabled}}); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+
+			server, cleanup := fakeServer(t, socketPath, "provider1")
+			defer cleanup()
+			server.SetCapabilities(test.capabilities)
+			if err := server.Start(); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+			if _, err := pool.Get(context.Background(), "provider1"); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+
+			if got := pool.WriteOptions("provider1", WriteOptions{}).mounts != nil; got != test.coalesced {
+				t.Errorf("expected the mount requests to be coalesced: %v, got: %v", test.coalesced, got)
+			}
+		})
+	}
+}
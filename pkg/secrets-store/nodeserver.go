@@ -24,13 +24,21 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	csicommon "sigs.k8s.io/secrets-store-csi-driver/pkg/csi-common"
 	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/k8sutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/policyutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	mount "k8s.io/mount-utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,11 +51,15 @@ type nodeServer struct {
 	reporter           StatsReporter
 	nodeID             string
 	client             client.Client
+	reader             client.Reader
+	eventRecorder      record.EventRecorder
 	providerClients    *PluginClientBuilder
 }
 
 const (
 	permission os.FileMode = 0644
+	// unmountTimeout is the timeout of the provider's Unmount() RPC on node unpublish
+	unmountTimeout = 10 * time.Second
 
 	csipodname               = "csi.storage.k8s.io/pod.name"
 	csipodnamespace          = "csi.storage.k8s.io/pod.namespace"
@@ -55,6 +67,9 @@ const (
 	csipodsa                 = "csi.storage.k8s.io/serviceAccount.name"
 	csipodsatokens           = "csi.storage.k8s.io/serviceAccount.tokens" //nolint
 	secretProviderClassField = "secretProviderClass"
+	// clusterSecretProviderClassField references a ClusterSecretProviderClass instead of a
+	// SecretProviderClass in the pod namespace
+	clusterSecretProviderClassField = "clusterSecretProviderClass"
 )
 
 func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (npvr *csi.NodePublishVolumeResponse, err error) {
@@ -63,10 +78,19 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 	var podName, podNamespace, podUID string
 	var targetPath string
 	var mounted bool
+	var secretProviderClass, secretProviderClassKind string
+	// providerCalled is set to true once the provider mount request has been made
+	var providerCalled bool
 	errorReason := internalerrors.FailedToMount
 
 	defer func() {
 		if err != nil {
+			// record the failure in the conditions of the spc pod status if it exists from a previous mount
+			if podName != "" && secretProviderClass != "" && !isMockProvider(providerName) {
+				if updateErr := updateSecretProviderClassPodStatusConditions(ctx, ns.client, spcPodStatusName(podName, podNamespace, secretProviderClassKind, secretProviderClass), podNamespace, errorReason, providerCalled, err); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update spc pod status conditions", "pod", klog.ObjectRef{Namespace: podNamespace, Name: podName})
+				}
+			}
 			// if there is an error at any stage during node publish volume and if the path
 			// has already been mounted, unmount the target path so the next time kubelet calls
 			// again for mount, entire node publish volume is retried
@@ -100,7 +124,15 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 	mountFlags := req.GetVolumeCapability().GetMount().GetMountFlags()
 	secrets := req.GetSecrets()
 
-	secretProviderClass := attrib[secretProviderClassField]
+	secretProviderClass = attrib[secretProviderClassField]
+	secretProviderClassKind = secretsstorev1.SecretProviderClassKind
+	if clusterSecretProviderClass := attrib[clusterSecretProviderClassField]; clusterSecretProviderClass != "" {
+		if secretProviderClass != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "only one of %s and %s can be set", secretProviderClassField, clusterSecretProviderClassField)
+		}
+		secretProviderClass = clusterSecretProviderClass
+		secretProviderClassKind = secretsstorev1.ClusterSecretProviderClassKind
+	}
 	providerName = attrib["providerName"]
 	podName = attrib[csipodname]
 	podNamespace = attrib[csipodnamespace]
@@ -147,7 +179,7 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		return nil, fmt.Errorf("secretProviderClass is not set")
 	}
 
-	spc, err := getSecretProviderItem(ctx, ns.client, secretProviderClass, podNamespace)
+	spc, err := getSecretProviderItem(ctx, ns.client, secretProviderClassKind, secretProviderClass, podNamespace)
 	if err != nil {
 		errorReason = internalerrors.SecretProviderClassNotFound
 		return nil, err
@@ -157,8 +189,50 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		return nil, err
 	}
 	providerName = provider
-	parameters, err = getParametersFromSPC(spc)
+	// fail fast without mounting while the provider is known to be down
+	if err = ns.providerClients.CheckHealth(providerName); err != nil {
+		errorReason = internalerrors.ProviderUnhealthy
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	spcParameters, err := getParametersFromSPC(spc)
+	if err != nil {
+		return nil, err
+	}
+
+	// ensure it's read-only
+	if !req.GetReadonly() {
+		return nil, status.Error(codes.InvalidArgument, "Readonly is not true in request")
+	}
+
+	// the pod is read from the api server, as the cache may not know the pod yet when the volume
+	// is published right after the pod is scheduled. The fsGroup of the pod owns the files in the
+	// mount, so the mount fails with a retryable error instead of writing the files as root.
+	pod := &corev1.Pod{}
+	if err = ns.reader.Get(ctx, client.ObjectKey{Namespace: podNamespace, Name: podName}, pod); err != nil {
+		klog.ErrorS(err, "failed to get pod", "pod", klog.ObjectRef{Namespace: podNamespace, Name: podName})
+		return nil, status.Error(codes.Unavailable, fmt.Sprintf("failed to get pod %s/%s, err: %v", podNamespace, podName, err))
+	}
+
+	// expand the placeholders for the pod metadata in the spc parameters. The pod is read from the
+	// api server, so a missing label or annotation is an error in the pod or spc, not a stale cache.
+	parameters, err = k8sutil.ExpandParameters(spcParameters, pod)
 	if err != nil {
+		errorReason = internalerrors.FailedToExpandParameters
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// add the keys of the configmaps and secrets referenced in parametersFrom
+	if err = spcutil.AddParametersFrom(ctx, spcutil.NewParametersSource(ns.reader), spc, parameters); err != nil {
+		errorReason = internalerrors.ParametersFromNotFound
+		return nil, err
+	}
+	// enforce the secrets store policies in the pod namespace before the provider is called
+	if err = policyutil.Enforce(ctx, ns.client, podNamespace, spc, parameters); err != nil {
+		var violation *policyutil.ViolationError
+		if errors.As(err, &violation) {
+			errorReason = internalerrors.SecretsStorePolicyViolation
+			ns.eventRecorder.Event(pod, corev1.EventTypeWarning, internalerrors.SecretsStorePolicyViolation, err.Error())
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, err
 	}
 	parameters[csipodname] = attrib[csipodname]
@@ -167,9 +241,14 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 	parameters[csipodsa] = attrib[csipodsa]
 	parameters[csipodsatokens], _ = attrib[csipodsatokens] //nolint
 
-	// ensure it's read-only
-	if !req.GetReadonly() {
-		return nil, status.Error(codes.InvalidArgument, "Readonly is not true in request")
+	// the files in the mount are owned by the fsGroup of the pod, so they can be read by
+	// non-root containers.
+	// TODO: advertise the VOLUME_MOUNT_GROUP node capability in NodeGetCapabilities and use
+	// VolumeCapability.MountVolume.VolumeMountGroup from the request when it's set. They were
+	// added in CSI spec v1.5.0, and the driver is still built with v1.3.0.
+	fsGroup, err := k8sutil.FSGroup(pod, attrib)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	parametersStr, err := json.Marshal(parameters)
@@ -182,7 +261,11 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		klog.ErrorS(err, "failed to marshal node publish secrets", "pod", klog.ObjectRef{Namespace: podNamespace, Name: podName})
 		return nil, err
 	}
-	permissionStr, err := json.Marshal(permission)
+	filePermission := permission
+	if spc.Spec.DefaultMode != nil {
+		filePermission = os.FileMode(*spc.Spec.DefaultMode)
+	}
+	permissionStr, err := json.Marshal(filePermission)
 	if err != nil {
 		klog.ErrorS(err, "failed to marshal file permission", "pod", klog.ObjectRef{Namespace: podNamespace, Name: podName})
 		return nil, err
@@ -203,13 +286,14 @@ func (ns *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		}
 	}
 	mounted = true
-	var objectVersions map[string]string
-	if objectVersions, errorReason, err = ns.mountSecretsStoreObjectContent(ctx, providerName, string(parametersStr), string(secretStr), targetPath, string(permissionStr), podName); err != nil {
-		return nil, fmt.Errorf("failed to mount secrets store objects for pod %s/%s, err: %v", podNamespace, podName, err)
+	providerCalled = true
+	var objects []secretsstorev1.SecretProviderClassObject
+	if objects, errorReason, err = ns.mountSecretsStoreObjectContent(ctx, providerName, string(parametersStr), string(secretStr), targetPath, string(permissionStr), podName, NewWriteOptions(spc.Spec, fsGroup)); err != nil {
+		return nil, status.Errorf(mountErrorCode(err), "failed to mount secrets store objects for pod %s/%s, err: %v", podNamespace, podName, err)
 	}
 
 	// create the secret provider class pod status object
-	if err = createSecretProviderClassPodStatus(ctx, ns.client, podName, podNamespace, podUID, secretProviderClass, targetPath, ns.nodeID, true, objectVersions); err != nil {
+	if err = createSecretProviderClassPodStatus(ctx, ns.client, podName, podNamespace, podUID, secretProviderClassKind, secretProviderClass, providerName, targetPath, ns.nodeID, true, objects); err != nil {
 		return nil, fmt.Errorf("failed to create secret provider class pod status for pod %s/%s, err: %v", podNamespace, podName, err)
 	}
 
@@ -260,6 +344,10 @@ func (ns *nodeServer) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// notify the provider after the mount is cleaned up, so the provider isn't called
+	// again when the node unpublish is retried
+	ns.unmountSecretsStoreObjectContent(ctx, targetPath)
+
 	klog.InfoS("node unpublish volume complete", "targetPath", targetPath)
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
@@ -288,7 +376,7 @@ func (ns *nodeServer) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
-func (ns *nodeServer) mountSecretsStoreObjectContent(ctx context.Context, providerName, attributes, secrets, targetPath, permission, podName string) (map[string]string, string, error) {
+func (ns *nodeServer) mountSecretsStoreObjectContent(ctx context.Context, providerName, attributes, secrets, targetPath, permission, podName string, opts WriteOptions) ([]secretsstorev1.SecretProviderClassObject, string, error) {
 	if len(attributes) == 0 {
 		return nil, "", errors.New("missing attributes")
 	}
@@ -306,12 +394,58 @@ func (ns *nodeServer) mountSecretsStoreObjectContent(ctx context.Context, provid
 
 	client, err := ns.providerClients.Get(ctx, providerName)
 	if err != nil {
-		return nil, "", fmt.Errorf("error connecting to provider %q: %w", providerName, err)
+		return nil, LookupErrorReason(err), fmt.Errorf("error connecting to provider %q: %w", providerName, err)
 	}
 
 	klog.InfoS("Using grpc client", "provider", providerName, "pod", podName)
 
-	return MountContent(ctx, client, attributes, secrets, targetPath, permission, nil)
+	return MountContent(ctx, client, attributes, secrets, targetPath, permission, nil, ns.providerClients.WriteOptions(providerName, opts))
+}
+
+// unmountSecretsStoreObjectContent calls the Unmount() RPC of the provider the volume was published
+// with, if the provider declares the Unmount capability, so the provider can revoke the credentials
+// it created for the pod. It's best-effort: errors are logged and don't fail the node unpublish.
+func (ns *nodeServer) unmountSecretsStoreObjectContent(ctx context.Context, targetPath string) {
+	if ns.client == nil || ns.providerClients == nil {
+		return
+	}
+	spcps, err := getSecretProviderClassPodStatusForTargetPath(ctx, ns.client, ns.nodeID, targetPath)
+	if err != nil {
+		klog.ErrorS(err, "failed to get spc pod status for target path", "targetPath", targetPath)
+		return
+	}
+	if spcps == nil {
+		klog.V(5).InfoS("no spc pod status for target path, skipping provider unmount", "targetPath", targetPath)
+		return
+	}
+	providerName := spcps.Status.Provider
+	if providerName == "" {
+		// the spc pod status was created by a driver version that didn't record the provider
+		spc, err := getSecretProviderItem(ctx, ns.client, spcps.Status.SecretProviderClassKind, spcps.Status.SecretProviderClassName, spcps.Namespace)
+		if err != nil {
+			klog.ErrorS(err, "failed to get secret provider class for provider unmount", "spcps", klog.KObj(spcps))
+			return
+		}
+		providerName = string(spc.Spec.Provider)
+	}
+	// the capabilities are cached when the provider is registered, so the providers that
+	// can't unmount aren't connected to
+	if !ns.providerClients.HasCapability(providerName, v1alpha1.CapabilityUnmount) {
+		return
+	}
+	providerClient, err := ns.providerClients.Get(ctx, providerName)
+	if err != nil {
+		klog.ErrorS(err, "failed to lookup provider client for provider unmount", "provider", providerName, "spcps", klog.KObj(spcps))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, unmountTimeout)
+	defer cancel()
+	if err := UnmountContent(ctx, providerClient, spcps, podUIDForSecretProviderClassPodStatus(spcps)); err != nil {
+		klog.ErrorS(err, "provider unmount failed", "provider", providerName, "pod", klog.ObjectRef{Namespace: spcps.Namespace, Name: spcps.Status.PodName})
+		return
+	}
+	klog.InfoS("provider unmount complete", "provider", providerName, "pod", klog.ObjectRef{Namespace: spcps.Namespace, Name: spcps.Status.PodName})
 }
 
 func (ns *nodeServer) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
@@ -22,18 +22,22 @@ import (
 	"path/filepath"
 	"testing"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store/mocks"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+	providerfake "sigs.k8s.io/secrets-store-csi-driver/provider/fake"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
 	"sigs.k8s.io/secrets-store-csi-driver/test/e2eprovider"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	mount "k8s.io/mount-utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -42,7 +46,17 @@ import (
 func testNodeServer(t *testing.T, tmpDir string, mountPoints []mount.MountPoint, client client.Client, reporter StatsReporter) (*nodeServer, error) {
 	t.Helper()
 	providerClients := NewPluginClientBuilder(tmpDir)
-	return newNodeServer(NewFakeDriver(), tmpDir, "testnode", mount.NewFakeMounter(mountPoints), providerClients, client, reporter)
+	return newNodeServer(NewFakeDriver(), tmpDir, "testnode", mount.NewFakeMounter(mountPoints), providerClients, client, client, record.NewFakeRecorder(10), reporter)
+}
+
+func newTestPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "default",
+			UID:       "poduid1",
+		},
+	}
 }
 
 func TestNodePublishVolume(t *testing.T) {
@@ -117,7 +131,7 @@ func TestNodePublishVolume(t *testing.T) {
 				VolumeContext:    map[string]string{"secretProviderClass": "provider1", csipodname: "pod1", csipodnamespace: "default"},
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "provider1",
 						Namespace: "testns",
@@ -136,7 +150,7 @@ func TestNodePublishVolume(t *testing.T) {
 				VolumeContext:    map[string]string{"secretProviderClass": "provider1", csipodname: "pod1", csipodnamespace: "default"},
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "provider1",
 						Namespace: "default",
@@ -155,12 +169,12 @@ func TestNodePublishVolume(t *testing.T) {
 				VolumeContext:    map[string]string{"secretProviderClass": "provider1", csipodname: "pod1", csipodnamespace: "default"},
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "provider1",
 						Namespace: "default",
 					},
-					Spec: v1alpha1.SecretProviderClassSpec{
+					Spec: secretsstorev1.SecretProviderClassSpec{
 						Provider: "provider1",
 					},
 				},
@@ -177,12 +191,12 @@ func TestNodePublishVolume(t *testing.T) {
 				VolumeContext:    map[string]string{"secretProviderClass": "provider1", csipodname: "pod1", csipodnamespace: "default"},
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "provider1",
 						Namespace: "default",
 					},
-					Spec: v1alpha1.SecretProviderClassSpec{
+					Spec: secretsstorev1.SecretProviderClassSpec{
 						Provider:   "provider1",
 						Parameters: map[string]string{"parameter1": "value1"},
 					},
@@ -201,12 +215,12 @@ func TestNodePublishVolume(t *testing.T) {
 				Readonly:         true,
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "provider1",
 						Namespace: "default",
 					},
-					Spec: v1alpha1.SecretProviderClassSpec{
+					Spec: secretsstorev1.SecretProviderClassSpec{
 						Provider:   "provider1",
 						Parameters: map[string]string{"parameter1": "value1"},
 					},
@@ -234,12 +248,12 @@ func TestNodePublishVolume(t *testing.T) {
 				Readonly: true,
 			},
 			initObjects: []runtime.Object{
-				&v1alpha1.SecretProviderClass{
+				&secretsstorev1.SecretProviderClass{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "simple_provider",
 						Namespace: "default",
 					},
-					Spec: v1alpha1.SecretProviderClassSpec{
+					Spec: secretsstorev1.SecretProviderClassSpec{
 						Provider:   "simple_provider",
 						Parameters: map[string]string{"parameter1": "value1"},
 					},
@@ -249,13 +263,176 @@ func TestNodePublishVolume(t *testing.T) {
 			expectedErr:        false,
 			shouldRetryRemount: true,
 		},
+		{
+			name: "pod not found",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext:    map[string]string{"secretProviderClass": "simple_provider", csipodname: "pod1", csipodnamespace: "default", csipoduid: "poduid1"},
+				Readonly:         true,
+			},
+			initObjects: []runtime.Object{
+				&secretsstorev1.SecretProviderClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple_provider",
+						Namespace: "default",
+					},
+					Spec: secretsstorev1.SecretProviderClassSpec{
+						Provider:   "simple_provider",
+						Parameters: map[string]string{"parameter1": "value1"},
+					},
+				},
+			},
+			RPCCode:            codes.Unavailable,
+			wantsRPCCode:       true,
+			expectedErr:        true,
+			shouldRetryRemount: true,
+		},
+		{
+			name: "parameter placeholder can't be expanded",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext:    map[string]string{"secretProviderClass": "simple_provider", csipodname: "pod1", csipodnamespace: "default", csipoduid: "poduid1"},
+				Readonly:         true,
+			},
+			initObjects: []runtime.Object{
+				newTestPod(),
+				&secretsstorev1.SecretProviderClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple_provider",
+						Namespace: "default",
+					},
+					Spec: secretsstorev1.SecretProviderClassSpec{
+						Provider:   "simple_provider",
+						Parameters: map[string]string{"parameter1": "/teams/${pod.labels['team']}/db-password"},
+					},
+				},
+			},
+			RPCCode:            codes.InvalidArgument,
+			wantsRPCCode:       true,
+			expectedErr:        true,
+			shouldRetryRemount: true,
+		},
+		{
+			name: "parametersFrom configmap not found",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext:    map[string]string{"secretProviderClass": "simple_provider", csipodname: "pod1", csipodnamespace: "default", csipoduid: "poduid1"},
+				Readonly:         true,
+			},
+			initObjects: []runtime.Object{
+				newTestPod(),
+				&secretsstorev1.SecretProviderClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple_provider",
+						Namespace: "default",
+					},
+					Spec: secretsstorev1.SecretProviderClassSpec{
+						Provider: "simple_provider",
+						ParametersFrom: []*secretsstorev1.ParametersFromSource{
+							{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+						},
+					},
+				},
+			},
+			expectedErr:        true,
+			shouldRetryRemount: true,
+		},
+		{
+			name: "provider not allowed by secrets store policy",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext:    map[string]string{"secretProviderClass": "simple_provider", csipodname: "pod1", csipodnamespace: "default", csipoduid: "poduid1"},
+				Readonly:         true,
+			},
+			initObjects: []runtime.Object{
+				newTestPod(),
+				&secretsstorev1.SecretProviderClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple_provider",
+						Namespace: "default",
+					},
+					Spec: secretsstorev1.SecretProviderClassSpec{
+						Provider:   "simple_provider",
+						Parameters: map[string]string{"parameter1": "value1"},
+					},
+				},
+				&secretsstorev1.SecretsStorePolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "policy1",
+						Namespace: "default",
+					},
+					Spec: secretsstorev1.SecretsStorePolicySpec{
+						AllowedProviders: []string{"vault"},
+					},
+				},
+			},
+			RPCCode:            codes.PermissionDenied,
+			wantsRPCCode:       true,
+			expectedErr:        true,
+			shouldRetryRemount: true,
+		},
+		{
+			name: "both secret provider class and cluster secret provider class set",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext:    map[string]string{"secretProviderClass": "provider1", "clusterSecretProviderClass": "provider1", csipodname: "pod1", csipodnamespace: "default"},
+			},
+			RPCCode:            codes.InvalidArgument,
+			wantsRPCCode:       true,
+			expectedErr:        true,
+			shouldRetryRemount: true,
+		},
+		{
+			name: "cluster secret provider class",
+			nodePublishVolReq: csi.NodePublishVolumeRequest{
+				VolumeCapability: &csi.VolumeCapability{},
+				VolumeId:         "testvolid1",
+				TargetPath:       tmpdir.New(t, "", "ut"),
+				VolumeContext: map[string]string{
+					"clusterSecretProviderClass": "simple_provider",
+					csipodname:                   "pod1",
+					csipodnamespace:              "default",
+					csipoduid:                    "poduid1",
+				},
+				Readonly: true,
+			},
+			initObjects: []runtime.Object{
+				&secretsstorev1.ClusterSecretProviderClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "simple_provider",
+					},
+					Spec: secretsstorev1.ClusterSecretProviderClassSpec{
+						SecretProviderClassSpec: secretsstorev1.SecretProviderClassSpec{
+							Provider:   "simple_provider",
+							Parameters: map[string]string{"parameter1": "value1"},
+						},
+					},
+				},
+			},
+			mountPoints:        []mount.MountPoint{},
+			expectedErr:        false,
+			shouldRetryRemount: true,
+		},
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(schema.GroupVersion{Group: v1alpha1.GroupVersion.Group, Version: v1alpha1.GroupVersion.Version},
-		&v1alpha1.SecretProviderClass{},
-		&v1alpha1.SecretProviderClassList{},
-		&v1alpha1.SecretProviderClassPodStatus{},
+	s.AddKnownTypes(schema.GroupVersion{Group: secretsstorev1.GroupVersion.Group, Version: secretsstorev1.GroupVersion.Version},
+		&secretsstorev1.SecretProviderClass{},
+		&secretsstorev1.SecretProviderClassList{},
+		&secretsstorev1.SecretProviderClassPodStatus{},
+		&secretsstorev1.ClusterSecretProviderClass{},
+		&secretsstorev1.SecretsStorePolicy{},
+		&secretsstorev1.SecretsStorePolicyList{},
 	)
 
 	for _, test := range tests {
@@ -332,6 +509,131 @@ func TestNodePublishVolume(t *testing.T) {
 	}
 }
 
+func TestNodePublishVolumeSecretProviderClassKinds(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(schema.GroupVersion{Group: secretsstorev1.GroupVersion.Group, Version: secretsstorev1.GroupVersion.Version},
+		&secretsstorev1.SecretProviderClass{},
+		&secretsstorev1.SecretProviderClassList{},
+		&secretsstorev1.SecretProviderClassPodStatus{},
+		&secretsstorev1.SecretProviderClassPodStatusList{},
+		&secretsstorev1.ClusterSecretProviderClass{},
+		&secretsstorev1.SecretsStorePolicy{},
+		&secretsstorev1.SecretsStorePolicyList{},
+	)
+	spec := secretsstorev1.SecretProviderClassSpec{
+		Provider:   "simple_provider",
+		Parameters: map[string]string{"secrets": "- key: foo\n  value: bar"},
+	}
+	// a secret provider class and a cluster secret provider class with the same name
+	c := fake.NewFakeClientWithScheme(s,
+		newTestPod(),
+		&secretsstorev1.SecretProviderClass{ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"}, Spec: spec},
+		&secretsstorev1.ClusterSecretProviderClass{ObjectMeta: metav1.ObjectMeta{Name: "spc1"}, Spec: secretsstorev1.ClusterSecretProviderClassSpec{SecretProviderClassSpec: spec}},
+	)
+
+	tmpDir := tmpdir.New(t, "", "ut")
+	server, err := e2eprovider.NewSimpleCSIProviderServer(filepath.Join(tmpDir, "simple_provider.sock"))
+	if err != nil {
+		t.Fatalf("Error creating e2e test server: %v", err)
+	}
+	if err = server.Start(); err != nil {
+		t.Fatalf("Error starting e2e test server: %v", err)
+	}
+	defer server.Stop()
+	ns, err := testNodeServer(t, tmpDir, []mount.MountPoint{}, c, mocks.NewFakeReporter())
+	if err != nil {
+		t.Fatalf("expected error to be nil, got: %+v", err)
+	}
+
+	expected := map[string]string{
+		"pod1-default-spc1":                            secretsstorev1.SecretProviderClassKind,
+		"pod1-default-spc1-clustersecretproviderclass": secretsstorev1.ClusterSecretProviderClassKind,
+	}
+	for _, field := range []string{secretProviderClassField, clusterSecretProviderClassField} {
+		targetPath := tmpdir.New(t, "", "ut")
+		defer os.RemoveAll(targetPath)
+		_, err = ns.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+			VolumeCapability: &csi.VolumeCapability{},
+			VolumeId:         "testvolid1",
+			TargetPath:       targetPath,
+			VolumeContext: map[string]string{
+				field:           "spc1",
+				csipodname:      "pod1",
+				csipodnamespace: "default",
+				csipoduid:       "poduid1",
+			},
+			Readonly: true,
+		})
+		if err != nil {
+			t.Fatalf("NodePublishVolume() with %s error = %v", field, err)
+		}
+	}
+
+	for name, kind := range expected {
+		spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, spcps); err != nil {
+			t.Fatalf("failed to get spc pod status %s, err: %v", name, err)
+		}
+		if spcps.Status.SecretProviderClassKind != kind || spcps.Status.SecretProviderClassName != "spc1" {
+			t.Fatalf("expected spc pod status %s for %s spc1, got %+v", name, kind, spcps.Status)
+		}
+	}
+}
+
+func TestNodePublishVolumePodNotInCache(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(schema.GroupVersion{Group: secretsstorev1.GroupVersion.Group, Version: secretsstorev1.GroupVersion.Version},
+		&secretsstorev1.SecretProviderClass{},
+		&secretsstorev1.SecretProviderClassList{},
+		&secretsstorev1.SecretProviderClassPodStatus{},
+		&secretsstorev1.SecretProviderClassPodStatusList{},
+		&secretsstorev1.SecretsStorePolicy{},
+		&secretsstorev1.SecretsStorePolicyList{},
+	)
+	spc := &secretsstorev1.SecretProviderClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider: "simple_provider",
+			Parameters: map[string]string{
+				"secrets": "- key: foo\n  value: bar",
+				"path":    "/teams/${pod.labels['team']}/db-password",
+			},
+		},
+	}
+	// the pod isn't in the cache yet, only in the api server
+	pod := newTestPod()
+	pod.Labels = map[string]string{"team": "a"}
+	cache := fake.NewFakeClientWithScheme(s, spc)
+	apiReader := fake.NewFakeClientWithScheme(s, pod)
+
+	tmpDir := tmpdir.New(t, "", "ut")
+	server, err := e2eprovider.NewSimpleCSIProviderServer(filepath.Join(tmpDir, "simple_provider.sock"))
+	if err != nil {
+		t.Fatalf("Error creating e2e test server: %v", err)
+	}
+	if err = server.Start(); err != nil {
+		t.Fatalf("Error starting e2e test server: %v", err)
+	}
+	defer server.Stop()
+	ns, err := newNodeServer(NewFakeDriver(), tmpDir, "testnode", mount.NewFakeMounter([]mount.MountPoint{}), NewPluginClientBuilder(tmpDir), cache, apiReader, record.NewFakeRecorder(10), mocks.NewFakeReporter())
+	if err != nil {
+		t.Fatalf("expected error to be nil, got: %+v", err)
+	}
+
+	targetPath := tmpdir.New(t, "", "ut")
+	defer os.RemoveAll(targetPath)
+	_, err = ns.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+		VolumeCapability: &csi.VolumeCapability{},
+		VolumeId:         "testvolid1",
+		TargetPath:       targetPath,
+		VolumeContext:    map[string]string{secretProviderClassField: "spc1", csipodname: "pod1", csipodnamespace: "default", csipoduid: "poduid1"},
+		Readonly:         true,
+	})
+	if err != nil {
+		t.Fatalf("NodePublishVolume() error = %v", err)
+	}
+}
+
 func TestMountSecretsStoreObjectContent(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -365,7 +667,7 @@ func TestMountSecretsStoreObjectContent(t *testing.T) {
 			if err != nil {
 				t.Fatalf("expected error to be nil, got: %+v", err)
 			}
-			_, errorReason, err := ns.mountSecretsStoreObjectContent(context.TODO(), "provider1", test.attributes, test.secrets, test.targetPath, test.permission, "pod")
+			_, errorReason, err := ns.mountSecretsStoreObjectContent(context.TODO(), "provider1", test.attributes, test.secrets, test.targetPath, test.permission, "pod", WriteOptions{})
 			if errorReason != test.expectedErrorReason {
 				t.Fatalf("expected error reason to be %s, got: %s", test.expectedErrorReason, errorReason)
 			}
@@ -417,9 +719,9 @@ func TestNodeUnpublishVolume(t *testing.T) {
 		},
 	}
 	s := scheme.Scheme
-	s.AddKnownTypes(schema.GroupVersion{Group: v1alpha1.GroupVersion.Group, Version: v1alpha1.GroupVersion.Version},
-		&v1alpha1.SecretProviderClass{},
-		&v1alpha1.SecretProviderClassList{},
+	s.AddKnownTypes(schema.GroupVersion{Group: secretsstorev1.GroupVersion.Group, Version: secretsstorev1.GroupVersion.Version},
+		&secretsstorev1.SecretProviderClass{},
+		&secretsstorev1.SecretProviderClassList{},
 	)
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -475,3 +777,117 @@ func TestNodeUnpublishVolume(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeUnpublishVolumeProviderUnmount(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []string
+		// provider is the provider recorded in the spc pod status at mount
+		provider string
+		// deletedSPC is true if the secret provider class is deleted before the pod
+		deletedSPC  bool
+		wantUnmount bool
+	}{
+		{
+			name:         "provider with unmount capability",
+			capabilities: []string{v1alpha1.CapabilityUnmount},
+			provider:     "provider1",
+			wantUnmount:  true,
+		},
+		{
+			name:         "provider with unmount capability and deleted secret provider class",
+			capabilities: []string{v1alpha1.CapabilityUnmount},
+			provider:     "provider1",
+			deletedSPC:   true,
+			wantUnmount:  true,
+		},
+		{
+			name:         "provider not recorded in the spc pod status",
+			capabilities: []string{v1alpha1.CapabilityUnmount},
+			wantUnmount:  true,
+		},
+		{
+			name:     "provider without unmount capability",
+			provider: "provider1",
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(schema.GroupVersion{Group: secretsstorev1.GroupVersion.Group, Version: secretsstorev1.GroupVersion.Version},
+		&secretsstorev1.SecretProviderClass{},
+		&secretsstorev1.SecretProviderClassList{},
+		&secretsstorev1.SecretProviderClassPodStatus{},
+		&secretsstorev1.SecretProviderClassPodStatusList{},
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpDir := tmpdir.New(t, "", "ut")
+			server, err := providerfake.NewMocKCSIProviderServer(filepath.Join(tmpDir, "provider1.sock"))
+			if err != nil {
+				t.Fatalf("expected error to be nil, got: %+v", err)
+			}
+			server.SetCapabilities(test.capabilities)
+			if err = server.Start(); err != nil {
+				t.Fatalf("expected error to be nil, got: %+v", err)
+			}
+			defer server.Stop()
+
+			targetPath := tmpdir.New(t, "", "mount")
+			spc := &secretsstorev1.SecretProviderClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+				Spec:       secretsstorev1.SecretProviderClassSpec{Provider: "provider1"},
+			}
+			spcps := &secretsstorev1.SecretProviderClassPodStatus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "pod1-default-spc1",
+					Namespace:       "default",
+					Labels:          map[string]string{secretsstorev1.InternalNodeLabel: "testnode"},
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "pod-uid"}},
+				},
+				Status: secretsstorev1.SecretProviderClassPodStatusStatus{
+					PodName:                 "pod1",
+					SecretProviderClassName: "spc1",
+					Provider:                test.provider,
+					TargetPath:              targetPath,
+					Objects:                 []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v1"}},
+				},
+			}
+			objs := []runtime.Object{spcps}
+			if !test.deletedSPC {
+				objs = append(objs, spc)
+			}
+			ns, err := testNodeServer(t, tmpDir, []mount.MountPoint{{Path: targetPath}}, fake.NewFakeClientWithScheme(s, objs...), mocks.NewFakeReporter())
+			if err != nil {
+				t.Fatalf("expected error to be nil, got: %+v", err)
+			}
+			// register the provider, which caches its capabilities
+			if _, err = ns.providerClients.Get(context.TODO(), "provider1"); err != nil {
+				t.Fatalf("expected error to be nil, got: %+v", err)
+			}
+
+			if _, err = ns.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "testvolid1", TargetPath: targetPath}); err != nil {
+				t.Fatalf("expected error to be nil, got: %+v", err)
+			}
+
+			requests := server.UnmountRequests()
+			if !test.wantUnmount {
+				if len(requests) != 0 {
+					t.Fatalf("expected no unmount requests, got: %+v", requests)
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("expected 1 unmount request, got: %d", len(requests))
+			}
+			req := requests[0]
+			if req.GetPodName() != "pod1" || req.GetPodNamespace() != "default" || req.GetPodUid() != "pod-uid" ||
+				req.GetSecretProviderClass() != "spc1" || req.GetTargetPath() != targetPath {
+				t.Errorf("unexpected unmount request: %+v", req)
+			}
+			if ov := req.GetCurrentObjectVersion(); len(ov) != 1 || ov[0].GetId() != "secret/object1" || ov[0].GetVersion() != "v1" {
+				t.Errorf("unexpected object versions in unmount request: %+v", ov)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+// registrationTimeout is the timeout to connect to a provider when its socket is created
+const registrationTimeout = 10 * time.Second
+
+// PluginWatcher watches the provider volume path for provider sockets, modeled on
+// the kubelet plugin watcher. The providers are connected to and validated with their
+// Version() RPC when their socket is created, and the connections are closed when their
+// socket is removed, so the registry of the PluginClientBuilder only has live providers.
+type PluginWatcher struct {
+	providerClients *PluginClientBuilder
+}
+
+// NewPluginWatcher returns a plugin watcher for the provider volume path of the
+// plugin client builder
+func NewPluginWatcher(providerClients *PluginClientBuilder) *PluginWatcher {
+	return &PluginWatcher{providerClients: providerClients}
+}
+
+// Run registers the providers with a socket in the provider volume path and watches
+// the path for socket create and remove events.
+//
+// This method blocks until the context is cancelled or the watch fails.
+func (w *PluginWatcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create provider volume watcher, err: %w", err)
+	}
+	defer watcher.Close()
+
+	// watch the path before the initial scan so no socket is missed
+	if err := watcher.Add(w.providerClients.socketPath); err != nil {
+		return fmt.Errorf("failed to watch provider volume %s, err: %w", w.providerClients.socketPath, err)
+	}
+	entries, err := os.ReadDir(w.providerClients.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to read provider volume %s, err: %w", w.providerClients.socketPath, err)
+	}
+	for _, entry := range entries {
+		if provider, ok := providerName(entry.Name()); ok {
+			w.register(ctx, provider)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.handleEvent(ctx, event)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			klog.ErrorS(err, "provider volume watch error", "path", w.providerClients.socketPath)
+		}
+	}
+}
+
+func (w *PluginWatcher) handleEvent(ctx context.Context, event fsnotify.Event) {
+	provider, ok := providerName(filepath.Base(event.Name))
+	if !ok {
+		return
+	}
+	switch {
+	case event.Op&fsnotify.Create == fsnotify.Create:
+		w.register(ctx, provider)
+	case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		klog.InfoS("provider socket removed", "provider", provider)
+		w.providerClients.Remove(provider)
+	}
+}
+
+// register connects to the provider, which validates the provider with its Version() RPC.
+// The existing connection to the provider is reset first, as the socket was created again
+// by a new provider process.
+func (w *PluginWatcher) register(ctx context.Context, provider string) {
+	w.providerClients.Reset(provider)
+
+	ctx, cancel := context.WithTimeout(ctx, registrationTimeout)
+	defer cancel()
+	if _, err := w.providerClients.Get(ctx, provider); err != nil {
+		// the provider is connected to again on the next mount
+		klog.ErrorS(err, "failed to register provider", "provider", provider)
+		return
+	}
+	klog.InfoS("registered provider", "provider", provider)
+}
+
+// providerName returns the provider name of the socket file name, and false if
+// the file isn't a provider socket
+func providerName(fileName string) (string, bool) {
+	if !strings.HasSuffix(fileName, ".sock") {
+		return "", false
+	}
+	provider := strings.TrimSuffix(fileName, ".sock")
+	if provider == "" || !PluginNameRe.MatchString(provider) {
+		return "", false
+	}
+	return provider, true
+}
+
+// RegisteredProvider is a provider in the registry of the PluginClientBuilder
+type RegisteredProvider struct {
+	Name string `json:"name"`
+	ProviderInfo
+}
+
+// Registry returns the providers the PluginClientBuilder is connected to, sorted by name
+func (p *PluginClientBuilder) Registry() []RegisteredProvider {
+	providers := p.Providers()
+	registry := make([]RegisteredProvider, 0, len(providers))
+	for _, provider := range providers {
+		if info, ok := p.Info(provider); ok {
+			registry = append(registry, RegisteredProvider{Name: provider, ProviderInfo: info})
+		}
+	}
+	return registry
+}
+
+// NewRegistryHandler returns a debug handler that serves the registry of the
+// PluginClientBuilder as JSON
+func NewRegistryHandler(providerClients *PluginClientBuilder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(providerClients.Registry()); err != nil {
+			klog.ErrorS(err, "failed to write provider registry")
+		}
+	})
+}
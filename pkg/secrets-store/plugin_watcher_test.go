@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/fake"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for i := 0; i < 50; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for the condition")
+}
+
+func TestPluginWatcher(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	pool := NewPluginClientBuilder(socketPath)
+	defer pool.Cleanup()
+
+	// the provider with a socket before the watcher starts is registered
+	server1, err := fake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider1.sock"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	server1.SetCapabilities([]string{v1alpha1.CapabilityUnmount})
+	if err := server1.Start(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	defer server1.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := NewPluginWatcher(pool).Run(ctx); err != nil {
+			t.Errorf("expected err to be nil, got: %+v", err)
+		}
+	}()
+	waitFor(t, func() bool { return cmp.Equal([]string{"provider1"}, pool.Providers()) })
+
+	// the provider is registered when its socket is created
+	server2, err := fake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider2.sock"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if err := server2.Start(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	waitFor(t, func() bool { return cmp.Equal([]string{"provider1", "provider2"}, pool.Providers()) })
+
+	rec := httptest.NewRecorder()
+	NewRegistryHandler(pool).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/providers", nil))
+	var registry []RegisteredProvider
+	if err := json.Unmarshal(rec.Body.Bytes(), &registry); err != nil {
+		t.Fatalf("failed to unmarshal the registry, err: %+v", err)
+	}
+	want := []RegisteredProvider{
+		{Name: "provider1", ProviderInfo: ProviderInfo{RuntimeName: "fakeprovider", RuntimeVersion: "0.0.10", Capabilities: []string{v1alpha1.CapabilityUnmount}}},
+		{Name: "provider2", ProviderInfo: ProviderInfo{RuntimeName: "fakeprovider", RuntimeVersion: "0.0.10"}},
+	}
+	if diff := cmp.Diff(want, registry); diff != "" {
+		t.Errorf("registry mismatch (-want +got):\n%s", diff)
+	}
+
+	// the provider is removed from the registry when its socket is removed
+	server2.Stop()
+	waitFor(t, func() bool { return cmp.Equal([]string{"provider1"}, pool.Providers()) })
+}
+
+func TestProviderName(t *testing.T) {
+	for fileName, want := range map[string]string{"provider1.sock": "provider1", "provider_1-a.sock": "provider_1-a"} {
+		if got, ok := providerName(fileName); !ok || got != want {
+			t.Errorf("providerName(%q) = %q, %v, want: %q", fileName, got, ok, want)
+		}
+	}
+	for _, fileName := range []string{".sock", "provider1", "provider.1.sock", "provider1.sock.tmp"} {
+		if got, ok := providerName(fileName); ok {
+			t.Errorf("expected %q to not be a provider socket, got: %q", fileName, got)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	mountMethod       = "/v1alpha1.CSIDriverProvider/Mount"
+	mountStreamMethod = "/v1alpha1.CSIDriverProvider/MountStream"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	// circuitHalfOpen lets a single trial call through
+	circuitHalfOpen
+)
+
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// callAbandoned is a call cancelled by the caller, which doesn't tell if the provider is up
+	callAbandoned
+)
+
+// callOutcomeOf returns the outcome of a call for the circuit breaker. Only the errors
+// that mean the provider is down or stuck are failures: the provider responded if the
+// call failed with any other status code.
+func callOutcomeOf(err error) callOutcome {
+	if err == nil {
+		return callSucceeded
+	}
+	if errors.Is(err, context.Canceled) {
+		return callAbandoned
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return callFailed
+	}
+	switch status.Code(err) {
+	case codes.Canceled:
+		return callAbandoned
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Unknown, codes.Internal:
+		return callFailed
+	default:
+		return callSucceeded
+	}
+}
+
+// ProviderCallStatus is the state of the calls to a provider with a provider config
+type ProviderCallStatus struct {
+	Name string `json:"name"`
+	// CircuitOpen is true if the calls to the provider fail fast
+	CircuitOpen bool `json:"circuitOpen"`
+	// InFlightMounts is the number of in-flight mount calls to the provider
+	InFlightMounts int `json:"inFlightMounts"`
+	// Timeouts is the number of calls that reached the call timeout
+	Timeouts int64 `json:"timeouts"`
+	// CircuitOpenRejections is the number of calls failed by the open circuit breaker
+	CircuitOpenRejections int64 `json:"circuitOpenRejections"`
+	// MountLimitRejections is the number of mount calls that timed out waiting for a mount slot
+	MountLimitRejections int64 `json:"mountLimitRejections"`
+}
+
+// providerCallPolicy applies the call configuration of a provider with client interceptors
+// on the connections to the provider. It's kept when the connection is reset, so the circuit
+// breaker state and the mount slots aren't lost.
+type providerCallPolicy struct {
+	provider string
+	config   ProviderCallConfig
+	// mounts has an entry for each in-flight mount call, nil if they're unlimited
+	mounts chan struct{}
+
+	mu                    sync.Mutex
+	state                 circuitState
+	failures              int
+	openedAt              time.Time
+	inFlightMounts        int
+	timeouts              int64
+	circuitOpenRejections int64
+	mountLimitRejections  int64
+}
+
+func newProviderCallPolicy(provider string, config ProviderCallConfig) *providerCallPolicy {
+	c := &providerCallPolicy{provider: provider, config: config}
+	if config.MaxConcurrentMounts > 0 {
+		c.mounts = make(chan struct{}, config.MaxConcurrentMounts)
+	}
+	return c
+}
+
+// dialOptions returns the interceptors that apply the policy to the calls on a connection
+func (c *providerCallPolicy) dialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithStreamInterceptor(c.streamInterceptor),
+	}
+}
+
+func (c *providerCallPolicy) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	trial, err := c.allow()
+	if err != nil {
+		return err
+	}
+	callCtx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if method == mountMethod {
+		release, err := c.acquireMount(callCtx)
+		if err != nil {
+			c.done(trial, callAbandoned)
+			return err
+		}
+		defer release()
+	}
+
+	err = invoker(callCtx, method, req, reply, cc, opts...)
+	c.recordTimeout(ctx, callCtx)
+	c.done(trial, callOutcomeOf(err))
+	return err
+}
+
+// streamInterceptor applies the policy to the MountStream() calls. The Watch() stream is
+// long-lived, so it has no deadline and isn't counted by the circuit breaker.
+func (c *providerCallPolicy) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if method != mountStreamMethod {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	trial, err := c.allow()
+	if err != nil {
+		return nil, err
+	}
+	callCtx, cancel := c.withTimeout(ctx)
+	release, err := c.acquireMount(callCtx)
+	if err != nil {
+		cancel()
+		c.done(trial, callAbandoned)
+		return nil, err
+	}
+	var once sync.Once
+	finish := func(err error) {
+		once.Do(func() {
+			c.recordTimeout(ctx, callCtx)
+			release()
+			cancel()
+			c.done(trial, callOutcomeOf(err))
+		})
+	}
+
+	stream, err := streamer(callCtx, desc, cc, method, opts...)
+	if err != nil {
+		finish(err)
+		return nil, err
+	}
+	// release the mount slot if the caller stops receiving before the end of the stream
+	go func() {
+		<-callCtx.Done()
+		finish(callCtx.Err())
+	}()
+	return &policyClientStream{ClientStream: stream, finish: finish}, nil
+}
+
+// policyClientStream finishes the call when the stream ends
+type policyClientStream struct {
+	grpc.ClientStream
+	finish func(error)
+}
+
+func (s *policyClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if errors.Is(err, io.EOF) {
+		s.finish(nil)
+	} else if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+func (c *providerCallPolicy) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.CallTimeout.Duration > 0 {
+		return context.WithTimeout(ctx, c.config.CallTimeout.Duration)
+	}
+	return context.WithCancel(ctx)
+}
+
+// recordTimeout counts the call if the call timeout was reached before the caller's context ended
+func (c *providerCallPolicy) recordTimeout(ctx, callCtx context.Context) {
+	if ctx.Err() != nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeouts++
+}
+
+// acquireMount waits for a mount slot and returns the function that releases it
+func (c *providerCallPolicy) acquireMount(ctx context.Context) (func(), error) {
+	if c.mounts != nil {
+		select {
+		case c.mounts <- struct{}{}:
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.mountLimitRejections++
+			c.mu.Unlock()
+			return nil, status.Errorf(codes.ResourceExhausted, "provider %q has %d in-flight mount requests, err: %v", c.provider, cap(c.mounts), ctx.Err())
+		}
+	}
+	c.mu.Lock()
+	c.inFlightMounts++
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		c.inFlightMounts--
+		c.mu.Unlock()
+		if c.mounts != nil {
+			<-c.mounts
+		}
+	}, nil
+}
+
+// allow returns an error wrapping ErrProviderCircuitOpen if the circuit breaker is open,
+// and true if the call is the trial call of the half-open circuit breaker
+func (c *providerCallPolicy) allow() (bool, error) {
+	if c.config.CircuitBreaker.FailureThreshold == 0 {
+		return false, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitClosed:
+		return false, nil
+	case circuitOpen:
+		if time.Since(c.openedAt) >= c.openDuration() {
+			c.state = circuitHalfOpen
+			return true, nil
+		}
+	}
+	c.circuitOpenRejections++
+	return false, fmt.Errorf("%w: provider %q failed %d consecutive calls", ErrProviderCircuitOpen, c.provider, c.failures)
+}
+
+// done records the outcome of an allowed call in the circuit breaker
+func (c *providerCallPolicy) done(trial bool, outcome callOutcome) {
+	if c.config.CircuitBreaker.FailureThreshold == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if trial {
+		switch outcome {
+		case callSucceeded:
+			klog.InfoS("provider circuit breaker closed", "provider", c.provider)
+			c.state = circuitClosed
+			c.failures = 0
+		case callFailed:
+			c.failures++
+			c.state = circuitOpen
+			c.openedAt = time.Now()
+		default:
+			// the next call is the trial call
+			c.state = circuitOpen
+		}
+		return
+	}
+	// the calls allowed before the circuit breaker opened don't change its state
+	if c.state != circuitClosed {
+		return
+	}
+	switch outcome {
+	case callSucceeded:
+		c.failures = 0
+	case callFailed:
+		c.failures++
+		if c.failures >= c.config.CircuitBreaker.FailureThreshold {
+			klog.ErrorS(nil, "provider circuit breaker opened", "provider", c.provider, "consecutiveFailures", c.failures, "openDuration", c.openDuration())
+			c.state = circuitOpen
+			c.openedAt = time.Now()
+		}
+	}
+}
+
+func (c *providerCallPolicy) openDuration() time.Duration {
+	if c.config.CircuitBreaker.OpenDuration.Duration > 0 {
+		return c.config.CircuitBreaker.OpenDuration.Duration
+	}
+	return defaultCircuitBreakerOpenDuration
+}
+
+func (c *providerCallPolicy) status() ProviderCallStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ProviderCallStatus{
+		Name:                  c.provider,
+		CircuitOpen:           c.state != circuitClosed,
+		InFlightMounts:        c.inFlightMounts,
+		Timeouts:              c.timeouts,
+		CircuitOpenRejections: c.circuitOpenRejections,
+		MountLimitRejections:  c.mountLimitRejections,
+	}
+}
+
+// SetProviderConfig sets the configuration of the calls to the providers. It must be
+// called before the first Get.
+func (p *PluginClientBuilder) SetProviderConfig(config *ProviderConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.providerConfig = config
+	p.callPolicies = make(map[string]*providerCallPolicy)
+	return nil
+}
+
+// callPolicyDialOptions returns the dial options that apply the provider config to the
+// connection to the provider, or nil if there's no provider config
+func (p *PluginClientBuilder) callPolicyDialOptions(provider string) []grpc.DialOption {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.providerConfig == nil {
+		return nil
+	}
+	policy, ok := p.callPolicies[provider]
+	if !ok {
+		policy = newProviderCallPolicy(provider, p.providerConfig.ForProvider(provider))
+		p.callPolicies[provider] = policy
+	}
+	return policy.dialOptions()
+}
+
+// CallStatus returns the state of the calls to the providers, sorted by name
+func (p *PluginClientBuilder) CallStatus() []ProviderCallStatus {
+	p.lock.RLock()
+	policies := make([]*providerCallPolicy, 0, len(p.callPolicies))
+	for _, policy := range p.callPolicies {
+		policies = append(policies, policy)
+	}
+	p.lock.RUnlock()
+
+	callStatus := make([]ProviderCallStatus, 0, len(policies))
+	for _, policy := range policies {
+		callStatus = append(callStatus, policy.status())
+	}
+	sort.Slice(callStatus, func(i, j int) bool { return callStatus[i].Name < callStatus[j].Name })
+	return callStatus
+}
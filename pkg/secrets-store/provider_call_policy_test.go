@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+)
+
+func TestCallOutcomeOf(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected callOutcome
+	}{
+		{err: nil, expected: callSucceeded},
+		{err: status.Error(codes.NotFound, "not found"), expected: callSucceeded},
+		{err: status.Error(codes.Unavailable, "unavailable"), expected: callFailed},
+		{err: status.Error(codes.DeadlineExceeded, "timeout"), expected: callFailed},
+		{err: context.DeadlineExceeded, expected: callFailed},
+		{err: status.Error(codes.Canceled, "canceled"), expected: callAbandoned},
+		{err: context.Canceled, expected: callAbandoned},
+	}
+	for _, test := range tests {
+		if got := callOutcomeOf(test.err); got != test.expected {
+			t.Errorf("callOutcomeOf(%v) = %d, expected %d", test.err, got, test.expected)
+		}
+	}
+}
+
+func TestProviderCallPolicy_CircuitBreaker(t *testing.T) {
+	c := newProviderCallPolicy("provider1", ProviderCallConfig{
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: metav1.Duration{Duration: 100 * time.Millisecond}},
+	})
+
+	// a successful call resets the consecutive failures
+	for _, outcome := range []callOutcome{callFailed, callSucceeded, callFailed} {
+		if _, err := c.allow(); err != nil {
+			t.Fatalf("expected err to be nil, got: %+v", err)
+		}
+		c.done(false, outcome)
+	}
+	if c.status().CircuitOpen {
+		t.Fatalf("expected the circuit breaker to be closed")
+	}
+
+	c.done(false, callFailed)
+	if _, err := c.allow(); !errors.Is(err, ErrProviderCircuitOpen) {
+		t.Fatalf("expected err to be ErrProviderCircuitOpen, got: %+v", err)
+	}
+
+	// a single trial call is let through after the open duration
+	time.Sleep(100 * time.Millisecond)
+	trial, err := c.allow()
+	if err != nil || !trial {
+		t.Fatalf("expected a trial call, got trial: %v, err: %+v", trial, err)
+	}
+	if _, err := c.allow(); !errors.Is(err, ErrProviderCircuitOpen) {
+		t.Fatalf("expected err to be ErrProviderCircuitOpen during the trial call, got: %+v", err)
+	}
+
+	// the failed trial call opens the circuit breaker again
+	c.done(true, callFailed)
+	if _, err := c.allow(); !errors.Is(err, ErrProviderCircuitOpen) {
+		t.Fatalf("expected err to be ErrProviderCircuitOpen, got: %+v", err)
+	}
+
+	// the successful trial call closes the circuit breaker
+	time.Sleep(100 * time.Millisecond)
+	if trial, err = c.allow(); err != nil || !trial {
+		t.Fatalf("expected a trial call, got trial: %v, err: %+v", trial, err)
+	}
+	c.done(true, callSucceeded)
+	if _, err := c.allow(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if s := c.status(); s.CircuitOpen || s.CircuitOpenRejections != 3 {
+		t.Errorf("expected the circuit breaker to be closed after 3 rejections, got: %+v", s)
+	}
+}
+
+func TestProviderCallPolicy_MaxConcurrentMounts(t *testing.T) {
+	c := newProviderCallPolicy("provider1", ProviderCallConfig{
+		CallTimeout:         metav1.Duration{Duration: 100 * time.Millisecond},
+		MaxConcurrentMounts: 1,
+	})
+
+	started, unblock := make(chan struct{}), make(chan struct{})
+	blocked := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		close(started)
+		<-unblock
+		return nil
+	}
+	done := make(chan error)
+	go func() {
+		done <- c.unaryInterceptor(context.Background(), mountMethod, nil, nil, nil, blocked)
+	}()
+	<-started
+
+	// the mount waits for a slot until the call timeout
+	invoked := false
+	err := c.unaryInterceptor(context.Background(), mountMethod, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	})
+	if status.Code(err) != codes.ResourceExhausted || invoked {
+		t.Fatalf("expected the mount to be rejected with ResourceExhausted, got invoked: %v, err: %+v", invoked, err)
+	}
+	// the other calls don't use a mount slot
+	if err := c.unaryInterceptor(context.Background(), "/v1alpha1.CSIDriverProvider/Version", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if s := c.status(); s.InFlightMounts != 1 || s.MountLimitRejections != 1 {
+		t.Errorf("expected 1 in-flight mount and 1 rejection, got: %+v", s)
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if s := c.status(); s.InFlightMounts != 0 {
+		t.Errorf("expected the mount slot to be released, got: %+v", s)
+	}
+}
+
+func TestProviderCallPolicy_MountStreamAbandoned(t *testing.T) {
+	c := newProviderCallPolicy("provider1", ProviderCallConfig{MaxConcurrentMounts: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, nil
+	}
+	if _, err := c.streamInterceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, mountStreamMethod, streamer); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if s := c.status(); s.InFlightMounts != 1 {
+		t.Fatalf("expected 1 in-flight mount, got: %+v", s)
+	}
+
+	// the mount slot is released when the caller stops receiving
+	cancel()
+	waitFor(t, func() bool { return c.status().InFlightMounts == 0 })
+}
+
+func TestPluginClientBuilder_ProviderConfig(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	targetPath := tmpdir.New(t, "", "ut")
+	pool := NewPluginClientBuilder(socketPath)
+	defer pool.Cleanup()
+	if err := pool.SetProviderConfig(&ProviderConfig{
+		Providers: map[string]ProviderCallConfig{
+			"provider1": {
+				CallTimeout:    metav1.Duration{Duration: 200 * time.Millisecond},
+				CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: metav1.Duration{Duration: time.Hour}},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	server, cleanup := fakeServer(t, socketPath, "provider1")
+	defer cleanup()
+	server.SetObjects(map[string]string{"foo": "v1"})
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	client, err := pool.Get(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	opts := pool.WriteOptions("provider1", WriteOptions{})
+	if _, _, err := MountContent(context.Background(), client, "{}", "{}", targetPath, "777", nil, opts); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	// the mounts to the stopped provider time out and open the circuit breaker
+	server.Stop()
+	for i := 0; i < 2; i++ {
+		_, _, err := MountContent(context.Background(), client, "{}", "{}", targetPath, "777", nil, opts)
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("expected the mount to time out, got: %+v", err)
+		}
+	}
+	_, errorReason, err := MountContent(context.Background(), client, "{}", "{}", targetPath, "777", nil, opts)
+	if !errors.Is(err, ErrProviderCircuitOpen) || errorReason != internalerrors.ProviderCircuitOpen {
+		t.Fatalf("expected the mount to fail fast with ProviderCircuitOpen, got reason: %s, err: %+v", errorReason, err)
+	}
+	if code := mountErrorCode(err); code != codes.Unavailable {
+		t.Errorf("expected mount error code Unavailable, got: %s", code)
+	}
+
+	expected := ProviderCallStatus{Name: "provider1", CircuitOpen: true, Timeouts: 2, CircuitOpenRejections: 1}
+	if s := pool.CallStatus(); len(s) != 1 || s[0] != expected {
+		t.Errorf("expected call status %+v, got: %+v", expected, s)
+	}
+
+	// the call policy is kept when the provider is registered again or removed
+	pool.Reset("provider1")
+	pool.Remove("provider1")
+	if s := pool.CallStatus(); len(s) != 1 || s[0] != expected {
+		t.Errorf("expected call status %+v after reset, got: %+v", expected, s)
+	}
+}
@@ -18,11 +18,15 @@ package secretsstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -31,10 +35,16 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/klog/v2"
 
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/templateutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/transformutil"
 	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
 )
 
@@ -62,21 +72,107 @@ const ServiceConfig = `
 }
 `
 
+// versionMaxRecvMsgSize is the maximum size in bytes of the Version() response
+const versionMaxRecvMsgSize = 1024 * 1024
+
+// defaultMaxMountStreamSize is the maximum total size in bytes of the files streamed
+// by a provider without a configured maximum mount size
+const defaultMaxMountStreamSize = 64 * 1024 * 1024
+
 var (
 	// PluginNameRe is the regular expression used to validate plugin names.
-	PluginNameRe        = regexp.MustCompile(`^[a-zA-Z0-9_-]{0,30}$`)
-	ErrInvalidProvider  = errors.New("invalid provider")
-	ErrProviderNotFound = errors.New("provider not found")
+	PluginNameRe                   = regexp.MustCompile(`^[a-zA-Z0-9_-]{0,30}$`)
+	ErrInvalidProvider             = errors.New("invalid provider")
+	ErrProviderNotFound            = errors.New("provider not found")
+	ErrIncompatibleProviderVersion = errors.New("incompatible provider version")
+	ErrProviderUnhealthy           = errors.New("provider is unhealthy")
+	ErrProviderCircuitOpen         = errors.New("provider circuit breaker is open")
 )
 
+// ProviderError is the error returned by the provider in the Error field of a response
+type ProviderError struct {
+	// Code is the error code returned by the provider, which is used as the error reason
+	Code string
+	// Message is the human-readable explanation of the error
+	Message string
+	// Retryable is true if the request can succeed when it's retried without changes
+	Retryable bool
+	// RetryAfter is the minimum duration to wait before retrying the request
+	RetryAfter time.Duration
+}
+
+// newProviderError returns the provider error in the response error, or nil if the provider
+// didn't return an error
+func newProviderError(e *v1alpha1.Error) *ProviderError {
+	if e == nil || (len(e.GetCode()) == 0 && len(e.GetMessage()) == 0) {
+		return nil
+	}
+	code := e.GetCode()
+	if len(code) == 0 {
+		code = internalerrors.ProviderError
+	}
+	return &ProviderError{
+		Code:       code,
+		Message:    e.GetMessage(),
+		Retryable:  e.GetRetryable(),
+		RetryAfter: time.Duration(e.GetRetryAfterSeconds()) * time.Second,
+	}
+}
+
+func (e *ProviderError) Error() string {
+	if len(e.Message) == 0 {
+		return fmt.Sprintf("provider error code %s", e.Code)
+	}
+	return fmt.Sprintf("provider error code %s: %s", e.Code, e.Message)
+}
+
+// GRPCCode returns the gRPC status code for the provider error returned to kubelet.
+// Retryable errors are Unavailable and the other errors are FailedPrecondition, as
+// they can't succeed until the secret provider class or the secrets store changes.
+func (e *ProviderError) GRPCCode() codes.Code {
+	if e.Retryable {
+		return codes.Unavailable
+	}
+	return codes.FailedPrecondition
+}
+
+// ProviderInfo is the runtime name, runtime version and capabilities returned
+// by the provider's Version() RPC when the driver connects to the provider.
+type ProviderInfo struct {
+	RuntimeName    string   `json:"runtimeName"`
+	RuntimeVersion string   `json:"runtimeVersion"`
+	Capabilities   []string `json:"capabilities,omitempty"`
+}
+
+// HasCapability returns true if the provider declared the capability
+func (i ProviderInfo) HasCapability(capability string) bool {
+	for _, c := range i.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // PluginClientBuilder builds and stores grpc clients for communicating with
 // provider plugins.
 type PluginClientBuilder struct {
-	clients    map[string]v1alpha1.CSIDriverProviderClient
-	conns      map[string]*grpc.ClientConn
-	socketPath string
-	lock       sync.RWMutex
-	opts       []grpc.DialOption
+	clients       map[string]v1alpha1.CSIDriverProviderClient
+	conns         map[string]*grpc.ClientConn
+	infos         map[string]ProviderInfo
+	minVersions   map[string]*version.Version
+	maxMountSizes map[string]int64
+	// health contains the health check state of the providers
+	health                      map[string]*ProviderHealth
+	healthCheckFailureThreshold int
+	socketPath                  string
+	lock                        sync.RWMutex
+	opts                        []grpc.DialOption
+	// providerConfig configures the calls to the providers, which are limited by the call policies
+	providerConfig *ProviderConfig
+	callPolicies   map[string]*providerCallPolicy
+	// mounts coalesces the concurrent identical mount requests of the write options
+	mounts mountGroup
 }
 
 // NewPluginClientBuilder creates a PluginClientBuilder that will connect to
@@ -93,6 +189,8 @@ func NewPluginClientBuilder(path string, opts ...grpc.DialOption) *PluginClientB
 	return &PluginClientBuilder{
 		clients:    make(map[string]v1alpha1.CSIDriverProviderClient),
 		conns:      make(map[string]*grpc.ClientConn),
+		infos:      make(map[string]ProviderInfo),
+		health:     make(map[string]*ProviderHealth),
 		socketPath: path,
 		lock:       sync.RWMutex{},
 		opts: append(opts, []grpc.DialOption{
@@ -106,8 +204,49 @@ func NewPluginClientBuilder(path string, opts ...grpc.DialOption) *PluginClientB
 	}
 }
 
+// SetMinimumVersions sets the minimum runtime version of the providers, keyed by
+// provider name. Get refuses to connect to a provider with a lower version. It
+// must be called before the first Get.
+func (p *PluginClientBuilder) SetMinimumVersions(minVersions map[string]string) error {
+	parsed := make(map[string]*version.Version, len(minVersions))
+	for provider, v := range minVersions {
+		minVersion, err := version.ParseGeneric(v)
+		if err != nil {
+			return fmt.Errorf("invalid minimum version %q for provider %q, err: %w", v, provider, err)
+		}
+		parsed[provider] = minVersion
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.minVersions = parsed
+	return nil
+}
+
+// SetMaxMountSizes sets the maximum size of the mount responses of the providers, keyed
+// by provider name. The sizes are quantities, e.g. 16Mi. It must be called before the
+// first Get.
+func (p *PluginClientBuilder) SetMaxMountSizes(maxMountSizes map[string]string) error {
+	parsed := make(map[string]int64, len(maxMountSizes))
+	for provider, s := range maxMountSizes {
+		size, err := resource.ParseQuantity(s)
+		if err != nil {
+			return fmt.Errorf("invalid maximum mount size %q for provider %q, err: %w", s, provider, err)
+		}
+		if size.Sign() <= 0 || size.Value() > math.MaxInt32 {
+			return fmt.Errorf("invalid maximum mount size %q for provider %q, must be between 1 and %d bytes", s, provider, math.MaxInt32)
+		}
+		parsed[provider] = size.Value()
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.maxMountSizes = parsed
+	return nil
+}
+
 // Get returns a CSIDriverProviderClient for the provider. If an existing client
 // is not found a new one will be created and added to the PluginClientBuilder.
+// The provider's Version() RPC is called when a new client is created and the
+// provider is refused if its version is below the configured minimum version.
 func (p *PluginClientBuilder) Get(ctx context.Context, provider string) (v1alpha1.CSIDriverProviderClient, error) {
 	var out v1alpha1.CSIDriverProviderClient
 
@@ -128,15 +267,24 @@ func (p *PluginClientBuilder) Get(ctx context.Context, provider string) (v1alpha
 		return nil, fmt.Errorf("%w: provider %q", ErrProviderNotFound, provider)
 	}
 
+	opts := append(p.opts[:len(p.opts):len(p.opts)], p.callPolicyDialOptions(provider)...)
 	conn, err := grpc.Dial(
 		fmt.Sprintf("%s/%s.sock", p.socketPath, provider),
-		p.opts...,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
 	}
 	out = v1alpha1.NewCSIDriverProviderClient(conn)
 
+	info, err := p.negotiate(ctx, provider, out)
+	if err != nil {
+		if cerr := conn.Close(); cerr != nil {
+			klog.ErrorS(cerr, "error shutting down provider connection", "provider", provider)
+		}
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -144,14 +292,108 @@ func (p *PluginClientBuilder) Get(ctx context.Context, provider string) (v1alpha
 	// and added a connection to the map before p.lock.Lock() was acquired.
 	if r, ok := p.clients[provider]; ok {
 		out = r
+		if err := conn.Close(); err != nil {
+			klog.ErrorS(err, "error shutting down provider connection", "provider", provider)
+		}
 	} else {
 		p.conns[provider] = conn
 		p.clients[provider] = out
+		p.infos[provider] = info
+		klog.InfoS("connected to provider", "provider", provider, "runtimeName", info.RuntimeName, "runtimeVersion", info.RuntimeVersion, "capabilities", info.Capabilities)
 	}
 
 	return out, nil
 }
 
+// Info returns the provider info cached when the client for the provider was
+// created, and false if there's no client for the provider.
+func (p *PluginClientBuilder) Info(provider string) (ProviderInfo, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	info, ok := p.infos[provider]
+	return info, ok
+}
+
+// Providers returns the names of the providers with a client, sorted by name
+func (p *PluginClientBuilder) Providers() []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	providers := make([]string, 0, len(p.clients))
+	for provider := range p.clients {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// HasCapability returns true if the provider declared the capability when the
+// client for the provider was created. Optional protocol features must only be
+// used if the provider has the capability.
+func (p *PluginClientBuilder) HasCapability(provider, capability string) bool {
+	info, ok := p.Info(provider)
+	return ok && info.HasCapability(capability)
+}
+
+// WriteOptions returns the write options with the options specific to the provider: the
+// files are streamed if the provider has the MountStream capability, and are limited to
+// the maximum mount size configured for the provider. The concurrent identical mount
+// requests to the provider are coalesced if the provider has the MountCoalescing capability
+// and not the Unmount capability, unless it's disabled in the provider config.
+func (p *PluginClientBuilder) WriteOptions(provider string, opts WriteOptions) WriteOptions {
+	opts.Stream = p.HasCapability(provider, v1alpha1.CapabilityMountStream)
+	// the providers that can unmount issue credentials for each pod, which can't be shared
+	coalesce := p.HasCapability(provider, v1alpha1.CapabilityMountCoalescing) && !p.HasCapability(provider, v1alpha1.CapabilityUnmount)
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	opts.MaxSize = p.maxMountSizes[provider]
+	if coalesce && (p.providerConfig == nil || !p.providerConfig.ForProvider(provider).DisableMountCoalescing) {
+		opts.provider = provider
+		opts.mounts = &p.mounts
+	}
+	return opts
+}
+
+// negotiate calls the provider's Version() RPC and returns the provider info if the
+// provider's runtime version isn't below the configured minimum version.
+func (p *PluginClientBuilder) negotiate(ctx context.Context, provider string, client v1alpha1.CSIDriverProviderClient) (ProviderInfo, error) {
+	// the configured max receive message size limits the mount responses, the
+	// version response is always small
+	resp, err := client.Version(ctx, &v1alpha1.VersionRequest{Version: "v1alpha1"}, grpc.MaxCallRecvMsgSize(versionMaxRecvMsgSize))
+	if err != nil {
+		return ProviderInfo{}, fmt.Errorf("failed to get version of provider %q, err: %w", provider, err)
+	}
+	info := ProviderInfo{
+		RuntimeName:    resp.GetRuntimeName(),
+		RuntimeVersion: resp.GetRuntimeVersion(),
+		Capabilities:   resp.GetCapabilities(),
+	}
+
+	p.lock.RLock()
+	minVersion, ok := p.minVersions[provider]
+	p.lock.RUnlock()
+	if ok {
+		runtimeVersion, err := version.ParseGeneric(info.RuntimeVersion)
+		if err != nil {
+			return ProviderInfo{}, fmt.Errorf("%w: provider %q runtime version %q is invalid, err: %v", ErrIncompatibleProviderVersion, provider, info.RuntimeVersion, err)
+		}
+		if runtimeVersion.LessThan(minVersion) {
+			return ProviderInfo{}, fmt.Errorf("%w: provider %q runtime version %s is below the minimum version %s", ErrIncompatibleProviderVersion, provider, info.RuntimeVersion, minVersion)
+		}
+	}
+	return info, nil
+}
+
+// LookupErrorReason returns the error reason for an error returned by Get
+func LookupErrorReason(err error) string {
+	if errors.Is(err, ErrIncompatibleProviderVersion) {
+		return internalerrors.IncompatibleProviderVersion
+	}
+	return internalerrors.FailedToLookupProviderGRPCClient
+}
+
 // Cleanup closes all underlying connections and removes all clients.
 func (p *PluginClientBuilder) Cleanup() {
 	p.lock.Lock()
@@ -164,10 +406,53 @@ func (p *PluginClientBuilder) Cleanup() {
 	}
 	p.clients = make(map[string]v1alpha1.CSIDriverProviderClient)
 	p.conns = make(map[string]*grpc.ClientConn)
+	p.infos = make(map[string]ProviderInfo)
+	p.health = make(map[string]*ProviderHealth)
+}
+
+// Reset closes the connection to the provider and removes its client, so the next Get
+// connects to the provider again, e.g. when a new provider process created the socket.
+// The call policy and health check state are kept, so a provider that restarts doesn't
+// reset its circuit breaker, mount slots and consecutive health check failures.
+func (p *PluginClientBuilder) Reset(provider string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.mounts.forget(provider)
+	p.closeConnection(provider)
+}
+
+// Remove resets the connection to a provider that's gone, e.g. when its socket is removed,
+// and removes its health check state so it's no longer health checked. The call policy is
+// kept, as it applies to the provider when it's registered again.
+func (p *PluginClientBuilder) Remove(provider string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.health, provider)
+	p.mounts.forget(provider)
+	p.closeConnection(provider)
+}
+
+// closeConnection closes the connection to the provider and removes its client.
+// The caller must hold the lock.
+func (p *PluginClientBuilder) closeConnection(provider string) {
+	conn, ok := p.conns[provider]
+	if !ok {
+		return
+	}
+	if err := conn.Close(); err != nil {
+		klog.ErrorS(err, "error shutting down provider connection", "provider", provider)
+	}
+	delete(p.clients, provider)
+	delete(p.conns, provider)
+	delete(p.infos, provider)
+	klog.InfoS("disconnected from provider", "provider", provider)
 }
 
 // HealthCheck enables periodic healthcheck for configured provider clients by making
-// a Version() RPC call. If the provider healthcheck fails, we log an error.
+// a Version() RPC call. A provider is unhealthy after the failure threshold of consecutive
+// failed health checks, and its connection is reset until a health check succeeds.
 //
 // This method blocks until the parent context is cancelled during termination.
 func (p *PluginClientBuilder) HealthCheck(ctx context.Context, interval time.Duration) {
@@ -179,28 +464,51 @@ func (p *PluginClientBuilder) HealthCheck(ctx context.Context, interval time.Dur
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			p.lock.RLock()
-
-			for provider, client := range p.clients {
-				c, cancel := context.WithTimeout(ctx, 5*time.Second)
-				defer cancel()
-
-				runtimeVersion, err := Version(c, client)
-				if err != nil {
-					klog.V(4).ErrorS(err, "provider healthcheck failed", "provider", provider)
-					continue
-				}
-				klog.V(4).InfoS("provider healthcheck successful", "provider", provider, "runtimeVersion", runtimeVersion)
+			for _, provider := range p.healthCheckProviders() {
+				p.checkHealth(ctx, provider)
 			}
-
-			p.lock.RUnlock()
 		}
 	}
 }
 
-// MountContent calls the client's Mount() RPC with helpers to format the
-// request and interpret the response.
-func MountContent(ctx context.Context, client v1alpha1.CSIDriverProviderClient, attributes, secrets, targetPath, permission string, oldObjectVersions map[string]string) (map[string]string, string, error) {
+// WriteOptions configure how the driver writes the files returned by the provider to the mount
+type WriteOptions struct {
+	// FileTransforms are applied to the files before they are written
+	FileTransforms []*secretsstorev1.FileTransform
+	// Templates are rendered with the files and written with them
+	Templates []*secretsstorev1.SecretTemplate
+	// FSGroup owns the written files if set
+	FSGroup *int64
+	// Stream fetches the files with the MountStream() RPC. It must only be set
+	// for providers with the MountStream capability.
+	Stream bool
+	// MaxSize is the maximum size in bytes of the mount response. If it's not set, the
+	// unary mount response is limited by the max receive message size of the client,
+	// and the streamed files are limited to 64MiB.
+	MaxSize int64
+
+	// provider is the name of the provider the concurrent identical mount requests are
+	// coalesced for with mounts
+	provider string
+	mounts   *mountGroup
+}
+
+// NewWriteOptions returns the write options defined in the secret provider class spec
+func NewWriteOptions(spec secretsstorev1.SecretProviderClassSpec, fsGroup *int64) WriteOptions {
+	return WriteOptions{
+		FileTransforms: spec.FileTransforms,
+		Templates:      spec.Templates,
+		FSGroup:        fsGroup,
+	}
+}
+
+// MountContent calls the client's Mount() RPC, or MountStream() RPC if streaming
+// is enabled in the write options, with helpers to format the request and interpret
+// the response. The files returned by the provider are written to the target path
+// with the write options, and the objects returned by the provider are returned
+// with their versions and refresh times. The concurrent identical requests with write
+// options from PluginClientBuilder.WriteOptions are coalesced into a single call.
+func MountContent(ctx context.Context, client v1alpha1.CSIDriverProviderClient, attributes, secrets, targetPath, permission string, oldObjectVersions map[string]string, opts WriteOptions) ([]secretsstorev1.SecretProviderClassObject, string, error) {
 	var objVersions []*v1alpha1.ObjectVersion
 	for obj, version := range oldObjectVersions {
 		objVersions = append(objVersions, &v1alpha1.ObjectVersion{Id: obj, Version: version})
@@ -214,25 +522,19 @@ func MountContent(ctx context.Context, client v1alpha1.CSIDriverProviderClient,
 		CurrentObjectVersion: objVersions,
 	}
 
-	resp, err := client.Mount(ctx, req)
+	resp, errorReason, err := coalesceMount(ctx, client, req, opts)
 	if err != nil {
-		if isMaxRecvMsgSizeError(err) {
-			klog.ErrorS(err, "Set --max-call-recv-msg-size to configure larger maximum size in bytes of gRPC response")
-		}
-		return nil, internalerrors.GRPCProviderError, err
+		return nil, errorReason, err
 	}
-	if resp != nil && resp.GetError() != nil && len(resp.GetError().Code) > 0 {
-		return nil, resp.GetError().Code, fmt.Errorf("mount request failed with provider error code %s", resp.GetError().Code)
+	if providerErr := newProviderError(resp.GetError()); providerErr != nil {
+		return nil, providerErr.Code, fmt.Errorf("mount request failed with %w", providerErr)
 	}
 
 	ov := resp.GetObjectVersion()
 	if ov == nil {
 		return nil, internalerrors.GRPCProviderError, errors.New("missing object versions")
 	}
-	objectVersions := make(map[string]string)
-	for _, v := range ov {
-		objectVersions[v.Id] = v.Version
-	}
+	mountedObjects := newSecretProviderClassObjects(ov, time.Now())
 
 	// warn if the proto response size is over 1 MiB.
 	// Individual k8s secrets are limited to 1MiB in size.
@@ -246,16 +548,255 @@ func MountContent(ctx context.Context, client v1alpha1.CSIDriverProviderClient,
 		if err := fileutil.Validate(resp.GetFiles()); err != nil {
 			return nil, internalerrors.FileWriteError, err
 		}
-		if err := fileutil.WritePayloads(targetPath, resp.GetFiles()); err != nil {
+		files, err := transformFiles(resp.GetFiles(), opts.FileTransforms)
+		if err != nil {
+			return nil, internalerrors.FileTransformError, err
+		}
+		objects := make(map[string]string, len(files))
+		for _, f := range files {
+			objects[f.GetPath()] = string(f.GetContents())
+		}
+		rendered, err := renderTemplateFiles(opts.Templates, objects, permission)
+		if err != nil {
+			return nil, internalerrors.TemplateRenderError, err
+		}
+		// the files may be shared with coalesced mount requests, so they're copied before the
+		// rendered files are appended
+		if err := fileutil.WritePayloads(targetPath, append(files[:len(files):len(files)], rendered...), opts.FSGroup); err != nil {
 			return nil, internalerrors.FileWriteError, err
 		}
 	} else {
 		// when no files are returned we assume that the plugin has not migrated
 		// grpc responses for writing files yet.
 		klog.V(5).Infof("mount response has no files")
+		if len(opts.FileTransforms) > 0 {
+			klog.InfoS("file transforms are not applied as the provider writes the files to the mount")
+		}
+		// render the templates with the files the provider has written to the mount
+		if len(opts.Templates) > 0 {
+			mountedFiles, err := fileutil.GetMountedFiles(targetPath)
+			if err != nil {
+				return nil, internalerrors.FileWriteError, err
+			}
+			objects, err := templateutil.ReadObjects(mountedFiles, opts.Templates)
+			if err != nil {
+				return nil, internalerrors.FileWriteError, err
+			}
+			rendered, err := renderTemplateFiles(opts.Templates, objects, permission)
+			if err != nil {
+				return nil, internalerrors.TemplateRenderError, err
+			}
+			if len(rendered) > 0 {
+				if err := fileutil.WritePayloads(targetPath, rendered, opts.FSGroup); err != nil {
+					return nil, internalerrors.FileWriteError, err
+				}
+			}
+		}
+	}
+
+	return mountedObjects, "", nil
+}
+
+// callMount calls the client's Mount() RPC, or MountStream() RPC if streaming is enabled
+// in the write options, and returns the response
+func callMount(ctx context.Context, client v1alpha1.CSIDriverProviderClient, req *v1alpha1.MountRequest, opts WriteOptions) (*v1alpha1.MountResponse, string, error) {
+	if opts.Stream {
+		return receiveMountStream(ctx, client, req, opts.MaxSize)
+	}
+	var callOpts []grpc.CallOption
+	if opts.MaxSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(int(opts.MaxSize)))
+	}
+	resp, err := client.Mount(ctx, req, callOpts...)
+	if err != nil {
+		if isMaxRecvMsgSizeError(err) {
+			klog.ErrorS(err, "Set --max-call-recv-msg-size or --provider-max-mount-sizes to configure larger maximum size in bytes of gRPC response")
+		}
+		return nil, callErrorReason(err), err
+	}
+	return resp, "", nil
+}
+
+// receiveMountStream calls the client's MountStream() RPC and assembles the streamed
+// file chunks into a mount response. The chunks of a file are appended to the file
+// contents in the order they're received. An error is returned if the total size of
+// the files exceeds maxSize, or 64MiB if maxSize isn't set.
+func receiveMountStream(ctx context.Context, client v1alpha1.CSIDriverProviderClient, req *v1alpha1.MountRequest, maxSize int64) (*v1alpha1.MountResponse, string, error) {
+	if maxSize <= 0 {
+		maxSize = defaultMaxMountStreamSize
+	}
+	// cancel the stream if the response is refused before the provider is done
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.MountStream(ctx, req)
+	if err != nil {
+		return nil, callErrorReason(err), err
+	}
+
+	resp := &v1alpha1.MountResponse{}
+	files := make(map[string]*v1alpha1.File)
+	var size int64
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, internalerrors.GRPCProviderError, err
+		}
+		resp.ObjectVersion = append(resp.ObjectVersion, msg.GetObjectVersion()...)
+		if msg.GetError() != nil {
+			resp.Error = msg.GetError()
+		}
+		chunk := msg.GetChunk()
+		if chunk == nil {
+			continue
+		}
+		if size += int64(len(chunk.GetContents())); size > maxSize {
+			return nil, internalerrors.MountResponseTooLarge, fmt.Errorf("mount stream files exceed the maximum size of %d bytes", maxSize)
+		}
+		file, ok := files[chunk.GetPath()]
+		if !ok {
+			file = &v1alpha1.File{Path: chunk.GetPath(), Mode: chunk.GetMode()}
+			files[chunk.GetPath()] = file
+			resp.Files = append(resp.Files, file)
+		}
+		file.Contents = append(file.Contents, chunk.GetContents()...)
+	}
+	return resp, "", nil
+}
+
+// newSecretProviderClassObjects returns the objects in the mount response. The refresh time of
+// the objects that only have an expiry is two thirds of the way from now to the expiry, so the
+// objects are fetched again before they expire. If the provider returns an object more than
+// once, the last one is used.
+func newSecretProviderClassObjects(ov []*v1alpha1.ObjectVersion, now time.Time) []secretsstorev1.SecretProviderClassObject {
+	objects := make([]secretsstorev1.SecretProviderClassObject, 0, len(ov))
+	index := make(map[string]int, len(ov))
+	for _, v := range ov {
+		obj := secretsstorev1.SecretProviderClassObject{ID: v.GetId(), Version: v.GetVersion()}
+		if v.GetExpiresAt() > 0 {
+			expiresAt := metav1.Unix(v.GetExpiresAt(), 0)
+			obj.ExpiresAt = &expiresAt
+		}
+		if v.GetRefreshAfter() > 0 {
+			refreshAfter := metav1.Unix(v.GetRefreshAfter(), 0)
+			obj.RefreshAfter = &refreshAfter
+		} else if obj.ExpiresAt != nil {
+			refreshAfter := metav1.NewTime(now.Add(obj.ExpiresAt.Sub(now) * 2 / 3).Truncate(time.Second))
+			obj.RefreshAfter = &refreshAfter
+		}
+		if i, ok := index[obj.ID]; ok {
+			objects[i] = obj
+			continue
+		}
+		index[obj.ID] = len(objects)
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+// ObjectVersions returns the versions of the objects keyed by object ID
+func ObjectVersions(objects []secretsstorev1.SecretProviderClassObject) map[string]string {
+	objectVersions := make(map[string]string, len(objects))
+	for _, obj := range objects {
+		objectVersions[obj.ID] = obj.Version
+	}
+	return objectVersions
+}
+
+// NextRefreshTime returns the earliest refresh time of the objects, or nil if
+// none of the objects has a refresh time.
+func NextRefreshTime(objects []secretsstorev1.SecretProviderClassObject) *time.Time {
+	var next *time.Time
+	for _, obj := range objects {
+		if obj.RefreshAfter == nil {
+			continue
+		}
+		if t := obj.RefreshAfter.Time; next == nil || t.Before(*next) {
+			next = &t
+		}
+	}
+	return next
+}
+
+// UnmountContent calls the client's Unmount() RPC with the pod and the objects
+// currently mounted for the pod in the secret provider class pod status.
+func UnmountContent(ctx context.Context, client v1alpha1.CSIDriverProviderClient, spcps *secretsstorev1.SecretProviderClassPodStatus, podUID string) error {
+	var objVersions []*v1alpha1.ObjectVersion
+	for _, obj := range spcps.Status.Objects {
+		objVersions = append(objVersions, &v1alpha1.ObjectVersion{Id: obj.ID, Version: obj.Version})
+	}
+
+	req := &v1alpha1.UnmountRequest{
+		TargetPath:           spcps.Status.TargetPath,
+		PodName:              spcps.Status.PodName,
+		PodNamespace:         spcps.Namespace,
+		PodUid:               podUID,
+		SecretProviderClass:  spcps.Status.SecretProviderClassName,
+		CurrentObjectVersion: objVersions,
 	}
 
-	return objectVersions, "", nil
+	resp, err := client.Unmount(ctx, req)
+	if err != nil {
+		return err
+	}
+	if providerErr := newProviderError(resp.GetError()); providerErr != nil {
+		return fmt.Errorf("unmount request failed with %w", providerErr)
+	}
+	return nil
+}
+
+// transformFiles applies the file transforms to the matching files and returns the transformed files
+func transformFiles(files []*v1alpha1.File, fileTransforms []*secretsstorev1.FileTransform) ([]*v1alpha1.File, error) {
+	if len(fileTransforms) == 0 {
+		return files, nil
+	}
+	transforms := make(map[string][]secretsstorev1.DataTransform, len(fileTransforms))
+	for _, ft := range fileTransforms {
+		if ft != nil {
+			transforms[ft.Path] = append(transforms[ft.Path], ft.Transforms...)
+		}
+	}
+	transformed := make([]*v1alpha1.File, 0, len(files))
+	for _, f := range files {
+		t, ok := transforms[f.GetPath()]
+		if !ok {
+			transformed = append(transformed, f)
+			continue
+		}
+		contents, err := transformutil.Apply(f.GetContents(), t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform file %s, err: %w", f.GetPath(), err)
+		}
+		transformed = append(transformed, &v1alpha1.File{Path: f.GetPath(), Mode: f.GetMode(), Contents: contents})
+	}
+	return transformed, nil
+}
+
+// renderTemplateFiles renders the templates that have a path with the objects and returns
+// the files to write to the mount. The files have the permission of the mount.
+func renderTemplateFiles(templates []*secretsstorev1.SecretTemplate, objects map[string]string, permission string) ([]*v1alpha1.File, error) {
+	var mode os.FileMode = 0644
+	if err := json.Unmarshal([]byte(permission), &mode); err != nil {
+		klog.V(5).InfoS("failed to parse file permission, using default", "permission", permission, "mode", mode)
+	}
+	var files []*v1alpha1.File
+	for _, t := range templates {
+		if t == nil || len(t.Path) == 0 {
+			continue
+		}
+		contents, err := templateutil.Render(t, objects)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &v1alpha1.File{Path: t.Path, Mode: int32(mode), Contents: contents})
+	}
+	if err := fileutil.Validate(files); err != nil {
+		return nil, err
+	}
+	return files, nil
 }
 
 // Version calls the client's Version() RPC
@@ -272,6 +813,14 @@ func Version(ctx context.Context, client v1alpha1.CSIDriverProviderClient) (stri
 	return resp.RuntimeVersion, nil
 }
 
+// callErrorReason returns the error reason of a failed call to the provider
+func callErrorReason(err error) string {
+	if errors.Is(err, ErrProviderCircuitOpen) {
+		return internalerrors.ProviderCircuitOpen
+	}
+	return internalerrors.GRPCProviderError
+}
+
 // isMaxRecvMsgSizeError checks if the grpc error is of ResourceExhausted type and
 // msg size is larger than max configured.
 func isMaxRecvMsgSizeError(err error) bool {
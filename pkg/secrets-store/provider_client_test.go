@@ -21,8 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -31,7 +33,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
 	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
 	"sigs.k8s.io/secrets-store-csi-driver/provider/fake"
@@ -194,11 +199,11 @@ func TestMountContent(t *testing.T) {
 				t.Fatalf("expected err to be nil, got: %+v", err)
 			}
 
-			objectVersions, _, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, test.permission, nil)
+			objects, _, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, test.permission, nil, WriteOptions{})
 			if err != nil {
 				t.Errorf("expected err to be nil, got: %+v", err)
 			}
-			if test.objectVersions != nil && !reflect.DeepEqual(test.objectVersions, objectVersions) {
+			if objectVersions := ObjectVersions(objects); test.objectVersions != nil && !reflect.DeepEqual(test.objectVersions, objectVersions) {
 				t.Errorf("expected object versions: %v, got: %+v", test.objectVersions, objectVersions)
 			}
 
@@ -250,7 +255,7 @@ func TestMountContent_TooLarge(t *testing.T) {
 	}
 
 	// rpc error: code = ResourceExhausted desc = grpc: received message larger than max (28 vs. 5)
-	_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil)
+	_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, WriteOptions{})
 	if err == nil {
 		t.Errorf("expected err to be not nil")
 	}
@@ -262,6 +267,180 @@ func TestMountContent_TooLarge(t *testing.T) {
 	}
 }
 
+func TestMountContent_Stream(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	targetPath := tmpdir.New(t, "", "ut")
+
+	// the file is larger than the max message size
+	pool := NewPluginClientBuilder(socketPath, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024)))
+	defer pool.Cleanup()
+
+	server, cleanup := fakeServer(t, socketPath, "provider1")
+	defer cleanup()
+
+	contents := []byte(strings.Repeat("a", 10*1024))
+	server.SetObjects(map[string]string{"foo": "v1"})
+	server.SetFiles([]*v1alpha1.File{{Path: "foo", Mode: 0644, Contents: contents}})
+	server.SetCapabilities([]string{v1alpha1.CapabilityMountStream})
+	server.SetChunkSize(512)
+	server.Start()
+
+	client, err := pool.Get(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	opts := pool.WriteOptions("provider1", WriteOptions{})
+	if !opts.Stream {
+		t.Fatalf("expected the files to be streamed for a provider with the MountStream capability")
+	}
+
+	objects, _, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, opts)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"foo": "v1"}, ObjectVersions(objects)); diff != "" {
+		t.Errorf("object versions mismatch (-want +got):\n%s", diff)
+	}
+	got, err := os.ReadFile(filepath.Join(targetPath, "foo"))
+	if err != nil {
+		t.Fatalf("failed to read the streamed file, err: %+v", err)
+	}
+	if !reflect.DeepEqual(contents, got) {
+		t.Errorf("expected the streamed file to have %d bytes, got: %d", len(contents), len(got))
+	}
+
+	// the streamed files exceed the maximum mount size of the provider
+	if err := pool.SetMaxMountSizes(map[string]string{"provider1": "4Ki"}); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, pool.WriteOptions("provider1", WriteOptions{}))
+	if err == nil {
+		t.Errorf("expected err to be not nil")
+	}
+	if want := internalerrors.MountResponseTooLarge; errorCode != want {
+		t.Errorf("expected error code: %v, got: %+v", want, errorCode)
+	}
+
+	// the maximum mount size of the provider overrides the max message size for the unary mount
+	if _, _, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, WriteOptions{MaxSize: 64 * 1024}); err != nil {
+		t.Errorf("expected err to be nil, got: %+v", err)
+	}
+}
+
+func TestPluginClientBuilder_SetMaxMountSizesInvalid(t *testing.T) {
+	pool := NewPluginClientBuilder(tmpdir.New(t, "", "ut"))
+	defer pool.Cleanup()
+
+	for _, size := range []string{"large", "0", "-1Mi", "4Gi"} {
+		if err := pool.SetMaxMountSizes(map[string]string{"provider1": size}); err == nil {
+			t.Errorf("expected error for maximum mount size %q", size)
+		}
+	}
+}
+
+func TestMountContent_FileTransforms(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	targetPath := tmpdir.New(t, "", "ut")
+
+	pool := NewPluginClientBuilder(socketPath)
+	defer pool.Cleanup()
+
+	server, cleanup := fakeServer(t, socketPath, "provider1")
+	defer cleanup()
+
+	server.SetObjects(map[string]string{"foo": "v1", "bar": "v1"})
+	server.SetFiles([]*v1alpha1.File{
+		{Path: "foo", Mode: 0644, Contents: []byte(`{"password": "pass"}`)},
+		{Path: "bar", Mode: 0644, Contents: []byte("YmFy")},
+	})
+	server.Start()
+
+	client, err := pool.Get(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	fileTransforms := []*secretsstorev1.FileTransform{
+		{Path: "foo", Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformJSONPath, JSONPath: "{.password}"}}},
+	}
+	if _, _, err = MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, WriteOptions{FileTransforms: fileTransforms}); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	for path, expected := range map[string]string{"foo": "pass", "bar": "YmFy"} {
+		got, err := os.ReadFile(filepath.Join(targetPath, path))
+		if err != nil {
+			t.Fatalf("unable to read mounted file: %s", err)
+		}
+		if string(got) != expected {
+			t.Errorf("expected file %s to be %q, got %q", path, expected, string(got))
+		}
+	}
+
+	// transform errors are returned with the file transform error code
+	fileTransforms[0].Transforms = []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformHexDecode}}
+	_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "777", nil, WriteOptions{FileTransforms: fileTransforms})
+	if err == nil {
+		t.Fatalf("expected err to be not nil")
+	}
+	if errorCode != internalerrors.FileTransformError {
+		t.Errorf("expected error code: %s, got: %s", internalerrors.FileTransformError, errorCode)
+	}
+}
+
+func TestMountContent_Templates(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	targetPath := tmpdir.New(t, "", "ut")
+
+	pool := NewPluginClientBuilder(socketPath)
+	defer pool.Cleanup()
+
+	server, cleanup := fakeServer(t, socketPath, "provider1")
+	defer cleanup()
+
+	server.SetObjects(map[string]string{"username": "v1", "password": "v1"})
+	server.SetFiles([]*v1alpha1.File{
+		{Path: "username", Mode: 0644, Contents: []byte("user")},
+		{Path: "password", Mode: 0644, Contents: []byte("cGFzcw==")},
+	})
+	server.Start()
+
+	client, err := pool.Get(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	// templates are rendered with the transformed files
+	fileTransforms := []*secretsstorev1.FileTransform{
+		{Path: "password", Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformBase64Decode}}},
+	}
+	templates := []*secretsstorev1.SecretTemplate{
+		{Name: "url", Template: "{{ .username }}:{{ .password }}@host", Path: "url"},
+		{Name: "secret-only", Template: "{{ .username }}"},
+	}
+	if _, _, err = MountContent(context.TODO(), client, "{}", "{}", targetPath, "420", nil, WriteOptions{FileTransforms: fileTransforms, Templates: templates}); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(targetPath, "url"))
+	if err != nil {
+		t.Fatalf("unable to read mounted file: %s", err)
+	}
+	if string(got) != "user:pass@host" {
+		t.Errorf("expected rendered template %q, got %q", "user:pass@host", string(got))
+	}
+	if _, err := os.Stat(filepath.Join(targetPath, "secret-only")); !os.IsNotExist(err) {
+		t.Errorf("expected template without path not to be written, got: %v", err)
+	}
+
+	templates[0].Template = "{{ .missing }}"
+	_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "420", nil, WriteOptions{FileTransforms: fileTransforms, Templates: templates})
+	if err == nil {
+		t.Fatalf("expected err to be not nil")
+	}
+	if errorCode != internalerrors.TemplateRenderError {
+		t.Errorf("expected error code: %s, got: %s", internalerrors.TemplateRenderError, errorCode)
+	}
+}
+
 func TestMountContentError(t *testing.T) {
 	cases := []struct {
 		name                  string
@@ -342,20 +521,86 @@ func TestMountContentError(t *testing.T) {
 				t.Fatalf("expected err to be nil, got: %+v", err)
 			}
 
-			objectVersions, errorCode, err := MountContent(context.TODO(), client, test.attributes, test.secrets, test.targetPath, test.permission, nil)
+			objects, errorCode, err := MountContent(context.TODO(), client, test.attributes, test.secrets, test.targetPath, test.permission, nil, WriteOptions{})
 			if err == nil {
 				t.Errorf("expected err to be not nil")
 			}
 			if errorCode != test.expectedErrorCode {
 				t.Errorf("expected error code: %v, got: %+v", test.expectedErrorCode, errorCode)
 			}
-			if test.expectedObjectVersion != nil && !reflect.DeepEqual(test.expectedObjectVersion, objectVersions) {
+			if objectVersions := ObjectVersions(objects); test.expectedObjectVersion != nil && !reflect.DeepEqual(test.expectedObjectVersion, objectVersions) {
 				t.Errorf("expected object versions: %v, got: %+v", test.expectedObjectVersion, objectVersions)
 			}
 		})
 	}
 }
 
+func TestMountContentProviderError(t *testing.T) {
+	cases := []struct {
+		name          string
+		providerError *v1alpha1.Error
+		expected      *ProviderError
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "error code and message",
+			providerError: &v1alpha1.Error{Code: "SecretNotFound", Message: "secret db-password not found"},
+			expected:      &ProviderError{Code: "SecretNotFound", Message: "secret db-password not found"},
+			expectedCode:  codes.FailedPrecondition,
+		},
+		{
+			name:          "retryable error",
+			providerError: &v1alpha1.Error{Code: "Throttled", Retryable: true, RetryAfterSeconds: 30},
+			expected:      &ProviderError{Code: "Throttled", Retryable: true, RetryAfter: 30 * time.Second},
+			expectedCode:  codes.Unavailable,
+		},
+		{
+			name:          "message without error code",
+			providerError: &v1alpha1.Error{Message: "vault is sealed", Retryable: true},
+			expected:      &ProviderError{Code: internalerrors.ProviderError, Message: "vault is sealed", Retryable: true},
+			expectedCode:  codes.Unavailable,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			socketPath := tmpdir.New(t, "", "ut")
+			targetPath := tmpdir.New(t, "", "ut")
+
+			pool := NewPluginClientBuilder(socketPath)
+			defer pool.Cleanup()
+
+			server, cleanup := fakeServer(t, socketPath, "provider1")
+			defer cleanup()
+			server.SetProviderError(test.providerError)
+			server.Start()
+
+			client, err := pool.Get(context.Background(), "provider1")
+			if err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+
+			_, errorCode, err := MountContent(context.TODO(), client, "{}", "{}", targetPath, "420", nil, WriteOptions{})
+			var providerErr *ProviderError
+			if !errors.As(err, &providerErr) {
+				t.Fatalf("expected provider error, got: %+v", err)
+			}
+			if diff := cmp.Diff(test.expected, providerErr); diff != "" {
+				t.Errorf("provider error mismatch (-want +got):\n%s", diff)
+			}
+			if errorCode != test.expected.Code {
+				t.Errorf("expected error code: %v, got: %v", test.expected.Code, errorCode)
+			}
+			if test.expected.Message != "" && !strings.Contains(err.Error(), test.expected.Message) {
+				t.Errorf("expected error %q to contain the provider message", err.Error())
+			}
+			if code := mountErrorCode(err); code != test.expectedCode {
+				t.Errorf("expected grpc code: %v, got: %v", test.expectedCode, code)
+			}
+		})
+	}
+}
+
 func TestPluginClientBuilder(t *testing.T) {
 	path := tmpdir.New(t, "", "ut")
 
@@ -441,6 +686,91 @@ func TestPluginClientBuilderErrorInvalid(t *testing.T) {
 	}
 }
 
+func TestPluginClientBuilder_Negotiate(t *testing.T) {
+	cases := []struct {
+		name           string
+		minVersions    map[string]string
+		runtimeVersion string
+		capabilities   []string
+		wantErr        error
+	}{
+		{
+			name:           "no minimum version",
+			runtimeVersion: "0.0.10",
+			capabilities:   []string{"feature1"},
+		},
+		{
+			name:           "version above minimum",
+			minVersions:    map[string]string{"provider1": "0.0.9", "provider2": "1.0.0"},
+			runtimeVersion: "v0.1.0",
+		},
+		{
+			name:           "version below minimum",
+			minVersions:    map[string]string{"provider1": "0.1.0"},
+			runtimeVersion: "0.0.10",
+			wantErr:        ErrIncompatibleProviderVersion,
+		},
+		{
+			name:           "invalid version",
+			minVersions:    map[string]string{"provider1": "0.1.0"},
+			runtimeVersion: "latest",
+			wantErr:        ErrIncompatibleProviderVersion,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			socketPath := tmpdir.New(t, "", "ut")
+
+			pool := NewPluginClientBuilder(socketPath)
+			defer pool.Cleanup()
+			if err := pool.SetMinimumVersions(test.minVersions); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+
+			server, cleanup := fakeServer(t, socketPath, "provider1")
+			defer cleanup()
+			server.SetRuntimeVersion(test.runtimeVersion)
+			server.SetCapabilities(test.capabilities)
+			server.Start()
+
+			_, err := pool.Get(context.Background(), "provider1")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Get() = %v, want %v", err, test.wantErr)
+			}
+			info, ok := pool.Info("provider1")
+			if test.wantErr != nil {
+				if ok {
+					t.Errorf("expected no info for the refused provider, got: %+v", info)
+				}
+				if reason := LookupErrorReason(err); reason != internalerrors.IncompatibleProviderVersion {
+					t.Errorf("expected reason %s, got: %s", internalerrors.IncompatibleProviderVersion, reason)
+				}
+				return
+			}
+			want := ProviderInfo{RuntimeName: "fakeprovider", RuntimeVersion: test.runtimeVersion, Capabilities: test.capabilities}
+			if diff := cmp.Diff(want, info); diff != "" {
+				t.Errorf("Info() mismatch (-want +got):\n%s", diff)
+			}
+			for _, c := range test.capabilities {
+				if !pool.HasCapability("provider1", c) {
+					t.Errorf("expected provider to have capability %s", c)
+				}
+			}
+			if pool.HasCapability("provider1", "unknown") {
+				t.Errorf("expected provider to not have capability unknown")
+			}
+		})
+	}
+}
+
+func TestPluginClientBuilder_SetMinimumVersionsInvalid(t *testing.T) {
+	pool := NewPluginClientBuilder(tmpdir.New(t, "", "ut"))
+	if err := pool.SetMinimumVersions(map[string]string{"provider1": "latest"}); err == nil {
+		t.Errorf("expected error for invalid minimum version")
+	}
+}
+
 func TestVersion(t *testing.T) {
 	cases := []struct {
 		name                   string
@@ -551,3 +881,32 @@ func TestIsMaxRecvMsgSizeError(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSecretProviderClassObjects(t *testing.T) {
+	now := time.Unix(1000, 0)
+	objects := newSecretProviderClassObjects([]*v1alpha1.ObjectVersion{
+		{Id: "secret/static", Version: "v1"},
+		{Id: "secret/cert", Version: "v1", ExpiresAt: 1300},
+		{Id: "secret/token", Version: "v1", ExpiresAt: 1300, RefreshAfter: 1100},
+		{Id: "secret/static", Version: "v2"},
+	}, now)
+
+	expiresAt := metav1.Unix(1300, 0)
+	certRefresh := metav1.Unix(1200, 0)
+	tokenRefresh := metav1.Unix(1100, 0)
+	want := []secretsstorev1.SecretProviderClassObject{
+		{ID: "secret/static", Version: "v2"},
+		// the refresh time defaults to 2/3 of the time until the expiry
+		{ID: "secret/cert", Version: "v1", ExpiresAt: &expiresAt, RefreshAfter: &certRefresh},
+		{ID: "secret/token", Version: "v1", ExpiresAt: &expiresAt, RefreshAfter: &tokenRefresh},
+	}
+	if diff := cmp.Diff(want, objects); diff != "" {
+		t.Errorf("newSecretProviderClassObjects() mismatch (-want +got):\n%s", diff)
+	}
+	if next := NextRefreshTime(objects); next == nil || !next.Equal(tokenRefresh.Time) {
+		t.Errorf("expected next refresh time %v, got: %v", tokenRefresh.Time, next)
+	}
+	if next := NextRefreshTime(objects[:1]); next != nil {
+		t.Errorf("expected no next refresh time, got: %v", next)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultCircuitBreakerOpenDuration is the default duration the circuit breaker of a
+// provider stays open before a trial call is let through
+const defaultCircuitBreakerOpenDuration = 30 * time.Second
+
+// ProviderConfig is the configuration of the calls to the providers, loaded from the
+// driver's provider config file.
+type ProviderConfig struct {
+	// Default is the configuration of the providers that aren't in Providers
+	Default ProviderCallConfig `json:"default,omitempty"`
+	// Providers is the configuration of the providers, keyed by provider name. It replaces
+	// the default configuration for the provider.
+	Providers map[string]ProviderCallConfig `json:"providers,omitempty"`
+}
+
+// ProviderCallConfig configures the calls to a provider. The zero value doesn't limit the calls.
+type ProviderCallConfig struct {
+	// CallTimeout is the deadline of the calls to the provider, including the time waiting
+	// for a mount slot. The long-lived Watch() stream has no deadline.
+	CallTimeout metav1.Duration `json:"callTimeout,omitempty"`
+	// MaxConcurrentMounts is the maximum number of in-flight Mount() and MountStream() calls
+	// to the provider. The other mount calls wait for a slot.
+	MaxConcurrentMounts int `json:"maxConcurrentMounts,omitempty"`
+	// CircuitBreaker fails the calls to the provider fast after repeated failures
+	CircuitBreaker CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+	// DisableMountCoalescing calls the provider for each mount request, even if the provider has
+	// the MountCoalescing capability
+	DisableMountCoalescing bool `json:"disableMountCoalescing,omitempty"`
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a provider
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed calls that open the circuit
+	// breaker. The circuit breaker is disabled if it's 0.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// OpenDuration is how long the circuit breaker stays open before a trial call is
+	// let through. The circuit breaker closes if the trial call succeeds. Defaults to 30s.
+	OpenDuration metav1.Duration `json:"openDuration,omitempty"`
+}
+
+// LoadProviderConfig reads and validates the provider config file
+func LoadProviderConfig(path string) (*ProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider config %s, err: %w", path, err)
+	}
+	config := &ProviderConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse provider config %s, err: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid provider config %s, err: %w", path, err)
+	}
+	return config, nil
+}
+
+// Validate returns an error if the provider names or any of the values are invalid
+func (c *ProviderConfig) Validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for provider, config := range c.Providers {
+		if provider == "" || !PluginNameRe.MatchString(provider) {
+			return fmt.Errorf("%w: provider %q", ErrInvalidProvider, provider)
+		}
+		if err := config.validate(); err != nil {
+			return fmt.Errorf("provider %q: %w", provider, err)
+		}
+	}
+	return nil
+}
+
+// ForProvider returns the configuration of the provider
+func (c *ProviderConfig) ForProvider(provider string) ProviderCallConfig {
+	if config, ok := c.Providers[provider]; ok {
+		return config
+	}
+	return c.Default
+}
+
+func (c ProviderCallConfig) validate() error {
+	if c.CallTimeout.Duration < 0 {
+		return fmt.Errorf("callTimeout %s must not be negative", c.CallTimeout.Duration)
+	}
+	if c.MaxConcurrentMounts < 0 {
+		return fmt.Errorf("maxConcurrentMounts %d must not be negative", c.MaxConcurrentMounts)
+	}
+	if c.CircuitBreaker.FailureThreshold < 0 {
+		return fmt.Errorf("circuitBreaker.failureThreshold %d must not be negative", c.CircuitBreaker.FailureThreshold)
+	}
+	if c.CircuitBreaker.OpenDuration.Duration < 0 {
+		return fmt.Errorf("circuitBreaker.openDuration %s must not be negative", c.CircuitBreaker.OpenDuration.Duration)
+	}
+	return nil
+}
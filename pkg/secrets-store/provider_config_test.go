@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+)
+
+func TestLoadProviderConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected *ProviderConfig
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			expected: &ProviderConfig{},
+		},
+		{
+			name: "default and provider",
+			config: `
+default:
+  callTimeout: 60s
+providers:
+  provider1:
+    callTimeout: 30s
+    maxConcurrentMounts: 10
+    circuitBreaker:
+      failureThreshold: 5
+      openDuration: 1m
+`,
+			expected: &ProviderConfig{
+				Default: ProviderCallConfig{CallTimeout: metav1.Duration{Duration: time.Minute}},
+				Providers: map[string]ProviderCallConfig{
+					"provider1": {
+						CallTimeout:         metav1.Duration{Duration: 30 * time.Second},
+						MaxConcurrentMounts: 10,
+						CircuitBreaker: CircuitBreakerConfig{
+							FailureThreshold: 5,
+							OpenDuration:     metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown field",
+			config:  "default:\n  timeout: 60s\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			config:  "default:\n  callTimeout: 60\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative value",
+			config:  "providers:\n  provider1:\n    maxConcurrentMounts: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid provider name",
+			config:  "providers:\n  provider/1:\n    callTimeout: 30s\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(tmpdir.New(t, "", "ut"), "provider-config.yaml")
+			if err := os.WriteFile(path, []byte(test.config), 0600); err != nil {
+				t.Fatalf("expected err to be nil, got: %+v", err)
+			}
+			config, err := LoadProviderConfig(path)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("expected err: %v, got: %+v", test.wantErr, err)
+			}
+			if diff := cmp.Diff(test.expected, config); diff != "" {
+				t.Errorf("LoadProviderConfig() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviderConfigForProvider(t *testing.T) {
+	config := &ProviderConfig{
+		Default:   ProviderCallConfig{MaxConcurrentMounts: 1},
+		Providers: map[string]ProviderCallConfig{"provider1": {MaxConcurrentMounts: 2}},
+	}
+	if got := config.ForProvider("provider1").MaxConcurrentMounts; got != 2 {
+		t.Errorf("expected provider1 config, got maxConcurrentMounts %d", got)
+	}
+	if got := config.ForProvider("provider2").MaxConcurrentMounts; got != 1 {
+		t.Errorf("expected default config, got maxConcurrentMounts %d", got)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+)
+
+const (
+	// defaultHealthCheckFailureThreshold is the default number of consecutive health check
+	// failures after which a provider is unhealthy and its connection is reset
+	defaultHealthCheckFailureThreshold = 3
+	// healthCheckTimeout is the timeout of a provider health check
+	healthCheckTimeout = 5 * time.Second
+)
+
+// ProviderHealth is the health check state of a provider
+type ProviderHealth struct {
+	Name string `json:"name"`
+	// Healthy is false after the failure threshold of consecutive health check failures
+	Healthy bool `json:"healthy"`
+	// ConsecutiveFailures is the number of health checks that failed since the last successful one
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// Error is the error of the last failed health check
+	Error string `json:"error,omitempty"`
+}
+
+// SetHealthCheckFailureThreshold sets the number of consecutive health check failures after
+// which a provider is unhealthy and its connection is reset. It must be called before HealthCheck.
+func (p *PluginClientBuilder) SetHealthCheckFailureThreshold(threshold int) error {
+	if threshold < 1 {
+		return fmt.Errorf("invalid health check failure threshold %d, must be at least 1", threshold)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.healthCheckFailureThreshold = threshold
+	return nil
+}
+
+// CheckHealth returns an error wrapping ErrProviderUnhealthy if the health check of the
+// provider failed the failure threshold of consecutive times. Providers that aren't health
+// checked are assumed healthy.
+func (p *PluginClientBuilder) CheckHealth(provider string) error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	health, ok := p.health[provider]
+	if !ok || health.Healthy {
+		return nil
+	}
+	return fmt.Errorf("%w: provider %q failed %d consecutive health checks, last error: %s", ErrProviderUnhealthy, provider, health.ConsecutiveFailures, health.Error)
+}
+
+// HealthStatus returns the health check state of the providers, sorted by name
+func (p *PluginClientBuilder) HealthStatus() []ProviderHealth {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	status := make([]ProviderHealth, 0, len(p.health))
+	for _, health := range p.health {
+		status = append(status, *health)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	return status
+}
+
+// healthCheckProviders returns the providers with a client and the providers that are
+// unhealthy, whose connection was reset
+func (p *PluginClientBuilder) healthCheckProviders() []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var providers []string
+	for provider := range p.clients {
+		providers = append(providers, provider)
+	}
+	for provider := range p.health {
+		if _, ok := p.clients[provider]; !ok {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// checkHealth calls the provider's Version() RPC and records the result. The connection to
+// the provider is reset when the provider becomes unhealthy, and the provider is connected
+// to again by the next health check.
+func (p *PluginClientBuilder) checkHealth(ctx context.Context, provider string) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	client, err := p.Get(ctx, provider)
+	if err == nil {
+		// don't wait for the connection to be ready, so a provider that's down fails the
+		// health check right away instead of at the timeout
+		var resp *v1alpha1.VersionResponse
+		if resp, err = client.Version(ctx, &v1alpha1.VersionRequest{Version: "v1alpha1"}, grpc.WaitForReady(false)); err == nil {
+			klog.V(4).InfoS("provider healthcheck successful", "provider", provider, "runtimeVersion", resp.GetRuntimeVersion())
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	health, ok := p.health[provider]
+	if !ok {
+		health = &ProviderHealth{Name: provider, Healthy: true}
+		p.health[provider] = health
+	}
+	if err == nil {
+		if !health.Healthy {
+			klog.InfoS("provider is healthy", "provider", provider)
+		}
+		*health = ProviderHealth{Name: provider, Healthy: true}
+		return
+	}
+
+	health.ConsecutiveFailures++
+	health.Error = err.Error()
+	klog.V(4).ErrorS(err, "provider healthcheck failed", "provider", provider, "consecutiveFailures", health.ConsecutiveFailures)
+
+	threshold := p.healthCheckFailureThreshold
+	if threshold == 0 {
+		threshold = defaultHealthCheckFailureThreshold
+	}
+	if health.ConsecutiveFailures < threshold {
+		return
+	}
+	if health.Healthy {
+		klog.ErrorS(err, "provider is unhealthy, resetting the connection", "provider", provider, "consecutiveFailures", health.ConsecutiveFailures)
+	}
+	health.Healthy = false
+	p.closeConnection(provider)
+}
+
+// NewHealthHandler returns a healthz-style readiness handler that fails if any of the
+// health checked providers is unhealthy, and lists the state of each provider
+func NewHealthHandler(providerClients *PluginClientBuilder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var out strings.Builder
+		healthy := true
+		for _, health := range providerClients.HealthStatus() {
+			if health.Healthy {
+				fmt.Fprintf(&out, "[+]%s ok\n", health.Name)
+				continue
+			}
+			healthy = false
+			fmt.Fprintf(&out, "[-]%s failed: %d consecutive health checks failed\n", health.Name, health.ConsecutiveFailures)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if !healthy {
+			out.WriteString("providers check failed\n")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			out.WriteString("providers check passed\n")
+		}
+		if _, err := w.Write([]byte(out.String())); err != nil {
+			klog.ErrorS(err, "failed to write provider health")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/test_utils/tmpdir"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/fake"
+)
+
+func getHealth(t *testing.T, pool *PluginClientBuilder) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	NewHealthHandler(pool).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz/providers", nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestCheckHealth(t *testing.T) {
+	socketPath := tmpdir.New(t, "", "ut")
+	pool := NewPluginClientBuilder(socketPath)
+	defer pool.Cleanup()
+	if err := pool.SetHealthCheckFailureThreshold(2); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	server, err := fake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider1.sock"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	ctx := context.Background()
+	pool.checkHealth(ctx, "provider1")
+	if err := pool.CheckHealth("provider1"); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if code, body := getHealth(t, pool); code != http.StatusOK || !strings.Contains(body, "[+]provider1 ok") {
+		t.Fatalf("expected provider1 to be healthy, got: %d %s", code, body)
+	}
+
+	// the provider is healthy until the failure threshold is reached
+	server.Stop()
+	pool.checkHealth(ctx, "provider1")
+	if err := pool.CheckHealth("provider1"); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	pool.checkHealth(ctx, "provider1")
+	if err := pool.CheckHealth("provider1"); !errors.Is(err, ErrProviderUnhealthy) {
+		t.Fatalf("expected err to be ErrProviderUnhealthy, got: %+v", err)
+	}
+	if providers := pool.Providers(); len(providers) != 0 {
+		t.Fatalf("expected the connection to be reset, got providers: %v", providers)
+	}
+	if code, body := getHealth(t, pool); code != http.StatusServiceUnavailable || !strings.Contains(body, "[-]provider1 failed: 2 consecutive health checks failed") {
+		t.Fatalf("expected provider1 to be unhealthy, got: %d %s", code, body)
+	}
+	// the socket of a restarted provider resets the connection, not the health check state
+	pool.Reset("provider1")
+	if err := pool.CheckHealth("provider1"); !errors.Is(err, ErrProviderUnhealthy) {
+		t.Fatalf("expected err to be ErrProviderUnhealthy after reset, got: %+v", err)
+	}
+
+	// the unhealthy provider is still health checked and recovers
+	server, err = fake.NewMocKCSIProviderServer(filepath.Join(socketPath, "provider1.sock"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	defer server.Stop()
+	if providers := pool.healthCheckProviders(); len(providers) != 1 || providers[0] != "provider1" {
+		t.Fatalf("expected provider1 to be health checked, got: %v", providers)
+	}
+	pool.checkHealth(ctx, "provider1")
+	if err := pool.CheckHealth("provider1"); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if status := pool.HealthStatus(); len(status) != 1 || !status[0].Healthy || status[0].ConsecutiveFailures != 0 {
+		t.Fatalf("expected provider1 to be healthy, got: %+v", status)
+	}
+
+	// providers that aren't health checked are assumed healthy
+	if err := pool.CheckHealth("provider2"); err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+
+	// a removed provider is no longer health checked
+	pool.Remove("provider1")
+	if providers := pool.healthCheckProviders(); len(providers) != 0 {
+		t.Fatalf("expected no provider to be health checked, got: %v", providers)
+	}
+}
+
+func TestSetHealthCheckFailureThresholdInvalid(t *testing.T) {
+	pool := NewPluginClientBuilder(tmpdir.New(t, "", "ut"))
+	defer pool.Cleanup()
+	for _, threshold := range []int{0, -1} {
+		if err := pool.SetHealthCheckFailureThreshold(threshold); err == nil {
+			t.Errorf("expected err for threshold %d, got nil", threshold)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"k8s.io/client-go/tools/record"
 	mount "k8s.io/mount-utils"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,7 +45,7 @@ func GetDriver() *SecretsStore {
 	return &SecretsStore{}
 }
 
-func newNodeServer(d *csicommon.CSIDriver, providerVolumePath, nodeID string, mounter mount.Interface, providerClients *PluginClientBuilder, client client.Client, statsReporter StatsReporter) (*nodeServer, error) {
+func newNodeServer(d *csicommon.CSIDriver, providerVolumePath, nodeID string, mounter mount.Interface, providerClients *PluginClientBuilder, client client.Client, reader client.Reader, eventRecorder record.EventRecorder, statsReporter StatsReporter) (*nodeServer, error) {
 	return &nodeServer{
 		DefaultNodeServer:  csicommon.NewDefaultNodeServer(d),
 		providerVolumePath: providerVolumePath,
@@ -52,6 +53,8 @@ func newNodeServer(d *csicommon.CSIDriver, providerVolumePath, nodeID string, mo
 		reporter:           statsReporter,
 		nodeID:             nodeID,
 		client:             client,
+		reader:             reader,
+		eventRecorder:      eventRecorder,
 		providerClients:    providerClients,
 	}, nil
 }
@@ -69,8 +72,10 @@ func newIdentityServer(d *csicommon.CSIDriver) *identityServer {
 	}
 }
 
-// Run starts the CSI plugin
-func (s *SecretsStore) Run(ctx context.Context, driverName, nodeID, endpoint, providerVolumePath string, providerClients *PluginClientBuilder, client client.Client) {
+// Run starts the CSI plugin. The reader is used to read the configmaps and secrets referenced
+// in the secret provider class parametersFrom, which aren't in the client cache. The event
+// recorder records the secrets store policy violations on the pods.
+func (s *SecretsStore) Run(ctx context.Context, driverName, nodeID, endpoint, providerVolumePath string, providerClients *PluginClientBuilder, client client.Client, reader client.Reader, eventRecorder record.EventRecorder) {
 	klog.Infof("Driver: %v ", driverName)
 	klog.Infof("Version: %s, BuildTime: %s", version.BuildVersion, version.BuildTime)
 	klog.Infof("Provider Volume Path: %s", providerVolumePath)
@@ -90,7 +95,7 @@ func (s *SecretsStore) Run(ctx context.Context, driverName, nodeID, endpoint, pr
 		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
 	})
 
-	ns, err := newNodeServer(s.driver, providerVolumePath, nodeID, mount.New(""), providerClients, client, NewStatsReporter())
+	ns, err := newNodeServer(s.driver, providerVolumePath, nodeID, mount.New(""), providerClients, client, reader, eventRecorder, NewStatsReporter())
 	if err != nil {
 		klog.Fatalf("failed to initialize node server, error: %+v", err)
 	}
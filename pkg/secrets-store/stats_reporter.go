@@ -26,6 +26,7 @@ var (
 	providerKey             = "provider"
 	errorKey                = "error_type"
 	osTypeKey               = "os_type"
+	reasonKey               = "reason"
 	nodePublishTotal        metric.Int64Counter
 	nodeUnPublishTotal      metric.Int64Counter
 	nodePublishErrorTotal   metric.Int64Counter
@@ -59,6 +60,50 @@ func NewStatsReporter() StatsReporter {
 	return &reporter{meter: meter}
 }
 
+// NewProviderHealthObserver reports the health check state of the providers as a gauge
+func NewProviderHealthObserver(providerClients *PluginClientBuilder) {
+	meter := global.Meter("secretsstore")
+	metric.Must(meter).NewInt64ValueObserver("provider_healthy", func(ctx context.Context, result metric.Int64ObserverResult) {
+		for _, health := range providerClients.HealthStatus() {
+			var healthy int64
+			if health.Healthy {
+				healthy = 1
+			}
+			result.Observe(healthy, label.String(providerKey, health.Name), label.String(osTypeKey, runtimeOS))
+		}
+	}, metric.WithDescription("Whether the provider passed the health checks (1) or failed the threshold of consecutive health checks (0)"))
+}
+
+// NewProviderCallObserver reports the state of the calls to the providers limited by the provider config
+func NewProviderCallObserver(providerClients *PluginClientBuilder) {
+	meter := global.Meter("secretsstore")
+	observe := func(result metric.Int64ObserverResult, value func(ProviderCallStatus) int64) {
+		for _, s := range providerClients.CallStatus() {
+			result.Observe(value(s), label.String(providerKey, s.Name), label.String(osTypeKey, runtimeOS))
+		}
+	}
+	metric.Must(meter).NewInt64ValueObserver("provider_circuit_breaker_open", func(ctx context.Context, result metric.Int64ObserverResult) {
+		observe(result, func(s ProviderCallStatus) int64 {
+			if s.CircuitOpen {
+				return 1
+			}
+			return 0
+		})
+	}, metric.WithDescription("Whether the circuit breaker of the provider is open (1) or closed (0)"))
+	metric.Must(meter).NewInt64ValueObserver("provider_mount_in_flight", func(ctx context.Context, result metric.Int64ObserverResult) {
+		observe(result, func(s ProviderCallStatus) int64 { return int64(s.InFlightMounts) })
+	}, metric.WithDescription("Number of in-flight mount calls to the provider"))
+	metric.Must(meter).NewInt64SumObserver("total_provider_call_timeout", func(ctx context.Context, result metric.Int64ObserverResult) {
+		observe(result, func(s ProviderCallStatus) int64 { return s.Timeouts })
+	}, metric.WithDescription("Total number of provider calls that reached the call timeout"))
+	metric.Must(meter).NewInt64SumObserver("total_provider_call_rejected", func(ctx context.Context, result metric.Int64ObserverResult) {
+		for _, s := range providerClients.CallStatus() {
+			result.Observe(s.CircuitOpenRejections, label.String(providerKey, s.Name), label.String(reasonKey, "circuit_open"), label.String(osTypeKey, runtimeOS))
+			result.Observe(s.MountLimitRejections, label.String(providerKey, s.Name), label.String(reasonKey, "mount_limit"), label.String(osTypeKey, runtimeOS))
+		}
+	}, metric.WithDescription("Total number of provider calls rejected by the open circuit breaker or the mount limit"))
+}
+
 func (r *reporter) ReportNodePublishCtMetric(provider string) {
 	labels := []label.KeyValue{label.String(providerKey, provider), label.String(osTypeKey, runtimeOS)}
 	nodePublishTotal.Add(context.Background(), 1, labels...)
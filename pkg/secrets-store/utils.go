@@ -17,19 +17,27 @@ limitations under the License.
 package secretsstore
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"context"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcpsutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/spcutil"
 )
 
 // ensureMountPoint ensures mount point is valid
@@ -74,40 +82,41 @@ func (ns *nodeServer) ensureMountPoint(target string) (bool, error) {
 	return false, nil
 }
 
-// getSecretProviderItem returns the secretproviderclass object by name and namespace
-func getSecretProviderItem(ctx context.Context, c client.Client, name, namespace string) (*v1alpha1.SecretProviderClass, error) {
-	spc := &v1alpha1.SecretProviderClass{}
-	spcKey := types.NamespacedName{
-		Namespace: namespace,
-		Name:      name,
-	}
-	if err := c.Get(ctx, spcKey, spc); err != nil {
+// getSecretProviderItem returns the secretproviderclass object by kind, name and namespace.
+// A clustersecretproviderclass is returned as a secretproviderclass in the namespace.
+func getSecretProviderItem(ctx context.Context, c client.Client, kind, name, namespace string) (*secretsstorev1.SecretProviderClass, error) {
+	spc, err := spcutil.GetSecretProviderClass(ctx, c, kind, name, namespace)
+	if err != nil {
+		if kind == secretsstorev1.ClusterSecretProviderClassKind {
+			return nil, fmt.Errorf("failed to get clustersecretproviderclass %s for namespace %s, error: %+v", name, namespace, err)
+		}
 		return nil, fmt.Errorf("failed to get secretproviderclass %s/%s, error: %+v", namespace, name, err)
 	}
 	return spc, nil
 }
 
 // createSecretProviderClassPodStatus creates secret provider class pod status
-func createSecretProviderClassPodStatus(ctx context.Context, c client.Client, podname, namespace, podUID, spcName, targetPath, nodeID string, mounted bool, objects map[string]string) error {
-	var o []v1alpha1.SecretProviderClassObject
-	for k, v := range objects {
-		o = append(o, v1alpha1.SecretProviderClassObject{ID: k, Version: v})
-	}
-
-	spcPodStatus := &v1alpha1.SecretProviderClassPodStatus{
+func createSecretProviderClassPodStatus(ctx context.Context, c client.Client, podname, namespace, podUID, spcKind, spcName, provider, targetPath, nodeID string, mounted bool, objects []secretsstorev1.SecretProviderClassObject) error {
+	now := metav1.Now()
+	spcPodStatus := &secretsstorev1.SecretProviderClassPodStatus{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      podname + "-" + namespace + "-" + spcName,
+			Name:      spcPodStatusName(podname, namespace, spcKind, spcName),
 			Namespace: namespace,
-			Labels:    map[string]string{v1alpha1.InternalNodeLabel: nodeID},
+			Labels:    map[string]string{secretsstorev1.InternalNodeLabel: nodeID},
 		},
-		Status: v1alpha1.SecretProviderClassPodStatusStatus{
+		Status: secretsstorev1.SecretProviderClassPodStatusStatus{
 			PodName:                 podname,
 			TargetPath:              targetPath,
 			Mounted:                 mounted,
 			SecretProviderClassName: spcName,
-			Objects:                 o,
+			SecretProviderClassKind: spcKind,
+			Provider:                provider,
+			Objects:                 objects,
+			LastAttemptTime:         &now,
 		},
 	}
+	spcpsutil.SetCondition(spcPodStatus, secretsstorev1.ConditionTypeMounted, "", nil)
+	spcpsutil.SetProviderHealthyCondition(spcPodStatus, "", nil)
 	// Set owner reference to the pod as the mapping between secret provider class pod status and
 	// pod is 1 to 1. When pod is deleted, the spc pod status will automatically be garbage collected
 	spcPodStatus.SetOwnerReferences([]metav1.OwnerReference{
@@ -121,23 +130,107 @@ func createSecretProviderClassPodStatus(ctx context.Context, c client.Client, po
 
 	// create the secret provider class pod status
 	err := c.Create(ctx, spcPodStatus, &client.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	if apierrors.IsAlreadyExists(err) {
+		// the spc pod status exists from a previous node publish for the pod,
+		// so only record the outcome of this mount in the conditions
+		return updateSecretProviderClassPodStatusConditions(ctx, c, spcPodStatus.Name, namespace, "", true, nil)
+	}
+	return err
+}
+
+// updateSecretProviderClassPodStatusConditions records the outcome of a node publish in the
+// conditions of an existing secret provider class pod status. The ProviderHealthy condition is
+// only updated if the provider was called. It's a no-op if the spc pod status doesn't exist.
+func updateSecretProviderClassPodStatusConditions(ctx context.Context, c client.Client, name, namespace, errorReason string, providerCalled bool, mountErr error) error {
+	spcPodStatus := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, spcPodStatus); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
-	return nil
+	patch := client.MergeFrom(spcPodStatus.DeepCopy())
+
+	reason := errorReason
+	if reason == "" {
+		reason = internalerrors.FailedToMount
+	}
+	spcpsutil.SetCondition(spcPodStatus, secretsstorev1.ConditionTypeMounted, reason, mountErr)
+	if providerCalled {
+		spcpsutil.SetProviderHealthyCondition(spcPodStatus, errorReason, mountErr)
+	}
+	now := metav1.Now()
+	spcPodStatus.Status.LastAttemptTime = &now
+	return c.Patch(ctx, spcPodStatus, patch)
+}
+
+// getSecretProviderClassPodStatusForTargetPath returns the secret provider class pod status of the
+// node for the target path, or nil if there's none.
+func getSecretProviderClassPodStatusForTargetPath(ctx context.Context, c client.Client, nodeID, targetPath string) (*secretsstorev1.SecretProviderClassPodStatus, error) {
+	spcPodStatuses := &secretsstorev1.SecretProviderClassPodStatusList{}
+	if err := c.List(ctx, spcPodStatuses, client.MatchingLabels{secretsstorev1.InternalNodeLabel: nodeID}); err != nil {
+		return nil, err
+	}
+	for i := range spcPodStatuses.Items {
+		if spcPodStatuses.Items[i].Status.TargetPath == targetPath {
+			return &spcPodStatuses.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// podUIDForSecretProviderClassPodStatus returns the UID of the pod that owns the secret provider
+// class pod status, or the pod UID in the target path if the owner reference isn't set.
+func podUIDForSecretProviderClassPodStatus(spcps *secretsstorev1.SecretProviderClassPodStatus) string {
+	for _, ref := range spcps.OwnerReferences {
+		if ref.Kind == "Pod" && ref.Name == spcps.Status.PodName {
+			return string(ref.UID)
+		}
+	}
+	return fileutil.GetPodUIDFromTargetPath(spcps.Status.TargetPath)
+}
+
+// mountErrorCode returns the gRPC status code returned to kubelet for an error of the provider
+// mount request. The status code of the provider's gRPC errors is kept, and the provider errors
+// are mapped by whether they're retryable.
+func mountErrorCode(err error) codes.Code {
+	if errors.Is(err, ErrProviderUnhealthy) || errors.Is(err, ErrProviderCircuitOpen) {
+		return codes.Unavailable
+	}
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.GRPCCode()
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	return codes.Unknown
+}
+
+// spcPodStatusName returns the name of the secret provider class pod status
+// for the pod and secret provider class. The name of a cluster secret provider
+// class pod status has the kind as suffix, so a pod can mount a secret provider
+// class and a cluster secret provider class with the same name.
+func spcPodStatusName(podName, namespace, spcKind, spcName string) string {
+	name := podName + "-" + namespace + "-" + spcName
+	if spcKind == secretsstorev1.ClusterSecretProviderClassKind {
+		name += "-" + strings.ToLower(spcKind)
+	}
+	return name
 }
 
 // getProviderFromSPC returns the provider as defined in SecretProviderClass
-func getProviderFromSPC(spc *v1alpha1.SecretProviderClass) (string, error) {
+func getProviderFromSPC(spc *secretsstorev1.SecretProviderClass) (string, error) {
 	if len(spc.Spec.Provider) == 0 {
 		return "", fmt.Errorf("provider not set in %s/%s", spc.Namespace, spc.Name)
 	}
 	return string(spc.Spec.Provider), nil
 }
 
-// getParametersFromSPC returns the parameters map as defined in SecretProviderClass
-func getParametersFromSPC(spc *v1alpha1.SecretProviderClass) (map[string]string, error) {
-	if len(spc.Spec.Parameters) == 0 {
+// getParametersFromSPC returns the parameters map as defined in SecretProviderClass. The
+// parameters can be empty if they're only set with parametersFrom.
+func getParametersFromSPC(spc *secretsstorev1.SecretProviderClass) (map[string]string, error) {
+	if len(spc.Spec.Parameters) == 0 && len(spc.Spec.ParametersFrom) == 0 {
 		return nil, fmt.Errorf("parameters not set in %s/%s", spc.Namespace, spc.Name)
 	}
 	return spc.Spec.Parameters, nil
@@ -15,3 +15,69 @@ limitations under the License.
 */
 
 package secretsstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+)
+
+func TestCreateSecretProviderClassPodStatusConditions(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := secretsstorev1.AddToScheme(s); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	c := fake.NewFakeClientWithScheme(s)
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: "pod1-default-spc1", Namespace: "default"}
+
+	// no-op when the spc pod status doesn't exist
+	if err := updateSecretProviderClassPodStatusConditions(ctx, c, key.Name, key.Namespace, internalerrors.GRPCProviderError, true, errors.New("error")); err != nil {
+		t.Fatalf("updateSecretProviderClassPodStatusConditions() error = %v", err)
+	}
+
+	if err := createSecretProviderClassPodStatus(ctx, c, "pod1", "default", "uid", secretsstorev1.SecretProviderClassKind, "spc1", "provider1", "/target", "node1", true, []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v1"}}); err != nil {
+		t.Fatalf("createSecretProviderClassPodStatus() error = %v", err)
+	}
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+	if err := c.Get(ctx, key, spcps); err != nil {
+		t.Fatalf("failed to get spc pod status, err: %v", err)
+	}
+	for _, conditionType := range []string{secretsstorev1.ConditionTypeMounted, secretsstorev1.ConditionTypeProviderHealthy} {
+		if !meta.IsStatusConditionTrue(spcps.Status.Conditions, conditionType) {
+			t.Fatalf("expected condition %s to be true, got %+v", conditionType, spcps.Status.Conditions)
+		}
+	}
+	if spcps.Status.LastAttemptTime == nil {
+		t.Fatalf("expected last attempt time to be set")
+	}
+	if spcps.Status.Provider != "provider1" {
+		t.Fatalf("expected provider to be provider1, got %q", spcps.Status.Provider)
+	}
+
+	if err := updateSecretProviderClassPodStatusConditions(ctx, c, key.Name, key.Namespace, internalerrors.GRPCProviderError, true, errors.New("error")); err != nil {
+		t.Fatalf("updateSecretProviderClassPodStatusConditions() error = %v", err)
+	}
+	if err := c.Get(ctx, key, spcps); err != nil {
+		t.Fatalf("failed to get spc pod status, err: %v", err)
+	}
+	expected := []metav1.Condition{
+		{Type: secretsstorev1.ConditionTypeMounted, Status: metav1.ConditionFalse, Reason: internalerrors.GRPCProviderError, Message: "error"},
+		{Type: secretsstorev1.ConditionTypeProviderHealthy, Status: metav1.ConditionFalse, Reason: internalerrors.GRPCProviderError, Message: "error"},
+	}
+	for _, e := range expected {
+		got := meta.FindStatusCondition(spcps.Status.Conditions, e.Type)
+		if got == nil || got.Status != e.Status || got.Reason != e.Reason || got.Message != e.Message {
+			t.Fatalf("expected condition %+v, got %+v", e, got)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"fmt"
+	"strings"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/fileutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/k8sutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/secretutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/templateutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/transformutil"
+	"sigs.k8s.io/secrets-store-csi-driver/provider/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// requiredSecretKeys are the keys that the kubernetes api server requires in the
+// secret data for the secret type. Secrets of these types can't be synced without them.
+var requiredSecretKeys = map[corev1.SecretType][]string{
+	corev1.SecretTypeTLS:              {corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+	corev1.SecretTypeSSHAuth:          {corev1.SSHAuthPrivateKey},
+	corev1.SecretTypeDockerConfigJson: {corev1.DockerConfigJsonKey},
+	corev1.SecretTypeDockercfg:        {corev1.DockerConfigKey},
+}
+
+// ValidateSecretProviderClass validates the secret provider class spec. It returns the
+// errors that would otherwise only be reported when the volume is mounted or the
+// secrets are synced, and warnings for fields that are accepted but probably not intended.
+func ValidateSecretProviderClass(spc *secretsstorev1.SecretProviderClass) (field.ErrorList, []string) {
+	var allErrs field.ErrorList
+	var warnings []string
+	specPath := field.NewPath("spec")
+
+	provider, err := getProviderFromSPC(spc)
+	if err != nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("provider"), err.Error()))
+	} else if !PluginNameRe.MatchString(provider) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("provider"), provider, fmt.Sprintf("must match %s", PluginNameRe.String())))
+	}
+
+	if mode := spc.Spec.DefaultMode; mode != nil && (*mode < 0 || *mode > 0777) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("defaultMode"), *mode, "must be a file mode between 0 and 0777"))
+	}
+
+	for _, k := range sets.StringKeySet(spc.Spec.Parameters).List() {
+		if err := k8sutil.ValidateParameter(spc.Spec.Parameters[k]); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("parameters").Key(k), spc.Spec.Parameters[k], err.Error()))
+		}
+	}
+
+	for i, from := range spc.Spec.ParametersFrom {
+		fromPath := specPath.Child("parametersFrom").Index(i)
+		if from == nil || (from.ConfigMapRef == nil) == (from.SecretRef == nil) {
+			allErrs = append(allErrs, field.Invalid(fromPath, from, "exactly one of configMapRef and secretRef must be set"))
+			continue
+		}
+		ref, refPath := from.ConfigMapRef, fromPath.Child("configMapRef")
+		if from.SecretRef != nil {
+			ref, refPath = from.SecretRef, fromPath.Child("secretRef")
+		}
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(refPath.Child("name"), "name is empty"))
+		}
+	}
+
+	for i, ft := range spc.Spec.FileTransforms {
+		ftPath := specPath.Child("fileTransforms").Index(i)
+		if ft == nil {
+			allErrs = append(allErrs, field.Required(ftPath, "file transform is empty"))
+			continue
+		}
+		if len(ft.Path) == 0 {
+			allErrs = append(allErrs, field.Required(ftPath.Child("path"), "path of the file is empty"))
+		}
+		allErrs = append(allErrs, validateTransforms(ftPath.Child("transforms"), ft.Transforms)...)
+	}
+
+	templateNames := sets.NewString()
+	templatePaths := sets.NewString()
+	for i, t := range spc.Spec.Templates {
+		tPath := specPath.Child("templates").Index(i)
+		if t == nil {
+			allErrs = append(allErrs, field.Required(tPath, "template is empty"))
+			continue
+		}
+		if len(t.Name) == 0 {
+			allErrs = append(allErrs, field.Required(tPath.Child("name"), "name of the template is empty"))
+		} else if templateNames.Has(t.Name) {
+			allErrs = append(allErrs, field.Duplicate(tPath.Child("name"), t.Name))
+		}
+		templateNames.Insert(t.Name)
+		if _, err := templateutil.Parse(t); err != nil {
+			allErrs = append(allErrs, field.Invalid(tPath.Child("template"), t.Template, err.Error()))
+		}
+		if len(t.Path) > 0 {
+			if err := fileutil.Validate([]*v1alpha1.File{{Path: t.Path}}); err != nil {
+				allErrs = append(allErrs, field.Invalid(tPath.Child("path"), t.Path, err.Error()))
+			}
+			if templatePaths.Has(t.Path) {
+				allErrs = append(allErrs, field.Duplicate(tPath.Child("path"), t.Path))
+			}
+			templatePaths.Insert(t.Path)
+		}
+	}
+
+	secretNames := sets.NewString()
+	for i, secretObj := range spc.Spec.SecretObjects {
+		secretObjPath := specPath.Child("secretObjects").Index(i)
+		if secretObj == nil {
+			allErrs = append(allErrs, field.Required(secretObjPath, "secret object is empty"))
+			continue
+		}
+		errs, warns := validateSecretObject(secretObjPath, *secretObj, templateNames)
+		allErrs = append(allErrs, errs...)
+		warnings = append(warnings, warns...)
+
+		if secretObj.SecretName != "" {
+			if secretNames.Has(secretObj.SecretName) {
+				allErrs = append(allErrs, field.Duplicate(secretObjPath.Child("secretName"), secretObj.SecretName))
+			}
+			secretNames.Insert(secretObj.SecretName)
+		}
+	}
+
+	configMapNames := sets.NewString()
+	for i, configMapObj := range spc.Spec.ConfigMapObjects {
+		configMapObjPath := specPath.Child("configMapObjects").Index(i)
+		if configMapObj == nil {
+			allErrs = append(allErrs, field.Required(configMapObjPath, "configmap object is empty"))
+			continue
+		}
+		allErrs = append(allErrs, validateConfigMapObject(configMapObjPath, *configMapObj, templateNames)...)
+
+		if configMapObj.ConfigMapName != "" {
+			if configMapNames.Has(configMapObj.ConfigMapName) {
+				allErrs = append(allErrs, field.Duplicate(configMapObjPath.Child("configMapName"), configMapObj.ConfigMapName))
+			}
+			configMapNames.Insert(configMapObj.ConfigMapName)
+		}
+	}
+	return allErrs, warnings
+}
+
+// ValidateClusterSecretProviderClass validates the cluster secret provider class spec. The
+// secret provider class spec is validated the same as for a SecretProviderClass, in addition
+// to the namespace selector.
+func ValidateClusterSecretProviderClass(cspc *secretsstorev1.ClusterSecretProviderClass) (field.ErrorList, []string) {
+	allErrs, warnings := ValidateSecretProviderClass(&secretsstorev1.SecretProviderClass{
+		ObjectMeta: cspc.ObjectMeta,
+		Spec:       cspc.Spec.SecretProviderClassSpec,
+	})
+	if cspc.Spec.NamespaceSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(cspc.Spec.NamespaceSelector, field.NewPath("spec", "namespaceSelector"))...)
+	}
+	return allErrs, warnings
+}
+
+// validateSecretObject validates a secret object in the secret provider class. In addition
+// to the mandatory fields checked by secretutil.ValidateSecretObject, the secret name, keys,
+// labels and annotations must be valid for a Kubernetes secret, and the templates referenced
+// by the data must be defined.
+func validateSecretObject(path *field.Path, secretObj secretsstorev1.SecretObject, templateNames sets.String) (field.ErrorList, []string) {
+	var allErrs field.ErrorList
+	var warnings []string
+
+	if err := secretutil.ValidateSecretObject(secretObj); err != nil {
+		switch {
+		case len(secretObj.SecretName) == 0:
+			allErrs = append(allErrs, field.Required(path.Child("secretName"), err.Error()))
+		case len(secretObj.Type) == 0:
+			allErrs = append(allErrs, field.Required(path.Child("type"), err.Error()))
+		default:
+			allErrs = append(allErrs, field.Required(path.Child("data"), err.Error()))
+		}
+	}
+	if len(secretObj.SecretName) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(secretObj.SecretName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("secretName"), secretObj.SecretName, msg))
+		}
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(secretObj.Labels, path.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(secretObj.Annotations, path.Child("annotations"))...)
+
+	secretType := secretutil.GetSecretType(strings.TrimSpace(secretObj.Type))
+	if len(secretObj.Type) > 0 && string(secretType) != strings.TrimSpace(secretObj.Type) {
+		warnings = append(warnings, fmt.Sprintf("%s: %q is not a supported secret type, the secret is created with type %s", path.Child("type"), secretObj.Type, secretType))
+	}
+
+	errs, keys := validateData(path.Child("data"), secretObj.Data, templateNames)
+	allErrs = append(allErrs, errs...)
+	// the tls cert and key are extracted from the object content, which is only
+	// supported for the tls.crt and tls.key keys
+	if secretType == corev1.SecretTypeTLS {
+		for j, data := range secretObj.Data {
+			key := ""
+			if data != nil {
+				key = strings.TrimSpace(data.Key)
+			}
+			if len(key) > 0 && key != corev1.TLSCertKey && key != corev1.TLSPrivateKeyKey {
+				allErrs = append(allErrs, field.NotSupported(path.Child("data").Index(j).Child("key"), key, []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}))
+			}
+		}
+	}
+	if len(secretObj.Data) > 0 {
+		for _, required := range requiredSecretKeys[secretType] {
+			if !keys.Has(required) {
+				allErrs = append(allErrs, field.Required(path.Child("data"), fmt.Sprintf("key %s is required for secret type %s", required, secretType)))
+			}
+		}
+	}
+	return allErrs, warnings
+}
+
+// validateConfigMapObject validates a configmap object in the secret provider class. The
+// configmap name, keys, labels and annotations must be valid for a Kubernetes configmap, and
+// the templates referenced by the data must be defined.
+func validateConfigMapObject(path *field.Path, configMapObj secretsstorev1.ConfigMapObject, templateNames sets.String) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if err := secretutil.ValidateConfigMapObject(configMapObj); err != nil {
+		if len(configMapObj.ConfigMapName) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("configMapName"), err.Error()))
+		} else {
+			allErrs = append(allErrs, field.Required(path.Child("data"), err.Error()))
+		}
+	}
+	if len(configMapObj.ConfigMapName) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(configMapObj.ConfigMapName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("configMapName"), configMapObj.ConfigMapName, msg))
+		}
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(configMapObj.Labels, path.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(configMapObj.Annotations, path.Child("annotations"))...)
+
+	errs, _ := validateData(path.Child("data"), configMapObj.Data, templateNames)
+	return append(allErrs, errs...)
+}
+
+// validateData validates the data of a secret or configmap object and returns the keys
+// of the data. Each data must reference either an object or a defined template, and the
+// keys must be unique valid keys.
+func validateData(path *field.Path, objData []*secretsstorev1.SecretObjectData, templateNames sets.String) (field.ErrorList, sets.String) {
+	var allErrs field.ErrorList
+	keys := sets.NewString()
+	for j, data := range objData {
+		dataPath := path.Index(j)
+		if data == nil {
+			allErrs = append(allErrs, field.Required(dataPath, "data is empty"))
+			continue
+		}
+		objectName, templateName := strings.TrimSpace(data.ObjectName), strings.TrimSpace(data.Template)
+		switch {
+		case len(objectName) == 0 && len(templateName) == 0:
+			allErrs = append(allErrs, field.Required(dataPath.Child("objectName"), "object name in data is empty"))
+		case len(objectName) > 0 && len(templateName) > 0:
+			allErrs = append(allErrs, field.Invalid(dataPath.Child("template"), data.Template, "objectName and template are mutually exclusive"))
+		case len(templateName) > 0 && !templateNames.Has(templateName):
+			allErrs = append(allErrs, field.NotFound(dataPath.Child("template"), data.Template))
+		}
+		key := strings.TrimSpace(data.Key)
+		if len(key) == 0 {
+			allErrs = append(allErrs, field.Required(dataPath.Child("key"), "key in data is empty"))
+			continue
+		}
+		for _, msg := range validation.IsConfigMapKey(key) {
+			allErrs = append(allErrs, field.Invalid(dataPath.Child("key"), key, msg))
+		}
+		if keys.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(dataPath.Child("key"), key))
+		}
+		keys.Insert(key)
+		allErrs = append(allErrs, validateTransforms(dataPath.Child("transforms"), data.Transforms)...)
+	}
+	return allErrs, keys
+}
+
+// validateTransforms validates that the transforms have the parameters required by their type
+func validateTransforms(path *field.Path, transforms []secretsstorev1.DataTransform) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, t := range transforms {
+		if err := transformutil.Validate(t); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), t.Type, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsstore
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+func TestValidateSecretProviderClass(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             secretsstorev1.SecretProviderClassSpec
+		expectedErrs     []string
+		expectedWarnings int
+	}{
+		{
+			name: "valid spc",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "secret1",
+						Type:       "Opaque",
+						Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "key1"}},
+					},
+					{
+						SecretName: "tls",
+						Type:       "kubernetes.io/tls",
+						Data: []*secretsstorev1.SecretObjectData{
+							{ObjectName: "cert", Key: "tls.crt"},
+							{ObjectName: "cert", Key: "tls.key"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:         "provider not set",
+			spec:         secretsstorev1.SecretProviderClassSpec{},
+			expectedErrs: []string{"spec.provider"},
+		},
+		{
+			name:         "invalid provider name",
+			spec:         secretsstorev1.SecretProviderClassSpec{Provider: "provider/1"},
+			expectedErrs: []string{"spec.provider"},
+		},
+		{
+			name: "secret object missing type and data",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{SecretName: "secret1"},
+					{SecretName: "secret2", Type: "Opaque"},
+				},
+			},
+			expectedErrs: []string{"spec.secretObjects[0].type", "spec.secretObjects[1].data"},
+		},
+		{
+			name: "duplicate secret name and key",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "secret1",
+						Type:       "Opaque",
+						Data: []*secretsstorev1.SecretObjectData{
+							{ObjectName: "obj1", Key: "key1"},
+							{ObjectName: "obj2", Key: "key1"},
+						},
+					},
+					{
+						SecretName: "secret1",
+						Type:       "Opaque",
+						Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "key1"}},
+					},
+				},
+			},
+			expectedErrs: []string{"spec.secretObjects[0].data[1].key", "spec.secretObjects[1].secretName"},
+		},
+		{
+			name: "invalid secret name, key and labels",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "Secret_1",
+						Type:       "Opaque",
+						Labels:     map[string]string{"invalid key": "value"},
+						Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "", Key: "key/1"}},
+					},
+				},
+			},
+			expectedErrs: []string{
+				"spec.secretObjects[0].secretName",
+				"spec.secretObjects[0].labels",
+				"spec.secretObjects[0].data[0].objectName",
+				"spec.secretObjects[0].data[0].key",
+			},
+		},
+		{
+			name: "tls secret with unsupported keys",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "tls",
+						Type:       "kubernetes.io/tls",
+						Data: []*secretsstorev1.SecretObjectData{
+							{ObjectName: "cert", Key: "tls.crt"},
+							{ObjectName: "cert", Key: "cert.pem"},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{"spec.secretObjects[0].data[1].key", "spec.secretObjects[0].data"},
+		},
+		{
+			name: "invalid transforms",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "secret1",
+						Type:       "Opaque",
+						Data: []*secretsstorev1.SecretObjectData{
+							{
+								ObjectName: "obj1",
+								Key:        "key1",
+								Transforms: []secretsstorev1.DataTransform{
+									{Type: secretsstorev1.DataTransformBase64Decode},
+									{Type: secretsstorev1.DataTransformJSONPath},
+								},
+							},
+						},
+					},
+				},
+				FileTransforms: []*secretsstorev1.FileTransform{
+					{Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformYAMLKey}}},
+				},
+			},
+			expectedErrs: []string{
+				"spec.fileTransforms[0].path",
+				"spec.fileTransforms[0].transforms[0]",
+				"spec.secretObjects[0].data[0].transforms[1]",
+			},
+		},
+		{
+			name: "invalid templates",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				Templates: []*secretsstorev1.SecretTemplate{
+					{Name: "url", Template: "{{ .obj1 }}", Path: "url"},
+					{Name: "url", Template: "{{ .obj1 ", Path: "../url"},
+				},
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "secret1",
+						Type:       "Opaque",
+						Data: []*secretsstorev1.SecretObjectData{
+							{Template: "url", Key: "key1"},
+							{Template: "missing", Key: "key2"},
+							{ObjectName: "obj1", Template: "url", Key: "key3"},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				"spec.templates[1].name",
+				"spec.templates[1].template",
+				"spec.templates[1].path",
+				"spec.secretObjects[0].data[1].template",
+				"spec.secretObjects[0].data[2].template",
+			},
+		},
+		{
+			name: "invalid configmap objects",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				ConfigMapObjects: []*secretsstorev1.ConfigMapObject{
+					{
+						ConfigMapName: "ca",
+						Data: []*secretsstorev1.SecretObjectData{
+							{ObjectName: "ca", Key: "ca.crt"},
+							{ObjectName: "ca", Key: "ca.crt"},
+						},
+					},
+					{ConfigMapName: "ca"},
+					{Data: []*secretsstorev1.SecretObjectData{{Template: "missing", Key: "key1"}}},
+				},
+			},
+			expectedErrs: []string{
+				"spec.configMapObjects[0].data[1].key",
+				"spec.configMapObjects[1].data",
+				"spec.configMapObjects[1].configMapName",
+				"spec.configMapObjects[2].configMapName",
+				"spec.configMapObjects[2].data[0].template",
+			},
+		},
+		{
+			name: "invalid parameter placeholders",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				Parameters: map[string]string{
+					"path": "/teams/${pod.namespace}/db-password",
+					"node": "${pod.nodeName}",
+				},
+			},
+			expectedErrs: []string{"spec.parameters[node]"},
+		},
+		{
+			name: "invalid parametersFrom",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				ParametersFrom: []*secretsstorev1.ParametersFromSource{
+					{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+					{},
+					{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}, SecretRef: &secretsstorev1.ParametersFromReference{Name: "vault-role"}},
+					{SecretRef: &secretsstorev1.ParametersFromReference{}},
+				},
+			},
+			expectedErrs: []string{
+				"spec.parametersFrom[1]",
+				"spec.parametersFrom[2]",
+				"spec.parametersFrom[3].secretRef.name",
+			},
+		},
+		{
+			name:         "invalid default mode",
+			spec:         secretsstorev1.SecretProviderClassSpec{Provider: "provider1", DefaultMode: int32Ptr(01000)},
+			expectedErrs: []string{"spec.defaultMode"},
+		},
+		{
+			name: "unsupported secret type",
+			spec: secretsstorev1.SecretProviderClassSpec{
+				Provider: "provider1",
+				SecretObjects: []*secretsstorev1.SecretObject{
+					{
+						SecretName: "secret1",
+						Type:       "example.com/custom",
+						Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "key1"}},
+					},
+				},
+			},
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spc := &secretsstorev1.SecretProviderClass{Spec: test.spec}
+			errs, warnings := ValidateSecretProviderClass(spc)
+			if len(errs) != len(test.expectedErrs) {
+				t.Fatalf("expected %d errors, got %v", len(test.expectedErrs), errs)
+			}
+			for i, e := range test.expectedErrs {
+				if errs[i].Field != e {
+					t.Errorf("expected error for field %s, got %v", e, errs[i])
+				}
+			}
+			if len(warnings) != test.expectedWarnings {
+				t.Errorf("expected %d warnings, got %v", test.expectedWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateSecretProviderClassErrorType(t *testing.T) {
+	spc := &secretsstorev1.SecretProviderClass{
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider: "provider1",
+			SecretObjects: []*secretsstorev1.SecretObject{
+				{SecretName: "secret1", Type: "Opaque"},
+				{SecretName: "secret1", Type: "Opaque"},
+			},
+		},
+	}
+	errs, _ := ValidateSecretProviderClass(spc)
+	expected := []field.ErrorType{field.ErrorTypeRequired, field.ErrorTypeRequired, field.ErrorTypeDuplicate}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range expected {
+		if errs[i].Type != e {
+			t.Errorf("expected error type %s, got %v", e, errs[i])
+		}
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestValidateClusterSecretProviderClass(t *testing.T) {
+	cspc := &secretsstorev1.ClusterSecretProviderClass{
+		Spec: secretsstorev1.ClusterSecretProviderClassSpec{
+			SecretProviderClassSpec: secretsstorev1.SecretProviderClassSpec{Provider: "provider1"},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "a"},
+			},
+		},
+	}
+	if errs, _ := ValidateClusterSecretProviderClass(cspc); len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	cspc.Spec.Provider = ""
+	cspc.Spec.NamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "a b"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "env", Operator: metav1.LabelSelectorOpIn},
+			{Key: "tier", Operator: "Matches", Values: []string{"web"}},
+		},
+	}
+	errs, _ := ValidateClusterSecretProviderClass(cspc)
+	fields := make(map[string]bool)
+	for _, err := range errs {
+		fields[err.Field] = true
+	}
+	for _, f := range []string{
+		"spec.provider",
+		"spec.namespaceSelector.matchLabels",
+		"spec.namespaceSelector.matchExpressions[0].values",
+		"spec.namespaceSelector.matchExpressions[1].operator",
+	} {
+		if !fields[f] {
+			t.Errorf("expected error for field %s, got %v", f, errs)
+		}
+	}
+}
@@ -65,9 +65,10 @@ type AtomicWriter struct {
 
 // FileProjection contains file Data and access Mode
 type FileProjection struct {
-	Data   []byte
-	Mode   int32
-	FsUser *int64
+	Data    []byte
+	Mode    int32
+	FsUser  *int64
+	FsGroup *int64
 }
 
 // NewAtomicWriter creates a new AtomicWriter configured to write to the given
@@ -399,11 +400,18 @@ func (w *AtomicWriter) writePayloadToDir(payload map[string]FileProjection, dir
 			return err
 		}
 
-		if fileProjection.FsUser == nil {
+		if fileProjection.FsUser == nil && fileProjection.FsGroup == nil {
 			continue
 		}
-		if err := os.Chown(fullPath, int(*fileProjection.FsUser), -1); err != nil {
-			klog.Errorf("%s: unable to change file %s with owner %v: %v", w.logContext, fullPath, int(*fileProjection.FsUser), err)
+		uid, gid := -1, -1
+		if fileProjection.FsUser != nil {
+			uid = int(*fileProjection.FsUser)
+		}
+		if fileProjection.FsGroup != nil {
+			gid = int(*fileProjection.FsGroup)
+		}
+		if err := os.Chown(fullPath, uid, gid); err != nil {
+			klog.Errorf("%s: unable to change file %s with owner %v and group %v: %v", w.logContext, fullPath, uid, gid, err)
 			return err
 		}
 	}
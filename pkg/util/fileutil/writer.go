@@ -37,8 +37,8 @@ func Validate(payloads []*v1alpha1.File) error {
 
 // WritePayloads writes the files to target directory. This helper builds the
 // atomic writer and converts the v1alpha1.File proto to the FileProjection type
-// used by the atomic writer.
-func WritePayloads(path string, payloads []*v1alpha1.File) error {
+// used by the atomic writer. The files are owned by fsGroup if it's set.
+func WritePayloads(path string, payloads []*v1alpha1.File, fsGroup *int64) error {
 	// cleanup any payload paths that may have been written by a previous
 	// version of the driver/provider.
 	if err := cleanupProviderFiles(path, payloads); err != nil {
@@ -54,8 +54,9 @@ func WritePayloads(path string, payloads []*v1alpha1.File) error {
 	files := make(map[string]FileProjection, len(payloads))
 	for _, payload := range payloads {
 		files[payload.GetPath()] = FileProjection{
-			Data: payload.GetContents(),
-			Mode: payload.GetMode(),
+			Data:    payload.GetContents(),
+			Mode:    payload.GetMode(),
+			FsGroup: fsGroup,
 		}
 	}
 
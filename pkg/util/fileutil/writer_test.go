@@ -372,7 +372,7 @@ func TestWritePayloads(t *testing.T) {
 			dir := tmpdir.New(t, "", "ut")
 
 			// check that the first write succeeds and the contents match
-			if err := WritePayloads(dir, tc.first); err != nil {
+			if err := WritePayloads(dir, tc.first, nil); err != nil {
 				t.Errorf("WritePayload(first) got error: %v", err)
 			}
 
@@ -382,7 +382,7 @@ func TestWritePayloads(t *testing.T) {
 
 			// check that the second write succeeds and the contents match,
 			// ensuring that the files have the updated values
-			if err := WritePayloads(dir, tc.second); err != nil {
+			if err := WritePayloads(dir, tc.second, nil); err != nil {
 				t.Errorf("WritePayload(second) got error: %v", err)
 			}
 
@@ -421,7 +421,7 @@ func TestWritePayloads_BackwardCompatible(t *testing.T) {
 
 	want := []byte("new")
 
-	if err := WritePayloads(dir, payload); err != nil {
+	if err := WritePayloads(dir, payload, nil); err != nil {
 		t.Fatalf("could not write new file: %s", err)
 	}
 
@@ -435,6 +435,29 @@ func TestWritePayloads_BackwardCompatible(t *testing.T) {
 	}
 }
 
+func TestWritePayloads_FsGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+	dir := tmpdir.New(t, "", "ut")
+
+	payload := []*v1alpha1.File{
+		{
+			Path:     "foo.txt",
+			Mode:     0440,
+			Contents: []byte("foo"),
+		},
+	}
+	// the current group can always be set as the file group
+	fsGroup := int64(os.Getgid())
+	if err := WritePayloads(dir, payload, &fsGroup); err != nil {
+		t.Fatalf("could not write file with fsGroup: %s", err)
+	}
+	if err := readPayloads(dir, payload); err != nil {
+		t.Errorf("could not read payload: %s", err)
+	}
+}
+
 func readPayloads(path string, payloads []*v1alpha1.File) error {
 	for _, p := range payloads {
 		fp := filepath.Join(path, p.Path)
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var (
+	// placeholderRe matches the placeholders in parameter values, e.g. ${pod.namespace}, and
+	// the escaped $${ that's expanded to a literal ${
+	placeholderRe = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+	// mapPlaceholderRe matches the placeholders of pod labels and annotations, e.g. ${pod.labels['app']}
+	mapPlaceholderRe = regexp.MustCompile(`^pod\.(labels|annotations)\[(?:'([^']+)'|"([^"]+)")\]$`)
+)
+
+// ExpandParameters returns a copy of the secret provider class parameters with the placeholders
+// in the values replaced with the pod metadata. The supported placeholders are ${pod.name},
+// ${pod.namespace} (or ${namespace}), ${pod.uid}, ${pod.serviceAccountName}, ${pod.labels['key']}
+// and ${pod.annotations['key']}. $${ is expanded to a literal ${. An error is returned for unknown
+// placeholders and labels or annotations that aren't set on the pod, so an object isn't fetched
+// from an unexpected path.
+func ExpandParameters(parameters map[string]string, pod *v1.Pod) (map[string]string, error) {
+	expanded := make(map[string]string, len(parameters))
+	for k, v := range parameters {
+		value, err := expand(v, pod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand parameter %s, err: %w", k, err)
+		}
+		expanded[k] = value
+	}
+	return expanded, nil
+}
+
+// ValidateParameter returns an error if the parameter value has invalid placeholders
+func ValidateParameter(value string) error {
+	for _, m := range placeholderRe.FindAllStringSubmatch(value, -1) {
+		if m[0] == "$${" {
+			continue
+		}
+		if _, err := placeholderValue(m[1], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expand(value string, pod *v1.Pod) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var err error
+	expanded := placeholderRe.ReplaceAllStringFunc(value, func(m string) string {
+		if m == "$${" {
+			return "${"
+		}
+		v, e := placeholderValue(strings.TrimSuffix(strings.TrimPrefix(m, "${"), "}"), pod)
+		if e != nil && err == nil {
+			err = e
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// placeholderValue returns the value of the placeholder for the pod. The placeholder is
+// only validated when the pod is nil.
+func placeholderValue(placeholder string, pod *v1.Pod) (string, error) {
+	placeholder = strings.TrimSpace(placeholder)
+	if m := mapPlaceholderRe.FindStringSubmatch(placeholder); m != nil {
+		if pod == nil {
+			return "", nil
+		}
+		key := m[2] + m[3]
+		values := pod.Labels
+		if m[1] == "annotations" {
+			values = pod.Annotations
+		}
+		v, ok := values[key]
+		if !ok {
+			return "", fmt.Errorf("%s %q is not set on pod %s/%s", strings.TrimSuffix(m[1], "s"), key, pod.Namespace, pod.Name)
+		}
+		return v, nil
+	}
+
+	var value func(*v1.Pod) string
+	switch placeholder {
+	case "pod.name":
+		value = func(p *v1.Pod) string { return p.Name }
+	case "pod.namespace", "namespace":
+		value = func(p *v1.Pod) string { return p.Namespace }
+	case "pod.uid":
+		value = func(p *v1.Pod) string { return string(p.UID) }
+	case "pod.serviceAccountName":
+		value = func(p *v1.Pod) string { return p.Spec.ServiceAccountName }
+	default:
+		return "", fmt.Errorf("unknown placeholder ${%s}", placeholder)
+	}
+	if pod == nil {
+		return "", nil
+	}
+	return value(pod), nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExpandParameters(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod1",
+			Namespace:   "team1",
+			UID:         "uid1",
+			Labels:      map[string]string{"app": "web"},
+			Annotations: map[string]string{"example.com/env": "prod"},
+		},
+		Spec: v1.PodSpec{ServiceAccountName: "sa1"},
+	}
+
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		want       map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "no placeholders",
+			parameters: map[string]string{"objects": "array:\n  - objectName: $secret"},
+			want:       map[string]string{"objects": "array:\n  - objectName: $secret"},
+		},
+		{
+			name: "pod metadata",
+			parameters: map[string]string{
+				"path":    "/teams/${namespace}/db-password",
+				"role":    "${pod.namespace}-${pod.serviceAccountName}",
+				"pod":     "${pod.name}/${pod.uid}",
+				"app":     "${pod.labels['app']}",
+				"env":     `${pod.annotations["example.com/env"]}`,
+				"escaped": "$${pod.name}",
+			},
+			want: map[string]string{
+				"path":    "/teams/team1/db-password",
+				"role":    "team1-sa1",
+				"pod":     "pod1/uid1",
+				"app":     "web",
+				"env":     "prod",
+				"escaped": "${pod.name}",
+			},
+		},
+		{
+			name:       "label not set",
+			parameters: map[string]string{"app": "${pod.labels['tier']}"},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown placeholder",
+			parameters: map[string]string{"node": "${pod.nodeName}"},
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ExpandParameters(test.parameters, pod)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ExpandParameters() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateParameter(t *testing.T) {
+	for _, value := range []string{"plain", "$${escaped}", "/teams/${pod.namespace}/${pod.labels['app']}"} {
+		if err := ValidateParameter(value); err != nil {
+			t.Errorf("expected %q to be valid, got %v", value, err)
+		}
+	}
+	for _, value := range []string{"${pod.nodeName}", "${pod.labels[app]}", "${}"} {
+		if err := ValidateParameter(value); err == nil {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
@@ -19,12 +19,37 @@ limitations under the License.
 package k8sutil
 
 import (
+	"fmt"
+	"strconv"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
 	v1 "k8s.io/api/core/v1"
 )
 
+const (
+	// SecretProviderClassAttribute is the volume attribute that references a SecretProviderClass
+	SecretProviderClassAttribute = "secretProviderClass"
+	// ClusterSecretProviderClassAttribute is the volume attribute that references a ClusterSecretProviderClass
+	ClusterSecretProviderClassAttribute = "clusterSecretProviderClass"
+	// FSGroupAttribute is the volume attribute that sets the group owning the files in the mount
+	FSGroupAttribute = "fsGroup"
+)
+
 // SPCVolume finds the Secret Provider Class volume from a Pod, or returns nil
 // if a volume could not be found.
 func SPCVolume(pod *v1.Pod, spcName string) *v1.Volume {
+	return SPCVolumeForKind(pod, "", spcName)
+}
+
+// SPCVolumeForKind finds the volume from a Pod that references the Secret Provider
+// Class of the given kind, or returns nil if a volume could not be found. An empty
+// kind is a SecretProviderClass.
+func SPCVolumeForKind(pod *v1.Pod, spcKind, spcName string) *v1.Volume {
+	attribute := SecretProviderClassAttribute
+	if spcKind == secretsstorev1.ClusterSecretProviderClassKind {
+		attribute = ClusterSecretProviderClassAttribute
+	}
 	for i, vol := range pod.Spec.Volumes {
 		if vol.CSI == nil {
 			continue
@@ -32,10 +57,28 @@ func SPCVolume(pod *v1.Pod, spcName string) *v1.Volume {
 		if vol.CSI.Driver != "secrets-store.csi.k8s.io" {
 			continue
 		}
-		if vol.CSI.VolumeAttributes["secretProviderClass"] != spcName {
+		if vol.CSI.VolumeAttributes[attribute] != spcName {
 			continue
 		}
 		return &pod.Spec.Volumes[i]
 	}
 	return nil
 }
+
+// FSGroup returns the group that owns the files in the mount. The fsGroup volume
+// attribute takes precedence over the fsGroup in the pod security context. Returns
+// nil if neither is set. The pod may be nil if it isn't known.
+func FSGroup(pod *v1.Pod, volumeAttributes map[string]string) (*int64, error) {
+	if fsGroup, ok := volumeAttributes[FSGroupAttribute]; ok && fsGroup != "" {
+		gid, err := strconv.ParseInt(fsGroup, 10, 64)
+		if err != nil || gid < 0 {
+			return nil, fmt.Errorf("invalid %s volume attribute %q, must be a non-negative integer", FSGroupAttribute, fsGroup)
+		}
+		return &gid, nil
+	}
+	if pod != nil && pod.Spec.SecurityContext != nil && pod.Spec.SecurityContext.FSGroup != nil {
+		gid := *pod.Spec.SecurityContext.FSGroup
+		return &gid, nil
+	}
+	return nil, nil
+}
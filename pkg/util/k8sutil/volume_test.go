@@ -166,3 +166,104 @@ func TestSPCVolume(t *testing.T) {
 		})
 	}
 }
+
+func TestSPCVolumeForKind(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{
+				{
+					Name: "spc-volume",
+					VolumeSource: v1.VolumeSource{
+						CSI: &v1.CSIVolumeSource{
+							Driver:           "secrets-store.csi.k8s.io",
+							VolumeAttributes: map[string]string{"secretProviderClass": "spc1"},
+						},
+					},
+				},
+				{
+					Name: "cluster-spc-volume",
+					VolumeSource: v1.VolumeSource{
+						CSI: &v1.CSIVolumeSource{
+							Driver:           "secrets-store.csi.k8s.io",
+							VolumeAttributes: map[string]string{"clusterSecretProviderClass": "spc1"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spcKind string
+		want    string
+	}{
+		{name: "secret provider class", spcKind: "", want: "spc-volume"},
+		{name: "secret provider class kind", spcKind: "SecretProviderClass", want: "spc-volume"},
+		{name: "cluster secret provider class", spcKind: "ClusterSecretProviderClass", want: "cluster-spc-volume"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SPCVolumeForKind(pod, tc.spcKind, "spc1")
+			if got == nil || got.Name != tc.want {
+				t.Errorf("SPCVolumeForKind() = %v, want volume %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFSGroup(t *testing.T) {
+	podGroup := int64(2000)
+	podWithFSGroup := &v1.Pod{
+		Spec: v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{FSGroup: &podGroup},
+		},
+	}
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		attributes map[string]string
+		want       *int64
+		wantErr    bool
+	}{
+		{
+			name: "not set",
+			pod:  &v1.Pod{},
+			want: nil,
+		},
+		{
+			name: "pod unknown",
+			pod:  nil,
+			want: nil,
+		},
+		{
+			name: "pod fsGroup",
+			pod:  podWithFSGroup,
+			want: &podGroup,
+		},
+		{
+			name:       "volume attribute takes precedence",
+			pod:        podWithFSGroup,
+			attributes: map[string]string{FSGroupAttribute: "1000"},
+			want:       func() *int64 { g := int64(1000); return &g }(),
+		},
+		{
+			name:       "invalid volume attribute",
+			pod:        podWithFSGroup,
+			attributes: map[string]string{FSGroupAttribute: "-1"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FSGroup(tc.pod, tc.attributes)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("FSGroup() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FSGroup() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
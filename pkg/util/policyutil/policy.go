@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policyutil holds Secrets CSI Driver utilities for enforcing the
+// SecretsStorePolicy in a namespace before the provider is called.
+package policyutil
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ViolationError is returned when a secrets store policy doesn't allow the
+// secret provider class or the parameters
+type ViolationError struct {
+	// Namespace of the policy
+	Namespace string
+	// Name of the policy
+	Name string
+	// Reason the policy doesn't allow the mount
+	Reason string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("secrets store policy %s/%s: %s", e.Namespace, e.Name, e.Reason)
+}
+
+// Enforce checks the secret provider class and the parameters sent to the provider against the
+// secrets store policies in the namespace. It returns a *ViolationError for the first policy that
+// doesn't allow them, or an error if the policies can't be listed. Everything is allowed if there
+// are no policies in the namespace, or if the SecretsStorePolicy CRD isn't installed.
+func Enforce(ctx context.Context, reader client.Reader, namespace string, spc *secretsstorev1.SecretProviderClass, parameters map[string]string) error {
+	policies := &secretsstorev1.SecretsStorePolicyList{}
+	if err := reader.List(ctx, policies, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list secrets store policies in namespace %s, err: %w", namespace, err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if reason := check(&policy.Spec, spc, parameters); reason != "" {
+			return &ViolationError{Namespace: policy.Namespace, Name: policy.Name, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// check returns the reason the policy doesn't allow the secret provider class and the
+// parameters, or an empty string if they're allowed. The values of the parameters aren't
+// in the reason, as they can be read from secrets with parametersFrom.
+func check(policy *secretsstorev1.SecretsStorePolicySpec, spc *secretsstorev1.SecretProviderClass, parameters map[string]string) string {
+	if len(policy.AllowedProviders) > 0 && !sets.NewString(policy.AllowedProviders...).Has(string(spc.Spec.Provider)) {
+		return fmt.Sprintf("provider %q is not allowed", spc.Spec.Provider)
+	}
+	if policy.AllowSecretSync != nil && !*policy.AllowSecretSync {
+		if len(spc.Spec.SecretObjects) > 0 {
+			return fmt.Sprintf("secret sync with secretObjects in secret provider class %s is not allowed", spc.Name)
+		}
+		if len(spc.Spec.ConfigMapObjects) > 0 {
+			return fmt.Sprintf("configmap sync with configMapObjects in secret provider class %s is not allowed", spc.Name)
+		}
+	}
+	for _, c := range policy.Parameters {
+		value, ok := parameters[c.Key]
+		if !ok {
+			if c.Required {
+				return fmt.Sprintf("parameter %q is required", c.Key)
+			}
+			continue
+		}
+		if len(c.AllowedValues) > 0 && !sets.NewString(c.AllowedValues...).Has(value) {
+			return fmt.Sprintf("value of parameter %q is not in the allowed values", c.Key)
+		}
+		if len(c.Pattern) > 0 {
+			// the pattern must match the whole value, so a role name pattern like team1-.* can't
+			// be bypassed with a value that only contains a match
+			re, err := regexp.Compile("^(?:" + c.Pattern + ")$")
+			if err != nil {
+				return fmt.Sprintf("pattern of parameter %q is invalid: %v", c.Key, err)
+			}
+			if !re.MatchString(value) {
+				return fmt.Sprintf("value of parameter %q doesn't match pattern %q", c.Key, c.Pattern)
+			}
+		}
+	}
+	return ""
+}
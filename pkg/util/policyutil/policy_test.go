@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnforce(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	allowSecretSync := false
+	c := fake.NewFakeClientWithScheme(scheme,
+		&secretsstorev1.SecretsStorePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "providers", Namespace: "team1"},
+			Spec:       secretsstorev1.SecretsStorePolicySpec{AllowedProviders: []string{"vault"}},
+		},
+		&secretsstorev1.SecretsStorePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "team1"},
+			Spec: secretsstorev1.SecretsStorePolicySpec{
+				Parameters: []secretsstorev1.ParameterConstraint{
+					{Key: "roleName", Pattern: "team1-.*", Required: true},
+					{Key: "vaultAddress", AllowedValues: []string{"https://vault:8200"}},
+				},
+				AllowSecretSync: &allowSecretSync,
+			},
+		},
+	)
+
+	tests := []struct {
+		name             string
+		namespace        string
+		provider         string
+		parameters       map[string]string
+		secretObjects    []*secretsstorev1.SecretObject
+		configMapObjects []*secretsstorev1.ConfigMapObject
+		wantViolation    bool
+	}{
+		{
+			name:       "allowed",
+			namespace:  "team1",
+			provider:   "vault",
+			parameters: map[string]string{"roleName": "team1-web", "vaultAddress": "https://vault:8200"},
+		},
+		{
+			name:       "no policies in namespace",
+			namespace:  "team2",
+			provider:   "azure",
+			parameters: map[string]string{"roleName": "team1-web"},
+		},
+		{
+			name:          "provider not allowed",
+			namespace:     "team1",
+			provider:      "azure",
+			parameters:    map[string]string{"roleName": "team1-web"},
+			wantViolation: true,
+		},
+		{
+			name:          "required parameter not set",
+			namespace:     "team1",
+			provider:      "vault",
+			parameters:    map[string]string{},
+			wantViolation: true,
+		},
+		{
+			name:          "parameter doesn't match the whole pattern",
+			namespace:     "team1",
+			provider:      "vault",
+			parameters:    map[string]string{"roleName": "team2-web,team1-web"},
+			wantViolation: true,
+		},
+		{
+			name:          "parameter value not allowed",
+			namespace:     "team1",
+			provider:      "vault",
+			parameters:    map[string]string{"roleName": "team1-web", "vaultAddress": "https://other:8200"},
+			wantViolation: true,
+		},
+		{
+			name:          "secret sync not allowed",
+			namespace:     "team1",
+			provider:      "vault",
+			parameters:    map[string]string{"roleName": "team1-web"},
+			secretObjects: []*secretsstorev1.SecretObject{{SecretName: "secret1"}},
+			wantViolation: true,
+		},
+		{
+			name:             "configmap sync not allowed",
+			namespace:        "team1",
+			provider:         "vault",
+			parameters:       map[string]string{"roleName": "team1-web"},
+			configMapObjects: []*secretsstorev1.ConfigMapObject{{ConfigMapName: "configmap1"}},
+			wantViolation:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spc := &secretsstorev1.SecretProviderClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: test.namespace},
+				Spec: secretsstorev1.SecretProviderClassSpec{
+					Provider:         secretsstorev1.Provider(test.provider),
+					SecretObjects:    test.secretObjects,
+					ConfigMapObjects: test.configMapObjects,
+				},
+			}
+			err := Enforce(context.TODO(), c, test.namespace, spc, test.parameters)
+			var violation *ViolationError
+			if got := errors.As(err, &violation); got != test.wantViolation {
+				t.Fatalf("expected violation: %v, got: %v", test.wantViolation, err)
+			}
+		})
+	}
+}
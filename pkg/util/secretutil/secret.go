@@ -27,8 +27,11 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/templateutil"
+	"sigs.k8s.io/secrets-store-csi-driver/pkg/util/transformutil"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -146,7 +149,7 @@ func GetSecretType(sType string) corev1.SecretType {
 
 // ValidateSecretObject performs basic validation of the secret provider class
 // secret object to check if the mandatory fields - name, type and data are defined
-func ValidateSecretObject(secretObj v1alpha1.SecretObject) error {
+func ValidateSecretObject(secretObj secretsstorev1.SecretObject) error {
 	if len(secretObj.SecretName) == 0 {
 		return fmt.Errorf("secret name is empty")
 	}
@@ -159,33 +162,69 @@ func ValidateSecretObject(secretObj v1alpha1.SecretObject) error {
 	return nil
 }
 
+// ValidateConfigMapObject performs basic validation of the secret provider class
+// configmap object to check if the mandatory fields - name and data are defined
+func ValidateConfigMapObject(configMapObj secretsstorev1.ConfigMapObject) error {
+	if len(configMapObj.ConfigMapName) == 0 {
+		return fmt.Errorf("configmap name is empty")
+	}
+	if len(configMapObj.Data) == 0 {
+		return fmt.Errorf("data is empty")
+	}
+	return nil
+}
+
 // GetSecretData gets the object contents from the pods target path and returns a
-// map that will be populated in the Kubernetes secret data field
-func GetSecretData(secretObjData []*v1alpha1.SecretObjectData, secretType corev1.SecretType, files map[string]string) (map[string][]byte, error) {
+// map that will be populated in the Kubernetes secret data field. Data that references
+// a template is rendered with the mounted objects.
+func GetSecretData(secretObjData []*secretsstorev1.SecretObjectData, secretType corev1.SecretType, files map[string]string, templates []*secretsstorev1.SecretTemplate) (map[string][]byte, error) {
 	datamap := make(map[string][]byte)
+	var objects map[string]string
 	for _, data := range secretObjData {
 		objectName := strings.TrimSpace(data.ObjectName)
+		templateName := strings.TrimSpace(data.Template)
 		dataKey := strings.TrimSpace(data.Key)
 
-		if len(objectName) == 0 {
+		if len(objectName) == 0 && len(templateName) == 0 {
 			return datamap, fmt.Errorf("object name in secretObjects.data")
 		}
 		if len(dataKey) == 0 {
 			return datamap, fmt.Errorf("key in secretObjects.data is empty")
 		}
-		file, ok := files[objectName]
-		if !ok {
-			return datamap, fmt.Errorf("file matching objectName %s not found in the pod", objectName)
+
+		var content []byte
+		var err error
+		if len(templateName) > 0 {
+			t := templateutil.Find(templates, templateName)
+			if t == nil {
+				return datamap, fmt.Errorf("template %s not found in the secret provider class", templateName)
+			}
+			if objects == nil {
+				if objects, err = templateutil.ReadObjects(files, templates); err != nil {
+					return datamap, err
+				}
+			}
+			if content, err = templateutil.Render(t, objects); err != nil {
+				return datamap, err
+			}
+			objectName = templateName
+		} else {
+			file, ok := files[objectName]
+			if !ok {
+				return datamap, fmt.Errorf("file matching objectName %s not found in the pod", objectName)
+			}
+			if content, err = os.ReadFile(file); err != nil {
+				return datamap, fmt.Errorf("failed to read file %s, err: %v", objectName, err)
+			}
 		}
-		content, err := os.ReadFile(file)
-		if err != nil {
-			return datamap, fmt.Errorf("failed to read file %s, err: %v", objectName, err)
+		if content, err = transformutil.Apply(content, data.Transforms); err != nil {
+			return datamap, fmt.Errorf("failed to transform file %s, err: %+v", objectName, err)
 		}
 		datamap[dataKey] = content
 		if secretType == v1.SecretTypeTLS {
 			c, err := GetCertPart(content, dataKey)
 			if err != nil {
-				return datamap, fmt.Errorf("failed to get cert data from file %s, err: %+v", file, err)
+				return datamap, fmt.Errorf("failed to get cert data from %s, err: %+v", objectName, err)
 			}
 			datamap[dataKey] = c
 		}
@@ -193,6 +232,26 @@ func GetSecretData(secretObjData []*v1alpha1.SecretObjectData, secretType corev1
 	return datamap, nil
 }
 
+// GetConfigMapData gets the object contents from the pods target path in the same way as
+// GetSecretData and splits them into the configmap data and binaryData fields. Content that
+// isn't valid UTF-8 can't be stored in the data field and is returned in binaryData.
+func GetConfigMapData(configMapObjData []*secretsstorev1.SecretObjectData, files map[string]string, templates []*secretsstorev1.SecretTemplate) (map[string]string, map[string][]byte, error) {
+	datamap, err := GetSecretData(configMapObjData, corev1.SecretTypeOpaque, files, templates)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := make(map[string]string)
+	binaryData := make(map[string][]byte)
+	for k, v := range datamap {
+		if utf8.Valid(v) {
+			data[k] = string(v)
+		} else {
+			binaryData[k] = v
+		}
+	}
+	return data, binaryData, nil
+}
+
 // GetSHAFromSecret gets SHA for the secret data
 func GetSHAFromSecret(data map[string][]byte) (string, error) {
 	var values []string
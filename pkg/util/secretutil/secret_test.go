@@ -24,7 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
-	"sigs.k8s.io/secrets-store-csi-driver/apis/v1alpha1"
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -172,30 +172,30 @@ func TestGetCert(t *testing.T) {
 func TestValidateSecretObject(t *testing.T) {
 	tests := []struct {
 		name          string
-		secretObj     v1alpha1.SecretObject
+		secretObj     secretsstorev1.SecretObject
 		expectedError bool
 	}{
 		{
 			name:          "secret name is empty",
-			secretObj:     v1alpha1.SecretObject{},
+			secretObj:     secretsstorev1.SecretObject{},
 			expectedError: true,
 		},
 		{
 			name:          "secret type is empty",
-			secretObj:     v1alpha1.SecretObject{SecretName: "secret1"},
+			secretObj:     secretsstorev1.SecretObject{SecretName: "secret1"},
 			expectedError: true,
 		},
 		{
 			name:          "data is empty",
-			secretObj:     v1alpha1.SecretObject{SecretName: "secret1", Type: "Opaque"},
+			secretObj:     secretsstorev1.SecretObject{SecretName: "secret1", Type: "Opaque"},
 			expectedError: true,
 		},
 		{
 			name: "valid secret object",
-			secretObj: v1alpha1.SecretObject{
+			secretObj: secretsstorev1.SecretObject{
 				SecretName: "secret1",
 				Type:       "Opaque",
-				Data:       []*v1alpha1.SecretObjectData{{ObjectName: "obj1", Key: "file1"}}},
+				Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "file1"}}},
 			expectedError: false,
 		},
 	}
@@ -213,15 +213,16 @@ func TestValidateSecretObject(t *testing.T) {
 func TestGetSecretData(t *testing.T) {
 	tests := []struct {
 		name            string
-		secretObjData   []*v1alpha1.SecretObjectData
+		secretObjData   []*secretsstorev1.SecretObjectData
 		secretType      corev1.SecretType
 		currentFiles    map[string]string
+		templates       []*secretsstorev1.SecretTemplate
 		expectedDataMap map[string][]byte
 		expectedError   bool
 	}{
 		{
 			name: "object name not set",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "",
 				},
@@ -232,7 +233,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "key not set",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1",
 				},
@@ -243,7 +244,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "file matching object doesn't exist in map",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1",
 					Key:        "file1",
@@ -256,7 +257,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "file matching object doesn't exist in the fs",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1",
 					Key:        "file1",
@@ -269,7 +270,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "file matching object found in fs",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1",
 					Key:        "file1",
@@ -282,7 +283,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "file matching object found in fs after trimming spaces in object name",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1     ",
 					Key:        "file1",
@@ -295,7 +296,7 @@ func TestGetSecretData(t *testing.T) {
 		},
 		{
 			name: "file matching object found in fs after trimming spaces in key",
-			secretObjData: []*v1alpha1.SecretObjectData{
+			secretObjData: []*secretsstorev1.SecretObjectData{
 				{
 					ObjectName: "obj1     ",
 					Key:        "   file1",
@@ -306,6 +307,61 @@ func TestGetSecretData(t *testing.T) {
 			expectedDataMap: map[string][]byte{"file1": []byte("test")},
 			expectedError:   false,
 		},
+		{
+			name: "transforms applied to file content",
+			secretObjData: []*secretsstorev1.SecretObjectData{
+				{
+					ObjectName: "obj1",
+					Key:        "file1",
+					Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformBase64Decode}},
+				},
+			},
+			secretType:      corev1.SecretTypeOpaque,
+			currentFiles:    map[string]string{"obj1": ""},
+			expectedDataMap: map[string][]byte{"file1": {0xb5, 0xeb, 0x2d}},
+			expectedError:   false,
+		},
+		{
+			name: "template rendered with mounted objects",
+			secretObjData: []*secretsstorev1.SecretObjectData{
+				{
+					Template: "url",
+					Key:      "url",
+				},
+			},
+			secretType:      corev1.SecretTypeOpaque,
+			currentFiles:    map[string]string{"obj1": "", "url": ""},
+			templates:       []*secretsstorev1.SecretTemplate{{Name: "url", Template: "jdbc://{{ .obj1 }}@host", Path: "url"}},
+			expectedDataMap: map[string][]byte{"url": []byte("jdbc://test@host")},
+			expectedError:   false,
+		},
+		{
+			name: "template not found",
+			secretObjData: []*secretsstorev1.SecretObjectData{
+				{
+					Template: "url",
+					Key:      "url",
+				},
+			},
+			secretType:      corev1.SecretTypeOpaque,
+			currentFiles:    map[string]string{"obj1": ""},
+			expectedDataMap: make(map[string][]byte),
+			expectedError:   true,
+		},
+		{
+			name: "transform fails",
+			secretObjData: []*secretsstorev1.SecretObjectData{
+				{
+					ObjectName: "obj1",
+					Key:        "file1",
+					Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformJSONPath, JSONPath: "{.password}"}},
+				},
+			},
+			secretType:      corev1.SecretTypeOpaque,
+			currentFiles:    map[string]string{"obj1": ""},
+			expectedDataMap: make(map[string][]byte),
+			expectedError:   true,
+		},
 	}
 
 	for _, test := range tests {
@@ -323,7 +379,7 @@ func TestGetSecretData(t *testing.T) {
 				}
 				test.currentFiles[fileName] = filePath
 			}
-			datamap, err := GetSecretData(test.secretObjData, test.secretType, test.currentFiles)
+			datamap, err := GetSecretData(test.secretObjData, test.secretType, test.currentFiles, test.templates)
 			if test.expectedError && err == nil {
 				t.Fatalf("expected err: %+v, got: %+v", test.expectedError, err)
 			}
@@ -334,6 +390,73 @@ func TestGetSecretData(t *testing.T) {
 	}
 }
 
+func TestValidateConfigMapObject(t *testing.T) {
+	tests := []struct {
+		name          string
+		configMapObj  secretsstorev1.ConfigMapObject
+		expectedError bool
+	}{
+		{
+			name:          "configmap name is empty",
+			configMapObj:  secretsstorev1.ConfigMapObject{},
+			expectedError: true,
+		},
+		{
+			name:          "data is empty",
+			configMapObj:  secretsstorev1.ConfigMapObject{ConfigMapName: "configmap1"},
+			expectedError: true,
+		},
+		{
+			name: "valid configmap object",
+			configMapObj: secretsstorev1.ConfigMapObject{
+				ConfigMapName: "configmap1",
+				Data:          []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: "file1"}}},
+			expectedError: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateConfigMapObject(test.configMapObj)
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected err: %+v, got: %+v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGetConfigMapData(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ut")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	filePath, err := createTestFile(tmpDir, "obj1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	files := map[string]string{"obj1": filePath}
+
+	data, binaryData, err := GetConfigMapData([]*secretsstorev1.SecretObjectData{
+		{ObjectName: "obj1", Key: "text"},
+		{ObjectName: "obj1", Key: "binary", Transforms: []secretsstorev1.DataTransform{{Type: secretsstorev1.DataTransformBase64Decode}}},
+	}, files, nil)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got: %+v", err)
+	}
+	if !reflect.DeepEqual(data, map[string]string{"text": "test"}) {
+		t.Errorf("unexpected data: %v", data)
+	}
+	if !reflect.DeepEqual(binaryData, map[string][]byte{"binary": {0xb5, 0xeb, 0x2d}}) {
+		t.Errorf("unexpected binary data: %v", binaryData)
+	}
+
+	if _, _, err = GetConfigMapData([]*secretsstorev1.SecretObjectData{{ObjectName: "obj2", Key: "text"}}, files, nil); err == nil {
+		t.Errorf("expected error for object not found in the mount")
+	}
+}
+
 func createTestFile(tmpDir, fileName string) (string, error) {
 	if fileName != "" {
 		filePath := fmt.Sprintf("%s/%s", tmpDir, fileName)
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spcpsutil holds Secrets CSI Driver utilities for maintaining the
+// SecretProviderClassPodStatus status.
+package spcpsutil
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+)
+
+// maxMessageLength is the maximum length of a condition message. Provider
+// errors can be arbitrarily long, so messages are truncated to keep the
+// spc pod status object small.
+const maxMessageLength = 1024
+
+// reasonRe is the validation pattern for metav1.Condition reason
+var reasonRe = regexp.MustCompile(`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`)
+
+// SetCondition sets the condition on the spc pod status. The condition is True when
+// err is nil, otherwise it's False with the given reason and the sanitized error message.
+// Returns true if the condition was added or changed.
+func SetCondition(spcps *secretsstorev1.SecretProviderClassPodStatus, conditionType, reason string, err error) bool {
+	return SetStatusCondition(&spcps.Status.Conditions, spcps.Generation, conditionType, reason, err)
+}
+
+// SetStatusCondition sets the condition in the conditions of a status object in the same
+// way as SetCondition. Returns true if the condition was added or changed.
+func SetStatusCondition(conditions *[]metav1.Condition, generation int64, conditionType, reason string, err error) bool {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsstorev1.ConditionReasonSucceeded,
+		ObservedGeneration: generation,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = sanitizeReason(reason)
+		condition.Message = SanitizeMessage(err.Error())
+	}
+
+	existing := meta.FindStatusCondition(*conditions, conditionType)
+	if existing != nil &&
+		existing.Status == condition.Status &&
+		existing.Reason == condition.Reason &&
+		existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(conditions, condition)
+	return true
+}
+
+// SetProviderHealthyCondition sets the ProviderHealthy condition based on the error
+// reason returned for a provider request. The provider is considered healthy if it
+// could be reached, even if it returned an error for the request.
+func SetProviderHealthyCondition(spcps *secretsstorev1.SecretProviderClassPodStatus, reason string, err error) bool {
+	return SetProviderHealthyStatusCondition(&spcps.Status.Conditions, spcps.Generation, reason, err)
+}
+
+// SetProviderHealthyStatusCondition sets the ProviderHealthy condition in the conditions of
+// a status object in the same way as SetProviderHealthyCondition.
+func SetProviderHealthyStatusCondition(conditions *[]metav1.Condition, generation int64, reason string, err error) bool {
+	switch reason {
+	case internalerrors.GRPCProviderError, internalerrors.FailedToLookupProviderGRPCClient, internalerrors.IncompatibleProviderVersion, internalerrors.ProviderUnhealthy, internalerrors.ProviderCircuitOpen:
+		return SetStatusCondition(conditions, generation, secretsstorev1.ConditionTypeProviderHealthy, reason, err)
+	default:
+		return SetStatusCondition(conditions, generation, secretsstorev1.ConditionTypeProviderHealthy, "", nil)
+	}
+}
+
+// OnlyTimestampsChanged returns true if the status of the spc pod status only differs from
+// the original in the last attempt and last successful rotation times. The rotation polls that
+// don't change anything else don't patch the spc pod status, so every poll isn't an API write.
+func OnlyTimestampsChanged(original, spcps *secretsstorev1.SecretProviderClassPodStatus) bool {
+	originalStatus, status := original.Status.DeepCopy(), spcps.Status.DeepCopy()
+	originalStatus.LastAttemptTime, status.LastAttemptTime = nil, nil
+	originalStatus.LastSuccessfulRotationTime, status.LastSuccessfulRotationTime = nil, nil
+	return equality.Semantic.DeepEqual(originalStatus, status)
+}
+
+// SanitizeMessage returns the message in a form that's suitable for a condition message.
+// Whitespace is collapsed to a single line and the message is truncated to maxMessageLength.
+func SanitizeMessage(msg string) string {
+	msg = strings.Join(strings.Fields(msg), " ")
+	if len(msg) > maxMessageLength {
+		msg = strings.ToValidUTF8(msg[:maxMessageLength-3], "") + "..."
+	}
+	return msg
+}
+
+// sanitizeReason returns the reason if it's a valid condition reason. Error codes returned
+// by providers aren't validated, so invalid or empty reasons are reported as ProviderError.
+func sanitizeReason(reason string) string {
+	if !reasonRe.MatchString(reason) {
+		return internalerrors.ProviderError
+	}
+	return reason
+}
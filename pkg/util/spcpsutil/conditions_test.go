@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spcpsutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	internalerrors "sigs.k8s.io/secrets-store-csi-driver/pkg/errors"
+)
+
+func TestSetCondition(t *testing.T) {
+	spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+
+	if !SetCondition(spcps, secretsstorev1.ConditionTypeMounted, "", nil) {
+		t.Fatalf("expected condition to be added")
+	}
+	if SetCondition(spcps, secretsstorev1.ConditionTypeMounted, "", nil) {
+		t.Fatalf("expected no change for the same condition")
+	}
+	c := meta.FindStatusCondition(spcps.Status.Conditions, secretsstorev1.ConditionTypeMounted)
+	if c.Status != metav1.ConditionTrue || c.Reason != secretsstorev1.ConditionReasonSucceeded {
+		t.Fatalf("unexpected condition: %+v", c)
+	}
+
+	if !SetCondition(spcps, secretsstorev1.ConditionTypeMounted, internalerrors.FailedToMount, errors.New("failed\nto mount")) {
+		t.Fatalf("expected condition to change")
+	}
+	c = meta.FindStatusCondition(spcps.Status.Conditions, secretsstorev1.ConditionTypeMounted)
+	if c.Status != metav1.ConditionFalse || c.Reason != internalerrors.FailedToMount || c.Message != "failed to mount" {
+		t.Fatalf("unexpected condition: %+v", c)
+	}
+
+	SetCondition(spcps, secretsstorev1.ConditionTypeRotated, "invalid provider code", errors.New("err"))
+	c = meta.FindStatusCondition(spcps.Status.Conditions, secretsstorev1.ConditionTypeRotated)
+	if c.Reason != internalerrors.ProviderError {
+		t.Fatalf("expected invalid reason to be reported as %s, got %s", internalerrors.ProviderError, c.Reason)
+	}
+}
+
+func TestSetStatusCondition(t *testing.T) {
+	status := &secretsstorev1.SecretProviderClassSyncStatusStatus{}
+
+	if !SetStatusCondition(&status.Conditions, 1, secretsstorev1.ConditionTypeSynced, "", nil) {
+		t.Fatalf("expected condition to be added")
+	}
+	if SetStatusCondition(&status.Conditions, 1, secretsstorev1.ConditionTypeSynced, "", nil) {
+		t.Fatalf("expected no change for the same condition")
+	}
+	if !SetStatusCondition(&status.Conditions, 2, secretsstorev1.ConditionTypeSynced, "", nil) {
+		t.Fatalf("expected condition to change for a new generation")
+	}
+	c := meta.FindStatusCondition(status.Conditions, secretsstorev1.ConditionTypeSynced)
+	if c.Status != metav1.ConditionTrue || c.ObservedGeneration != 2 {
+		t.Fatalf("unexpected condition: %+v", c)
+	}
+}
+
+func TestSetProviderHealthyCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		reason   string
+		err      error
+		expected metav1.ConditionStatus
+	}{
+		{name: "success", expected: metav1.ConditionTrue},
+		{name: "grpc error", reason: internalerrors.GRPCProviderError, err: errors.New("err"), expected: metav1.ConditionFalse},
+		{name: "provider client not found", reason: internalerrors.FailedToLookupProviderGRPCClient, err: errors.New("err"), expected: metav1.ConditionFalse},
+		{name: "incompatible provider version", reason: internalerrors.IncompatibleProviderVersion, err: errors.New("err"), expected: metav1.ConditionFalse},
+		{name: "provider unhealthy", reason: internalerrors.ProviderUnhealthy, err: errors.New("err"), expected: metav1.ConditionFalse},
+		{name: "provider circuit open", reason: internalerrors.ProviderCircuitOpen, err: errors.New("err"), expected: metav1.ConditionFalse},
+		{name: "provider error code", reason: "SecretNotFound", err: errors.New("err"), expected: metav1.ConditionTrue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spcps := &secretsstorev1.SecretProviderClassPodStatus{}
+			SetProviderHealthyCondition(spcps, test.reason, test.err)
+			if !meta.IsStatusConditionPresentAndEqual(spcps.Status.Conditions, secretsstorev1.ConditionTypeProviderHealthy, test.expected) {
+				t.Fatalf("expected ProviderHealthy to be %s, got %+v", test.expected, spcps.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestOnlyTimestampsChanged(t *testing.T) {
+	original := &secretsstorev1.SecretProviderClassPodStatus{}
+	SetCondition(original, secretsstorev1.ConditionTypeRotated, "", nil)
+
+	spcps := original.DeepCopy()
+	now := metav1.Now()
+	spcps.Status.LastAttemptTime = &now
+	spcps.Status.LastSuccessfulRotationTime = &now
+	if !OnlyTimestampsChanged(original, spcps) {
+		t.Fatalf("expected only the timestamps to change")
+	}
+
+	SetCondition(spcps, secretsstorev1.ConditionTypeRotated, internalerrors.FailedToRotate, errors.New("err"))
+	if OnlyTimestampsChanged(original, spcps) {
+		t.Fatalf("expected the condition change to be detected")
+	}
+
+	spcps = original.DeepCopy()
+	spcps.Status.Objects = []secretsstorev1.SecretProviderClassObject{{ID: "secret/object1", Version: "v2"}}
+	if OnlyTimestampsChanged(original, spcps) {
+		t.Fatalf("expected the objects change to be detected")
+	}
+}
+
+func TestSanitizeMessage(t *testing.T) {
+	if got := SanitizeMessage("  a\n\tb  c "); got != "a b c" {
+		t.Fatalf("SanitizeMessage() = %q, expected %q", got, "a b c")
+	}
+	if got := SanitizeMessage(strings.Repeat("a", 2000)); len(got) != maxMessageLength {
+		t.Fatalf("expected message to be truncated to %d, got %d", maxMessageLength, len(got))
+	}
+}
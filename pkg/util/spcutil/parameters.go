@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spcutil
+
+import (
+	"context"
+	"fmt"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ParametersSource gets the configmaps and secrets referenced in the parametersFrom
+// of a secret provider class
+type ParametersSource interface {
+	// GetConfigMap returns the configmap matching name and namespace
+	GetConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error)
+	// GetSecret returns the secret matching name and namespace
+	GetSecret(ctx context.Context, name, namespace string) (*corev1.Secret, error)
+}
+
+type readerParametersSource struct {
+	reader client.Reader
+}
+
+// NewParametersSource returns a ParametersSource that gets the configmaps and secrets
+// with the reader
+func NewParametersSource(reader client.Reader) ParametersSource {
+	return &readerParametersSource{reader: reader}
+}
+
+// GetConfigMap returns the configmap matching name and namespace
+func (r *readerParametersSource) GetConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cm); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// GetSecret returns the secret matching name and namespace
+func (r *readerParametersSource) GetSecret(ctx context.Context, name, namespace string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// AddParametersFrom adds the keys of the configmaps and secrets in the parametersFrom of the secret
+// provider class to the parameters. The sources are read from the namespace of the secret provider
+// class and are merged in order. Keys that are already in the parameters aren't overwritten, so the
+// spc parameters take precedence over the sources. The values of the sources are added as is, without
+// expanding placeholders. An error is returned if a source that isn't optional doesn't exist.
+func AddParametersFrom(ctx context.Context, source ParametersSource, spc *secretsstorev1.SecretProviderClass, parameters map[string]string) error {
+	from := make(map[string]string)
+	for _, src := range spc.Spec.ParametersFrom {
+		if src == nil {
+			continue
+		}
+		switch {
+		case src.ConfigMapRef != nil:
+			cm, err := source.GetConfigMap(ctx, src.ConfigMapRef.Name, spc.Namespace)
+			if err != nil {
+				if apierrors.IsNotFound(err) && src.ConfigMapRef.Optional {
+					continue
+				}
+				return fmt.Errorf("failed to get parametersFrom configmap %s/%s, err: %w", spc.Namespace, src.ConfigMapRef.Name, err)
+			}
+			for k, v := range cm.Data {
+				from[k] = v
+			}
+		case src.SecretRef != nil:
+			secret, err := source.GetSecret(ctx, src.SecretRef.Name, spc.Namespace)
+			if err != nil {
+				if apierrors.IsNotFound(err) && src.SecretRef.Optional {
+					continue
+				}
+				return fmt.Errorf("failed to get parametersFrom secret %s/%s, err: %w", spc.Namespace, src.SecretRef.Name, err)
+			}
+			for k, v := range secret.Data {
+				from[k] = string(v)
+			}
+		}
+	}
+	for k, v := range from {
+		if _, ok := parameters[k]; !ok {
+			parameters[k] = v
+		}
+	}
+	return nil
+}
+
+// ReferencesParametersSource returns true if the parametersFrom of the secret provider class
+// references the configmap or secret with the name
+func ReferencesParametersSource(spc *secretsstorev1.SecretProviderClass, kind, name string) bool {
+	for _, from := range spc.Spec.ParametersFrom {
+		if from == nil {
+			continue
+		}
+		if kind == "ConfigMap" && from.ConfigMapRef != nil && from.ConfigMapRef.Name == name {
+			return true
+		}
+		if kind == "Secret" && from.SecretRef != nil && from.SecretRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spcutil
+
+import (
+	"context"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAddParametersFrom(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	c := fake.NewFakeClientWithScheme(scheme,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-config", Namespace: "default"},
+			Data:       map[string]string{"vaultAddress": "https://vault:8200", "roleName": "default"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-role", Namespace: "default"},
+			Data:       map[string][]byte{"roleName": []byte("team1"), "tenantID": []byte("tenant1")},
+		},
+	)
+
+	tests := []struct {
+		name           string
+		parameters     map[string]string
+		parametersFrom []*secretsstorev1.ParametersFromSource
+		want           map[string]string
+		wantErr        bool
+	}{
+		{
+			name:       "sources are merged in order",
+			parameters: map[string]string{"objects": "${namespace}"},
+			parametersFrom: []*secretsstorev1.ParametersFromSource{
+				{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+				{SecretRef: &secretsstorev1.ParametersFromReference{Name: "vault-role"}},
+			},
+			// the values of the sources are added as is
+			want: map[string]string{"vaultAddress": "https://vault:8200", "roleName": "team1", "tenantID": "tenant1", "objects": "${namespace}"},
+		},
+		{
+			name:       "parameters take precedence",
+			parameters: map[string]string{"roleName": "spc"},
+			parametersFrom: []*secretsstorev1.ParametersFromSource{
+				{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "vault-config"}},
+			},
+			want: map[string]string{"vaultAddress": "https://vault:8200", "roleName": "spc"},
+		},
+		{
+			name:       "optional source not found",
+			parameters: map[string]string{},
+			parametersFrom: []*secretsstorev1.ParametersFromSource{
+				{SecretRef: &secretsstorev1.ParametersFromReference{Name: "missing", Optional: true}},
+			},
+			want: map[string]string{},
+		},
+		{
+			name:       "source not found",
+			parameters: map[string]string{},
+			parametersFrom: []*secretsstorev1.ParametersFromSource{
+				{ConfigMapRef: &secretsstorev1.ParametersFromReference{Name: "missing"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spc := &secretsstorev1.SecretProviderClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+				Spec:       secretsstorev1.SecretProviderClassSpec{ParametersFrom: test.parametersFrom},
+			}
+			err := AddParametersFrom(context.TODO(), NewParametersSource(c), spc, test.parameters)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, test.parameters); diff != "" {
+				t.Errorf("AddParametersFrom() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spcutil holds Secrets CSI Driver utilities for resolving the
+// SecretProviderClass referenced by a pod volume.
+package spcutil
+
+import (
+	"context"
+	"fmt"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetSecretProviderClass returns the secret provider class of the given kind and name used by a pod
+// in namespace. A ClusterSecretProviderClass is returned as a SecretProviderClass in the pod namespace,
+// so it's handled the same way as a namespaced secret provider class by the callers. An error is returned
+// if the namespace isn't selected by the cluster secret provider class namespace selector.
+func GetSecretProviderClass(ctx context.Context, c client.Reader, kind, name, namespace string) (*secretsstorev1.SecretProviderClass, error) {
+	switch kind {
+	case "", secretsstorev1.SecretProviderClassKind:
+		spc := &secretsstorev1.SecretProviderClass{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, spc); err != nil {
+			return nil, err
+		}
+		return spc, nil
+	case secretsstorev1.ClusterSecretProviderClassKind:
+		cspc := &secretsstorev1.ClusterSecretProviderClass{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, cspc); err != nil {
+			return nil, err
+		}
+		allowed, err := namespaceAllowed(ctx, c, cspc, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("namespace %s is not selected by the namespace selector of cluster secret provider class %s", namespace, name)
+		}
+		return &secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        cspc.Name,
+				Namespace:   namespace,
+				Labels:      cspc.Labels,
+				Annotations: cspc.Annotations,
+			},
+			Spec: cspc.Spec.SecretProviderClassSpec,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported secret provider class kind %q", kind)
+	}
+}
+
+// namespaceAllowed returns true if the namespace is selected by the namespace
+// selector of the cluster secret provider class
+func namespaceAllowed(ctx context.Context, c client.Reader, cspc *secretsstorev1.ClusterSecretProviderClass, namespace string) (bool, error) {
+	if cspc.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cspc.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector in cluster secret provider class %s, err: %v", cspc.Name, err)
+	}
+	if selector.Empty() {
+		return true, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, fmt.Errorf("failed to get namespace %s, err: %w", namespace, err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spcutil
+
+import (
+	"context"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetSecretProviderClass(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+
+	spec := secretsstorev1.SecretProviderClassSpec{Provider: "provider1", Parameters: map[string]string{"key": "value"}}
+	c := fake.NewFakeClientWithScheme(scheme,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"team": "b"}}},
+		&secretsstorev1.SecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+			Spec:       spec,
+		},
+		&secretsstorev1.ClusterSecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "all"},
+			Spec:       secretsstorev1.ClusterSecretProviderClassSpec{SecretProviderClassSpec: spec},
+		},
+		&secretsstorev1.ClusterSecretProviderClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: secretsstorev1.ClusterSecretProviderClassSpec{
+				SecretProviderClassSpec: spec,
+				NamespaceSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+		},
+	)
+
+	tests := []struct {
+		name      string
+		kind      string
+		spcName   string
+		namespace string
+		wantErr   bool
+		notFound  bool
+	}{
+		{name: "secret provider class", kind: "", spcName: "spc1", namespace: "default"},
+		{name: "secret provider class kind", kind: secretsstorev1.SecretProviderClassKind, spcName: "spc1", namespace: "default"},
+		{name: "secret provider class not found", kind: "", spcName: "spc1", namespace: "other", wantErr: true, notFound: true},
+		{name: "cluster secret provider class without selector", kind: secretsstorev1.ClusterSecretProviderClassKind, spcName: "all", namespace: "other"},
+		{name: "cluster secret provider class with selected namespace", kind: secretsstorev1.ClusterSecretProviderClassKind, spcName: "team-a", namespace: "default"},
+		{name: "cluster secret provider class with namespace not selected", kind: secretsstorev1.ClusterSecretProviderClassKind, spcName: "team-a", namespace: "other", wantErr: true},
+		{name: "cluster secret provider class not found", kind: secretsstorev1.ClusterSecretProviderClassKind, spcName: "spc1", namespace: "default", wantErr: true, notFound: true},
+		{name: "unsupported kind", kind: "Secret", spcName: "spc1", namespace: "default", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spc, err := GetSecretProviderClass(context.TODO(), c, test.kind, test.spcName, test.namespace)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if apierrors.IsNotFound(err) != test.notFound {
+					t.Fatalf("expected not found error to be %v, got %v", test.notFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSecretProviderClass() error = %v", err)
+			}
+			if spc.Name != test.spcName || spc.Namespace != test.namespace || spc.Spec.Provider != "provider1" {
+				t.Fatalf("unexpected secret provider class %+v", spc)
+			}
+		})
+	}
+}
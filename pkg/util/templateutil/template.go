@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templateutil renders the templates defined in the secret provider class
+// with the content of the mounted objects.
+package templateutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+// maxRenderedSize is the maximum size of a rendered template. Kubernetes secrets are limited to 1MiB.
+const maxRenderedSize = 1024 * 1024
+
+// errRenderedSizeExceeded is returned when the rendered template exceeds maxRenderedSize
+var errRenderedSizeExceeded = fmt.Errorf("rendered template exceeds %d bytes", maxRenderedSize)
+
+// funcs are the functions available to the templates. The templates are defined by the
+// users of the secret provider class and rendered by the driver, so only functions
+// that transform their arguments are allowed. There are no functions to access the
+// environment, the filesystem or the network.
+var funcs = template.FuncMap{
+	"base64Encode": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"base64Decode": func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		return string(b), err
+	},
+	"trim":        strings.TrimSpace,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+	"replace":     func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"quote":       func(s string) string { return fmt.Sprintf("%q", s) },
+	"queryEscape": url.QueryEscape,
+	"pathEscape":  url.PathEscape,
+	"fromJSON": func(s string) (interface{}, error) {
+		var v interface{}
+		err := json.Unmarshal([]byte(s), &v)
+		return v, err
+	},
+	"toJSON": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Parse parses the template
+func Parse(t *secretsstorev1.SecretTemplate) (*template.Template, error) {
+	return template.New(t.Name).Funcs(funcs).Option("missingkey=error").Parse(t.Template)
+}
+
+// Render renders the template with the objects. The objects are available in the
+// template by their path in the mount, e.g. {{ .username }} or {{ index . "db/password" }}.
+func Render(t *secretsstorev1.SecretTemplate, objects map[string]string) ([]byte, error) {
+	tmpl, err := Parse(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s, err: %w", t.Name, err)
+	}
+	w := &limitedWriter{limit: maxRenderedSize}
+	if err := tmpl.Execute(w, objects); err != nil {
+		return nil, fmt.Errorf("failed to render template %s, err: %w", t.Name, err)
+	}
+	return w.buf.Bytes(), nil
+}
+
+// ReadObjects reads the content of the mounted files, excluding the files rendered from the templates
+func ReadObjects(files map[string]string, templates []*secretsstorev1.SecretTemplate) (map[string]string, error) {
+	rendered := make(map[string]struct{}, len(templates))
+	for _, t := range templates {
+		if t != nil && len(t.Path) > 0 {
+			rendered[t.Path] = struct{}{}
+		}
+	}
+	objects := make(map[string]string, len(files))
+	for name, file := range files {
+		if _, ok := rendered[name]; ok {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s, err: %w", name, err)
+		}
+		objects[name] = string(content)
+	}
+	return objects, nil
+}
+
+// Find returns the template with name, or nil if it isn't defined
+func Find(templates []*secretsstorev1.SecretTemplate, name string) *secretsstorev1.SecretTemplate {
+	for _, t := range templates {
+		if t != nil && t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// limitedWriter is a bytes buffer that fails writes over the limit
+type limitedWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		return 0, errRenderedSizeExceeded
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templateutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+)
+
+func TestRender(t *testing.T) {
+	objects := map[string]string{
+		"username":    "user",
+		"password":    "p@ss word\n",
+		"db/host":     "db.example.com",
+		"credentials": `{"username": "admin"}`,
+	}
+	tests := []struct {
+		name          string
+		template      string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "jdbc url",
+			template: `jdbc:postgresql://{{ index . "db/host" }}/db?user={{ .username }}&password={{ .password | trim | queryEscape }}`,
+			expected: "jdbc:postgresql://db.example.com/db?user=user&password=p%40ss+word",
+		},
+		{
+			name:     "json object",
+			template: `{{ (fromJSON .credentials).username | upper }}`,
+			expected: "ADMIN",
+		},
+		{
+			name:     "base64",
+			template: `{{ .username | base64Encode }}`,
+			expected: "dXNlcg==",
+		},
+		{
+			name:          "missing object",
+			template:      `{{ .missing }}`,
+			expectedError: true,
+		},
+		{
+			name:          "parse error",
+			template:      `{{ .username `,
+			expectedError: true,
+		},
+		{
+			name:          "functions outside the sandbox aren't defined",
+			template:      `{{ env "HOME" }}`,
+			expectedError: true,
+		},
+		{
+			name:          "rendered size limit",
+			template:      `{{ range $i, $v := fromJSON "[` + strings.Repeat("0,", 2000) + `0]" }}{{ range $j, $w := fromJSON "[` + strings.Repeat("0,", 1000) + `0]" }}{{ $.username }}{{ end }}{{ end }}`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Render(&secretsstorev1.SecretTemplate{Name: "test", Template: test.template}, objects)
+			if test.expectedError {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, string(got))
+			}
+		})
+	}
+}
+
+func TestReadObjects(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	for name, content := range map[string]string{"username": "user", "url": "rendered"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		files[name] = path
+	}
+
+	objects, err := ReadObjects(files, []*secretsstorev1.SecretTemplate{{Name: "url", Path: "url"}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(objects) != 1 || objects["username"] != "user" {
+		t.Errorf("expected only the username object, got: %v", objects)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transformutil applies the data transforms defined in the secret provider
+// class to the object content.
+package transformutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Apply applies the transforms to the data in order and returns the result
+func Apply(data []byte, transforms []secretsstorev1.DataTransform) ([]byte, error) {
+	var err error
+	for i, t := range transforms {
+		if data, err = apply(data, t); err != nil {
+			return nil, fmt.Errorf("transform %d (%s) failed, err: %w", i, t.Type, err)
+		}
+	}
+	return data, nil
+}
+
+// Validate checks that the transform has the parameters required by its type
+func Validate(t secretsstorev1.DataTransform) error {
+	switch t.Type {
+	case secretsstorev1.DataTransformBase64Decode, secretsstorev1.DataTransformHexDecode, secretsstorev1.DataTransformTrim:
+		return nil
+	case secretsstorev1.DataTransformJSONPath:
+		if len(t.JSONPath) == 0 {
+			return fmt.Errorf("jsonPath is required")
+		}
+		return jsonpath.New("transform").Parse(t.JSONPath)
+	case secretsstorev1.DataTransformYAMLKey:
+		if len(t.Key) == 0 {
+			return fmt.Errorf("key is required")
+		}
+		return nil
+	case secretsstorev1.DataTransformPEMBlock:
+		if t.Index < 0 {
+			return fmt.Errorf("index must not be negative")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported transform type %q", t.Type)
+	}
+}
+
+func apply(data []byte, t secretsstorev1.DataTransform) ([]byte, error) {
+	if err := Validate(t); err != nil {
+		return nil, err
+	}
+	switch t.Type {
+	case secretsstorev1.DataTransformBase64Decode:
+		return base64Decode(data)
+	case secretsstorev1.DataTransformHexDecode:
+		s := strings.TrimSpace(string(data))
+		return hex.DecodeString(s)
+	case secretsstorev1.DataTransformJSONPath:
+		return jsonPathValue(data, t.JSONPath)
+	case secretsstorev1.DataTransformYAMLKey:
+		return yamlKeyValue(data, t.Key)
+	case secretsstorev1.DataTransformPEMBlock:
+		return pemBlock(data, t.PEMType, t.Index)
+	default:
+		return bytes.TrimSpace(data), nil
+	}
+}
+
+// base64Decode decodes standard or url encoded base64 content, with or without padding
+func base64Decode(data []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(data))
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if decoded, err := enc.DecodeString(s); err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, fmt.Errorf("content is not base64 encoded")
+}
+
+// jsonPathValue returns the value at the JSONPath expression. Strings are returned as is,
+// other values are JSON encoded.
+func jsonPathValue(data []byte, expr string) ([]byte, error) {
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("content is not valid json, err: %w", err)
+	}
+	jp := jsonpath.New("transform")
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, r := range results {
+		for _, v := range r {
+			values = append(values, v.Interface())
+		}
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("jsonPath %s matched %d values, expected 1", expr, len(values))
+	}
+	return encodeValue(values[0])
+}
+
+// yamlKeyValue returns the value of the key in the YAML content. Strings are returned as is,
+// other values are JSON encoded.
+func yamlKeyValue(data []byte, key string) ([]byte, error) {
+	var obj interface{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("content is not valid yaml, err: %w", err)
+	}
+	for _, k := range strings.Split(key, ".") {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("key %s not found", key)
+		}
+		if obj, ok = m[k]; !ok {
+			return nil, fmt.Errorf("key %s not found", key)
+		}
+	}
+	return encodeValue(obj)
+}
+
+func encodeValue(v interface{}) ([]byte, error) {
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
+
+// pemBlock returns the PEM encoded block at index among the blocks of pemType
+func pemBlock(data []byte, pemType string, index int) ([]byte, error) {
+	i := 0
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if len(pemType) == 0 || block.Type == pemType {
+			if i == index {
+				return pem.EncodeToMemory(block), nil
+			}
+			i++
+		}
+		data = rest
+	}
+	return nil, fmt.Errorf("pem block %d of type %q not found", index, pemType)
+}
@@ -0,0 +1,387 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;patch
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;patch
+
+const (
+	// caCertKey and caKeyKey are the keys of the CA certificate and key in the cert secret.
+	// The serving certificate and key use the corev1.TLSCertKey and corev1.TLSPrivateKeyKey keys.
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+
+	// ConversionPath is the path the conversion webhook is served at by controller-runtime
+	ConversionPath = "/convert"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+	// certs are renewed when they expire within rotateBefore
+	rotateBefore = 90 * 24 * time.Hour
+)
+
+// ConversionCRDs are the CRDs served with the conversion webhook
+var ConversionCRDs = []string{
+	"secretproviderclasses.secrets-store.csi.x-k8s.io",
+	"secretproviderclasspodstatuses.secrets-store.csi.x-k8s.io",
+}
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// CertRotator keeps the webhook serving certificate and its self-signed CA in a secret
+// shared by all the driver pods. It writes the certificate to the cert dir of the webhook
+// server, renews it before it expires and injects the CA in the conversion webhook of the
+// CRDs and in the validating webhook configuration.
+type CertRotator struct {
+	// client must not read from the manager's cache, as the secrets cache only has the synced secrets
+	client client.Client
+	// Secret is the secret the certificates are stored in
+	Secret types.NamespacedName
+	// Service is the service the webhook server is exposed with
+	Service types.NamespacedName
+	// CertDir is the directory the webhook server reads tls.crt and tls.key from
+	CertDir string
+	// CRDNames are the CRDs the conversion webhook is configured for
+	CRDNames []string
+	// ValidatingWebhookConfiguration is the name of the validating webhook configuration
+	// the CA is injected in, if the validating webhook is enabled
+	ValidatingWebhookConfiguration string
+
+	now func() time.Time
+}
+
+// NewCertRotator creates a new CertRotator
+func NewCertRotator(c client.Client, secret, service types.NamespacedName, certDir string) *CertRotator {
+	return &CertRotator{
+		client:  c,
+		Secret:  secret,
+		Service: service,
+		CertDir: certDir,
+		now:     time.Now,
+	}
+}
+
+// Sync makes sure the secret has a valid certificate, writes it to the cert dir and
+// configures the webhooks with the CA.
+func (r *CertRotator) Sync(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ensure webhook cert secret %s, err: %w", r.Secret, err)
+	}
+	if err := r.writeCertFiles(secret); err != nil {
+		return fmt.Errorf("failed to write webhook cert, err: %w", err)
+	}
+	caBundle := secret.Data[caCertKey]
+	for _, name := range r.CRDNames {
+		if err := r.injectConversionWebhook(ctx, name, caBundle); err != nil {
+			return fmt.Errorf("failed to configure conversion webhook of crd %s, err: %w", name, err)
+		}
+	}
+	if r.ValidatingWebhookConfiguration != "" {
+		if err := r.injectValidatingWebhook(ctx, caBundle); err != nil {
+			return fmt.Errorf("failed to inject ca in validating webhook configuration %s, err: %w", r.ValidatingWebhookConfiguration, err)
+		}
+	}
+	return nil
+}
+
+// Start syncs the certificate every interval until the context is done. It implements
+// manager.Runnable, the first sync must be done with Sync before the manager is started
+// as the webhook server fails to start without the cert files.
+func (r *CertRotator) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.Sync(ctx); err != nil {
+			klog.ErrorS(err, "failed to sync webhook cert", "secret", r.Secret)
+		}
+	}, 10*time.Minute)
+	return nil
+}
+
+// ensureSecret returns the cert secret, after creating it or renewing its certificates
+// if needed. All the driver pods run it, so the secret is only updated if it hasn't
+// changed since it was read, and re-read on conflict.
+func (r *CertRotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		err := r.client.Get(ctx, r.Secret, secret)
+		if apierrors.IsNotFound(err) {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: r.Secret.Name, Namespace: r.Secret.Namespace},
+				Type:       corev1.SecretTypeTLS,
+			}
+			if err = r.renew(secret); err != nil {
+				return err
+			}
+			return r.client.Create(ctx, secret)
+		}
+		if err != nil {
+			return err
+		}
+		if r.valid(secret) {
+			return nil
+		}
+		klog.InfoS("renewing webhook cert", "secret", r.Secret)
+		if err = r.renew(secret); err != nil {
+			return err
+		}
+		return r.client.Update(ctx, secret)
+	})
+	return secret, err
+}
+
+// valid returns true if the certificate in the secret is signed by the CA in the secret,
+// is valid for the service and doesn't need to be renewed yet
+func (r *CertRotator) valid(secret *corev1.Secret) bool {
+	ca, _, err := parseKeyPair(secret.Data[caCertKey], secret.Data[caKeyKey])
+	if err != nil || r.expiresSoon(ca) {
+		return false
+	}
+	cert, _, err := parseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil || r.expiresSoon(cert) {
+		return false
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     r.dnsNames()[0],
+		Roots:       pool,
+		CurrentTime: r.now(),
+	})
+	return err == nil
+}
+
+// renew sets a new serving certificate in the secret. The certificate is signed by the
+// CA in the secret, so the CA bundle of the webhooks doesn't change, unless the CA is
+// missing or expires soon.
+func (r *CertRotator) renew(secret *corev1.Secret) error {
+	ca, caKey, err := parseKeyPair(secret.Data[caCertKey], secret.Data[caKeyKey])
+	if err != nil || r.expiresSoon(ca) {
+		var caPEM, caKeyPEM []byte
+		if caPEM, caKeyPEM, err = r.newCert(nil, nil); err != nil {
+			return fmt.Errorf("failed to create ca, err: %w", err)
+		}
+		if ca, caKey, err = parseKeyPair(caPEM, caKeyPEM); err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[caCertKey], secret.Data[caKeyKey] = caPEM, caKeyPEM
+	}
+	certPEM, keyPEM, err := r.newCert(ca, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create serving cert, err: %w", err)
+	}
+	secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey] = certPEM, keyPEM
+	return nil
+}
+
+// newCert creates a serving certificate signed by the CA, or a self-signed CA if ca is nil,
+// and returns the PEM encoded certificate and key
+func (r *CertRotator) newCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := r.now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		// allow for clock skew between the nodes and the api server
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Subject:     pkix.Name{CommonName: r.dnsNames()[0]},
+		DNSNames:    r.dnsNames(),
+	}
+	if ca == nil {
+		template.Subject = pkix.Name{CommonName: "secrets-store-csi-driver-webhook-ca"}
+		template.DNSNames = nil
+		template.NotAfter = now.Add(caValidity)
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = nil
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		ca, caKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+func (r *CertRotator) expiresSoon(cert *x509.Certificate) bool {
+	return r.now().Add(rotateBefore).After(cert.NotAfter)
+}
+
+// dnsNames returns the names the api server uses to reach the webhook service
+func (r *CertRotator) dnsNames() []string {
+	name, namespace := r.Service.Name, r.Service.Namespace
+	return []string{
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+		fmt.Sprintf("%s.%s", name, namespace),
+		name,
+	}
+}
+
+// writeCertFiles writes the serving certificate and key to the cert dir if they changed.
+// The files are replaced atomically, as the webhook server reloads them on change.
+func (r *CertRotator) writeCertFiles(secret *corev1.Secret) error {
+	if err := os.MkdirAll(r.CertDir, 0700); err != nil {
+		return err
+	}
+	for _, key := range []string{corev1.TLSPrivateKeyKey, corev1.TLSCertKey} {
+		path := filepath.Join(r.CertDir, key)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, secret.Data[key], 0600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectConversionWebhook sets the conversion strategy of the CRD to Webhook, with the
+// webhook service and CA bundle. The CRDs are installed with the None strategy, as the
+// service namespace and CA are only known at runtime.
+func (r *CertRotator) injectConversionWebhook(ctx context.Context, name string, caBundle []byte) error {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		return err
+	}
+	want := map[string]interface{}{
+		"strategy": "Webhook",
+		"webhook": map[string]interface{}{
+			"clientConfig": map[string]interface{}{
+				"service": map[string]interface{}{
+					"namespace": r.Service.Namespace,
+					"name":      r.Service.Name,
+					"path":      ConversionPath,
+					"port":      int64(443),
+				},
+				// []byte fields are base64 encoded strings in the json encoding
+				"caBundle": base64.StdEncoding.EncodeToString(caBundle),
+			},
+			"conversionReviewVersions": []interface{}{"v1", "v1beta1"},
+		},
+	}
+	got, _, err := unstructured.NestedMap(crd.Object, "spec", "conversion")
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(got, want) {
+		return nil
+	}
+	patch := client.MergeFrom(crd.DeepCopy())
+	if err := unstructured.SetNestedMap(crd.Object, want, "spec", "conversion"); err != nil {
+		return err
+	}
+	klog.InfoS("configuring conversion webhook", "crd", name, "service", r.Service)
+	return r.client.Patch(ctx, crd, patch)
+}
+
+// injectValidatingWebhook sets the CA bundle of the webhooks in the validating webhook configuration
+func (r *CertRotator) injectValidatingWebhook(ctx context.Context, caBundle []byte) error {
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.ValidatingWebhookConfiguration}, config); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(config.DeepCopy())
+	changed := false
+	for i := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			config.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	klog.InfoS("injecting ca in validating webhook configuration", "name", r.ValidatingWebhookConfiguration)
+	return r.client.Patch(ctx, config, patch)
+}
+
+// parseKeyPair parses the PEM encoded certificate and EC private key
+func parseKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode private key")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
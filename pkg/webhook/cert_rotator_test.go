@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testCertSecret = types.NamespacedName{Namespace: "kube-system", Name: "webhook-cert"}
+	testService    = types.NamespacedName{Namespace: "kube-system", Name: "webhook-service"}
+)
+
+func newTestCRD(name string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"group": "secrets-store.csi.x-k8s.io"},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(name)
+	return crd
+}
+
+func newTestRotator(c client.Client, certDir string) *CertRotator {
+	r := NewCertRotator(c, testCertSecret, testService, certDir)
+	r.CRDNames = ConversionCRDs
+	r.ValidatingWebhookConfiguration = "validating-webhook"
+	return r
+}
+
+func newTestClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	objs := []runtime.Object{
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "validating-webhook"},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "v1"}, {Name: "v2"}},
+		},
+	}
+	for _, name := range ConversionCRDs {
+		objs = append(objs, newTestCRD(name))
+	}
+	return fake.NewFakeClientWithScheme(scheme, objs...)
+}
+
+func getCertSecret(t *testing.T, c client.Client) *corev1.Secret {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), testCertSecret, secret); err != nil {
+		t.Fatalf("failed to get cert secret, err: %v", err)
+	}
+	return secret
+}
+
+func TestCertRotatorSync(t *testing.T) {
+	c := newTestClient(t)
+	certDir := t.TempDir()
+	r := newTestRotator(c, certDir)
+
+	if err := r.Sync(context.TODO()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	secret := getCertSecret(t, c)
+
+	// the cert files are the serving cert, valid for the service and signed by the ca
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(certDir, corev1.TLSCertKey), filepath.Join(certDir, corev1.TLSPrivateKeyKey))
+	if err != nil {
+		t.Fatalf("failed to load cert files, err: %v", err)
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse cert, err: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(secret.Data[caCertKey])
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "webhook-service.kube-system.svc", Roots: pool}); err != nil {
+		t.Errorf("serving cert isn't valid for the service, err: %v", err)
+	}
+
+	for _, name := range ConversionCRDs {
+		crd := newTestCRD(name)
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, crd); err != nil {
+			t.Fatalf("failed to get crd, err: %v", err)
+		}
+		strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+		if strategy != "Webhook" {
+			t.Errorf("crd %s conversion strategy = %q, want Webhook", name, strategy)
+		}
+		caBundle, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		if caBundle != base64.StdEncoding.EncodeToString(secret.Data[caCertKey]) {
+			t.Errorf("crd %s conversion webhook caBundle doesn't match the ca", name)
+		}
+		serviceName, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "service", "name")
+		if serviceName != testService.Name {
+			t.Errorf("crd %s conversion webhook service = %q, want %q", name, serviceName, testService.Name)
+		}
+	}
+
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "validating-webhook"}, config); err != nil {
+		t.Fatalf("failed to get validating webhook configuration, err: %v", err)
+	}
+	for _, webhook := range config.Webhooks {
+		if !bytes.Equal(webhook.ClientConfig.CABundle, secret.Data[caCertKey]) {
+			t.Errorf("webhook %s caBundle doesn't match the ca", webhook.Name)
+		}
+	}
+}
+
+func TestCertRotatorSyncSharedSecret(t *testing.T) {
+	c := newTestClient(t)
+	if err := newTestRotator(c, t.TempDir()).Sync(context.TODO()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := getCertSecret(t, c)
+
+	// another driver pod uses the same certificate
+	certDir := t.TempDir()
+	if err := newTestRotator(c, certDir).Sync(context.TODO()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	got := getCertSecret(t, c)
+	if got.ResourceVersion != want.ResourceVersion {
+		t.Errorf("Sync() updated a valid cert secret")
+	}
+	certPEM, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	if err != nil {
+		t.Fatalf("failed to read cert file, err: %v", err)
+	}
+	if !bytes.Equal(certPEM, want.Data[corev1.TLSCertKey]) {
+		t.Errorf("Sync() wrote a different cert than the secret one")
+	}
+}
+
+func TestCertRotatorSyncRenew(t *testing.T) {
+	c := newTestClient(t)
+	r := newTestRotator(c, t.TempDir())
+	if err := r.Sync(context.TODO()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	before := getCertSecret(t, c)
+
+	// the serving cert expires within rotateBefore: it's renewed with the same ca
+	r.now = func() time.Time { return time.Now().Add(certValidity - rotateBefore + time.Hour) }
+	if err := r.Sync(context.TODO()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	after := getCertSecret(t, c)
+	if bytes.Equal(before.Data[corev1.TLSCertKey], after.Data[corev1.TLSCertKey]) {
+		t.Errorf("Sync() didn't renew the serving cert")
+	}
+	if !bytes.Equal(before.Data[caCertKey], after.Data[caCertKey]) {
+		t.Errorf("Sync() renewed the ca with the serving cert")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateClusterSecretProviderClassPath is the path the cluster secret provider class validating webhook is served at
+const ValidateClusterSecretProviderClassPath = "/validate-secrets-store-csi-x-k8s-io-v1-clustersecretproviderclass"
+
+// ClusterSecretProviderClassValidator validates the cluster secret provider class on create and update.
+// The secrets synced for a cluster secret provider class are in the namespaces of the pods, so unlike
+// the SecretProviderClassValidator, it doesn't warn about updates of secrets that have already been synced.
+type ClusterSecretProviderClassValidator struct {
+	decoder *admission.Decoder
+}
+
+// NewClusterSecretProviderClassValidator creates a new ClusterSecretProviderClassValidator
+func NewClusterSecretProviderClassValidator() *ClusterSecretProviderClassValidator {
+	return &ClusterSecretProviderClassValidator{}
+}
+
+// SetupWithManager registers the validating webhook with the manager's webhook server
+func (v *ClusterSecretProviderClassValidator) SetupWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ValidateClusterSecretProviderClassPath, &webhook.Admission{Handler: v})
+	return nil
+}
+
+// InjectDecoder injects the decoder into the validator
+func (v *ClusterSecretProviderClassValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the cluster secret provider class in the admission request
+func (v *ClusterSecretProviderClassValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	cspc := &secretsstorev1.ClusterSecretProviderClass{}
+	if err := v.decoder.Decode(req, cspc); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	errs, warnings := secretsstore.ValidateClusterSecretProviderClass(cspc)
+	if len(errs) > 0 {
+		return invalid(secretsstorev1.ClusterSecretProviderClassKind, cspc.Name, errs, warnings)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newClusterValidator(t *testing.T) *ClusterSecretProviderClassValidator {
+	scheme := runtime.NewScheme()
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("failed to create decoder, err: %v", err)
+	}
+	v := NewClusterSecretProviderClassValidator()
+	if err := v.InjectDecoder(decoder); err != nil {
+		t.Fatalf("failed to inject decoder, err: %v", err)
+	}
+	return v
+}
+
+func newClusterRequest(t *testing.T, operation admissionv1.Operation, cspc *secretsstorev1.ClusterSecretProviderClass) admission.Request {
+	raw, err := json.Marshal(cspc)
+	if err != nil {
+		t.Fatalf("failed to marshal cspc, err: %v", err)
+	}
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Name:      cspc.Name,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func TestHandleClusterSecretProviderClass(t *testing.T) {
+	v := newClusterValidator(t)
+
+	cspc := &secretsstorev1.ClusterSecretProviderClass{
+		TypeMeta:   metav1.TypeMeta{APIVersion: secretsstorev1.GroupVersion.String(), Kind: secretsstorev1.ClusterSecretProviderClassKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cspc1"},
+		Spec: secretsstorev1.ClusterSecretProviderClassSpec{
+			SecretProviderClassSpec: secretsstorev1.SecretProviderClassSpec{Provider: "provider1"},
+			NamespaceSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		},
+	}
+	for _, operation := range []admissionv1.Operation{admissionv1.Create, admissionv1.Update} {
+		resp := v.Handle(context.TODO(), newClusterRequest(t, operation, cspc))
+		if !resp.Allowed {
+			t.Fatalf("expected valid cspc to be allowed on %s, got %+v", operation, resp.Result)
+		}
+	}
+
+	invalid := cspc.DeepCopy()
+	invalid.Spec.SecretObjects = []*secretsstorev1.SecretObject{{SecretName: "secret1"}}
+	invalid.Spec.NamespaceSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: metav1.LabelSelectorOpExists, Values: []string{"a"}}},
+	}
+	resp := v.Handle(context.TODO(), newClusterRequest(t, admissionv1.Create, invalid))
+	if resp.Allowed {
+		t.Fatalf("expected invalid cspc to be denied")
+	}
+	if resp.Result == nil || resp.Result.Reason != metav1.StatusReasonInvalid || resp.Result.Details == nil {
+		t.Fatalf("expected invalid status, got %+v", resp.Result)
+	}
+	if resp.Result.Details.Kind != secretsstorev1.ClusterSecretProviderClassKind {
+		t.Errorf("expected kind %s, got %s", secretsstorev1.ClusterSecretProviderClassKind, resp.Result.Details.Kind)
+	}
+	fields := make(map[string]bool)
+	for _, cause := range resp.Result.Details.Causes {
+		fields[cause.Field] = true
+	}
+	for _, f := range []string{"spec.secretObjects[0].type", "spec.namespaceSelector.matchExpressions[0].values"} {
+		if !fields[f] {
+			t.Errorf("expected error for field %s, got %+v", f, resp.Result.Details.Causes)
+		}
+	}
+}
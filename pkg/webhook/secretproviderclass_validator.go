@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook holds the admission webhooks for the secrets-store.csi.x-k8s.io APIs.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/controllers"
+	secretsstore "sigs.k8s.io/secrets-store-csi-driver/pkg/secrets-store"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateSecretProviderClassPath is the path the secret provider class validating webhook is served at
+const ValidateSecretProviderClassPath = "/validate-secrets-store-csi-x-k8s-io-v1-secretproviderclass"
+
+// SecretProviderClassValidator validates the secret provider class on create and update
+type SecretProviderClassValidator struct {
+	// reader is used to look up the secrets that have been synced for the secret provider class
+	reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// NewSecretProviderClassValidator creates a new SecretProviderClassValidator
+func NewSecretProviderClassValidator(reader client.Reader) *SecretProviderClassValidator {
+	return &SecretProviderClassValidator{
+		reader: reader,
+	}
+}
+
+// SetupWithManager registers the validating webhook with the manager's webhook server
+func (v *SecretProviderClassValidator) SetupWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ValidateSecretProviderClassPath, &webhook.Admission{Handler: v})
+	return nil
+}
+
+// InjectDecoder injects the decoder into the validator
+func (v *SecretProviderClassValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the secret provider class in the admission request
+func (v *SecretProviderClassValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	spc := &secretsstorev1.SecretProviderClass{}
+	if err := v.decoder.Decode(req, spc); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	errs, warnings := secretsstore.ValidateSecretProviderClass(spc)
+	if len(errs) > 0 {
+		return invalid(secretsstorev1.SecretProviderClassKind, spc.Name, errs, warnings)
+	}
+
+	if req.Operation == admissionv1.Update {
+		old := &secretsstorev1.SecretProviderClass{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		warnings = append(warnings, v.syncedSecretWarnings(ctx, old, spc)...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// syncedSecretWarnings returns a warning for each secret object whose type or data is changed
+// by the update when the Kubernetes secret has already been synced by the driver.
+func (v *SecretProviderClassValidator) syncedSecretWarnings(ctx context.Context, old, spc *secretsstorev1.SecretProviderClass) []string {
+	oldSecretObjects := make(map[string]*secretsstorev1.SecretObject)
+	for _, secretObj := range old.Spec.SecretObjects {
+		if secretObj != nil {
+			oldSecretObjects[secretObj.SecretName] = secretObj
+		}
+	}
+
+	var warnings []string
+	for i, secretObj := range spc.Spec.SecretObjects {
+		oldSecretObj, ok := oldSecretObjects[secretObj.SecretName]
+		if !ok || (oldSecretObj.Type == secretObj.Type && reflect.DeepEqual(oldSecretObj.Data, secretObj.Data)) {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := v.reader.Get(ctx, types.NamespacedName{Namespace: spc.Namespace, Name: secretObj.SecretName}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "failed to get secret", "secret", klog.KRef(spc.Namespace, secretObj.SecretName), "spc", klog.KObj(spc))
+			}
+			continue
+		}
+		if secret.GetLabels()[controllers.SecretManagedLabel] != "true" {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("spec.secretObjects[%d]: secret %s/%s has already been synced, the update changes the type or data of the synced secret", i, spc.Namespace, secretObj.SecretName))
+	}
+	return warnings
+}
+
+// invalid returns a response that denies the object of the kind with the field errors
+func invalid(kind, name string, errs field.ErrorList, warnings []string) admission.Response {
+	statusErr := apierrors.NewInvalid(schema.GroupKind{Group: secretsstorev1.GroupName, Kind: kind}, name, errs)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		},
+	}.WithWarnings(warnings...)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	secretsstorev1 "sigs.k8s.io/secrets-store-csi-driver/apis/v1"
+	"sigs.k8s.io/secrets-store-csi-driver/controllers"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newValidator(t *testing.T, objs ...runtime.Object) *SecretProviderClassValidator {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	if err := secretsstorev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add to scheme, err: %v", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("failed to create decoder, err: %v", err)
+	}
+	v := NewSecretProviderClassValidator(fake.NewFakeClientWithScheme(scheme, objs...))
+	if err := v.InjectDecoder(decoder); err != nil {
+		t.Fatalf("failed to inject decoder, err: %v", err)
+	}
+	return v
+}
+
+func newSPC(secretObjects ...*secretsstorev1.SecretObject) *secretsstorev1.SecretProviderClass {
+	return &secretsstorev1.SecretProviderClass{
+		TypeMeta:   metav1.TypeMeta{APIVersion: secretsstorev1.GroupVersion.String(), Kind: "SecretProviderClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: "spc1", Namespace: "default"},
+		Spec: secretsstorev1.SecretProviderClassSpec{
+			Provider:      "provider1",
+			SecretObjects: secretObjects,
+		},
+	}
+}
+
+func newRequest(t *testing.T, operation admissionv1.Operation, obj, old *secretsstorev1.SecretProviderClass) admission.Request {
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Name:      obj.Name,
+			Namespace: obj.Namespace,
+		},
+	}
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal spc, err: %v", err)
+	}
+	req.Object = runtime.RawExtension{Raw: raw}
+	if old != nil {
+		raw, err = json.Marshal(old)
+		if err != nil {
+			t.Fatalf("failed to marshal spc, err: %v", err)
+		}
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func TestHandleCreate(t *testing.T) {
+	v := newValidator(t)
+
+	resp := v.Handle(context.TODO(), newRequest(t, admissionv1.Create, newSPC(), nil))
+	if !resp.Allowed {
+		t.Fatalf("expected valid spc to be allowed, got %+v", resp.Result)
+	}
+
+	invalid := newSPC(&secretsstorev1.SecretObject{SecretName: "secret1"})
+	invalid.Spec.Provider = ""
+	resp = v.Handle(context.TODO(), newRequest(t, admissionv1.Create, invalid, nil))
+	if resp.Allowed {
+		t.Fatalf("expected invalid spc to be denied")
+	}
+	if resp.Result == nil || resp.Result.Reason != metav1.StatusReasonInvalid || resp.Result.Details == nil {
+		t.Fatalf("expected invalid status, got %+v", resp.Result)
+	}
+	fields := make(map[string]bool)
+	for _, cause := range resp.Result.Details.Causes {
+		fields[cause.Field] = true
+	}
+	for _, f := range []string{"spec.provider", "spec.secretObjects[0].type"} {
+		if !fields[f] {
+			t.Errorf("expected error for field %s, got %+v", f, resp.Result.Details.Causes)
+		}
+	}
+}
+
+func TestHandleUpdateSyncedSecretWarnings(t *testing.T) {
+	synced := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "synced",
+			Namespace: "default",
+			Labels:    map[string]string{controllers.SecretManagedLabel: "true"},
+		},
+	}
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "default"},
+	}
+	v := newValidator(t, synced, unmanaged)
+
+	secretObject := func(name, key string) *secretsstorev1.SecretObject {
+		return &secretsstorev1.SecretObject{
+			SecretName: name,
+			Type:       "Opaque",
+			Data:       []*secretsstorev1.SecretObjectData{{ObjectName: "obj1", Key: key}},
+		}
+	}
+	old := newSPC(secretObject("synced", "key1"), secretObject("unmanaged", "key1"), secretObject("notsynced", "key1"))
+
+	// no warnings when the data is unchanged
+	resp := v.Handle(context.TODO(), newRequest(t, admissionv1.Update, old, old))
+	if !resp.Allowed || len(resp.Warnings) != 0 {
+		t.Fatalf("expected update to be allowed without warnings, got %+v", resp.AdmissionResponse)
+	}
+
+	// only the secrets synced by the driver are reported
+	updated := newSPC(secretObject("synced", "key2"), secretObject("unmanaged", "key2"), secretObject("notsynced", "key2"))
+	resp = v.Handle(context.TODO(), newRequest(t, admissionv1.Update, updated, old))
+	if !resp.Allowed {
+		t.Fatalf("expected update to be allowed, got %+v", resp.Result)
+	}
+	if len(resp.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", resp.Warnings)
+	}
+}
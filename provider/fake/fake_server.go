@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -29,21 +31,34 @@ import (
 )
 
 type MockCSIProviderServer struct {
-	grpcServer *grpc.Server
-	listener   net.Listener
-	socketPath string
-	returnErr  error
-	errorCode  string
-	objects    []*v1alpha1.ObjectVersion
-	files      []*v1alpha1.File
+	grpcServer     *grpc.Server
+	listener       net.Listener
+	socketPath     string
+	returnErr      error
+	errorCode      string
+	providerErr    *v1alpha1.Error
+	objects        []*v1alpha1.ObjectVersion
+	files          []*v1alpha1.File
+	runtimeVersion string
+	capabilities   []string
+	chunkSize      int
+	watchEvents    chan *v1alpha1.WatchResponse
+	mountDelay     time.Duration
+
+	lock            sync.Mutex
+	mountRequests   []*v1alpha1.MountRequest
+	unmountRequests []*v1alpha1.UnmountRequest
 }
 
 // NewMocKCSIProviderServer returns a mock csi-provider grpc server
 func NewMocKCSIProviderServer(socketPath string) (*MockCSIProviderServer, error) {
 	server := grpc.NewServer()
 	s := &MockCSIProviderServer{
-		grpcServer: server,
-		socketPath: socketPath,
+		grpcServer:     server,
+		socketPath:     socketPath,
+		runtimeVersion: "0.0.10",
+		chunkSize:      64 * 1024,
+		watchEvents:    make(chan *v1alpha1.WatchResponse, 10),
 	}
 	v1alpha1.RegisterCSIDriverProviderServer(server, s)
 	return s, nil
@@ -81,6 +96,51 @@ func (m *MockCSIProviderServer) SetProviderErrorCode(errorCode string) {
 	m.errorCode = errorCode
 }
 
+// SetRuntimeVersion sets the runtime version to return on Version
+func (m *MockCSIProviderServer) SetRuntimeVersion(runtimeVersion string) {
+	m.runtimeVersion = runtimeVersion
+}
+
+// SetCapabilities sets the capabilities to return on Version
+func (m *MockCSIProviderServer) SetCapabilities(capabilities []string) {
+	m.capabilities = capabilities
+}
+
+// SetChunkSize sets the maximum size of the file chunks sent by MountStream
+func (m *MockCSIProviderServer) SetChunkSize(chunkSize int) {
+	m.chunkSize = chunkSize
+}
+
+// SendWatchEvent sends the notification to the open Watch stream
+func (m *MockCSIProviderServer) SendWatchEvent(event *v1alpha1.WatchResponse) {
+	m.watchEvents <- event
+}
+
+// SetMountDelay sets the delay before Mount returns
+func (m *MockCSIProviderServer) SetMountDelay(delay time.Duration) {
+	m.mountDelay = delay
+}
+
+// MountRequests returns the requests received by Mount and MountStream
+func (m *MockCSIProviderServer) MountRequests() []*v1alpha1.MountRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.mountRequests
+}
+
+// UnmountRequests returns the requests received by Unmount
+func (m *MockCSIProviderServer) UnmountRequests() []*v1alpha1.UnmountRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.unmountRequests
+}
+
+// SetProviderError sets the provider error to return, which takes precedence over
+// the provider error code
+func (m *MockCSIProviderServer) SetProviderError(providerErr *v1alpha1.Error) {
+	m.providerErr = providerErr
+}
+
 func (m *MockCSIProviderServer) Start() error {
 	var err error
 	m.listener, err = net.Listen("unix", m.socketPath)
@@ -101,6 +161,17 @@ func (m *MockCSIProviderServer) Mount(ctx context.Context, req *v1alpha1.MountRe
 	var filePermission os.FileMode
 	var err error
 
+	m.lock.Lock()
+	m.mountRequests = append(m.mountRequests, req)
+	m.lock.Unlock()
+	if m.mountDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.mountDelay):
+		}
+	}
+
 	if m.returnErr != nil {
 		return &v1alpha1.MountResponse{}, m.returnErr
 	}
@@ -116,20 +187,86 @@ func (m *MockCSIProviderServer) Mount(ctx context.Context, req *v1alpha1.MountRe
 	if len(req.GetTargetPath()) == 0 {
 		return nil, fmt.Errorf("missing target path")
 	}
+	providerErr := m.providerErr
+	if providerErr == nil {
+		providerErr = &v1alpha1.Error{
+			Code: m.errorCode,
+		}
+	}
 	return &v1alpha1.MountResponse{
 		ObjectVersion: m.objects,
-		Error: &v1alpha1.Error{
-			Code: m.errorCode,
-		},
-		Files: m.files,
+		Error:         providerErr,
+		Files:         m.files,
 	}, nil
 }
 
+// MountStream implements provider csi-provider method
+func (m *MockCSIProviderServer) MountStream(req *v1alpha1.MountRequest, stream v1alpha1.CSIDriverProvider_MountStreamServer) error {
+	resp, err := m.Mount(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for _, file := range resp.GetFiles() {
+		contents := file.GetContents()
+		for {
+			n := len(contents)
+			if n > m.chunkSize {
+				n = m.chunkSize
+			}
+			chunk := &v1alpha1.FileChunk{Path: file.GetPath(), Mode: file.GetMode(), Contents: contents[:n]}
+			if err := stream.Send(&v1alpha1.MountStreamResponse{Chunk: chunk}); err != nil {
+				return err
+			}
+			if contents = contents[n:]; len(contents) == 0 {
+				break
+			}
+		}
+	}
+	return stream.Send(&v1alpha1.MountStreamResponse{
+		ObjectVersion: resp.GetObjectVersion(),
+		Error:         resp.GetError(),
+	})
+}
+
 // Version implements provider csi-provider method
 func (m *MockCSIProviderServer) Version(ctx context.Context, req *v1alpha1.VersionRequest) (*v1alpha1.VersionResponse, error) {
 	return &v1alpha1.VersionResponse{
 		Version:        "v1alpha1",
 		RuntimeName:    "fakeprovider",
-		RuntimeVersion: "0.0.10",
+		RuntimeVersion: m.runtimeVersion,
+		Capabilities:   m.capabilities,
+	}, nil
+}
+
+// Unmount implements provider csi-provider method
+func (m *MockCSIProviderServer) Unmount(ctx context.Context, req *v1alpha1.UnmountRequest) (*v1alpha1.UnmountResponse, error) {
+	m.lock.Lock()
+	m.unmountRequests = append(m.unmountRequests, req)
+	m.lock.Unlock()
+
+	if m.returnErr != nil {
+		return &v1alpha1.UnmountResponse{}, m.returnErr
+	}
+	return &v1alpha1.UnmountResponse{
+		Error: &v1alpha1.Error{
+			Code: m.errorCode,
+		},
 	}, nil
 }
+
+// Watch implements provider csi-provider method
+func (m *MockCSIProviderServer) Watch(req *v1alpha1.WatchRequest, stream v1alpha1.CSIDriverProvider_WatchServer) error {
+	if m.returnErr != nil {
+		return m.returnErr
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-m.watchEvents:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
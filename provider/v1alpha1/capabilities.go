@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// The capabilities a provider can declare in the VersionResponse. The driver
+// only calls the optional RPCs of the providers that declare the capability.
+const (
+	// CapabilityUnmount is declared by providers that implement the Unmount RPC
+	CapabilityUnmount = "Unmount"
+	// CapabilityMountStream is declared by providers that implement the MountStream RPC
+	CapabilityMountStream = "MountStream"
+	// CapabilityWatch is declared by providers that implement the Watch RPC
+	CapabilityWatch = "Watch"
+	// CapabilityMountCoalescing is declared by providers whose mount responses don't depend on
+	// the pod, so the concurrent identical mount requests of the pods of a service account can
+	// be served by a single Mount call. It's ignored for providers with the Unmount capability,
+	// as they issue the credentials for each pod.
+	CapabilityMountCoalescing = "MountCoalescing"
+)
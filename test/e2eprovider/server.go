@@ -27,6 +27,10 @@ type KubernetesTokenContent struct {
 }
 
 type SimpleCSIProviderServer struct {
+	// the e2e provider doesn't declare any capabilities, so the optional
+	// RPCs aren't called by the driver
+	v1alpha1.UnimplementedCSIDriverProviderServer
+
 	grpcServer *grpc.Server
 	listener   net.Listener
 	socketPath string
@@ -36,7 +36,7 @@ const (
 func TestSanity(t *testing.T) {
 	driver := secretsstore.GetDriver()
 	go func() {
-		driver.Run(context.Background(), "secrets-store.csi.k8s.io", "somenodeid", endpoint, providerVolumePath, nil, nil)
+		driver.Run(context.Background(), "secrets-store.csi.k8s.io", "somenodeid", endpoint, providerVolumePath, nil, nil, nil, nil)
 	}()
 
 	tmpPath := filepath.Join(os.TempDir(), "csi")